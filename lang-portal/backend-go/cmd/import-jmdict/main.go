@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"lang-portal/config"
+	"lang-portal/internal/database"
+	"lang-portal/internal/dictionary"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// import-jmdict loads the dictionary teachers look words up in from a local JMdict file,
+// replacing the previous import
+func main() {
+	file := flag.String("file", "JMdict_e.gz", "JMdict file, optionally gzipped")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	r, err := dictionary.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to read JMdict: %v", err)
+	}
+	defer r.Close()
+
+	// Create database connection
+	db, err := database.CreateDatabase(database.DatabaseConfig{Path: cfg.DatabasePath})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	// Entries are stored as they are parsed; an invalid file rolls the import back
+	dictionaryRepo := repository.NewDictionaryRepository(db.DB)
+	imported, err := dictionaryRepo.Import(ctx, func(add func(*models.DictionaryEntry) error) error {
+		return dictionary.ReadJMdict(r, add)
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	log.Printf("Imported %d dictionary entries", imported)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"lang-portal/config"
+	"lang-portal/internal/database"
+	"lang-portal/internal/dictionary"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// import-kanjidic loads the kanji dictionary from a local KANJIDIC2 file, replacing the
+// previous import, and links every word to the kanji of its parts
+func main() {
+	file := flag.String("file", "kanjidic2.xml.gz", "KANJIDIC2 file, optionally gzipped")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Parse the dictionary before touching the database
+	r, err := dictionary.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to read KANJIDIC2: %v", err)
+	}
+	defer r.Close()
+	var entries []models.Kanji
+	err = dictionary.ReadKANJIDIC2(r, func(k *models.Kanji) error {
+		entries = append(entries, *k)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to read KANJIDIC2: %v", err)
+	}
+
+	// Create database connection
+	db, err := database.CreateDatabase(database.DatabaseConfig{Path: cfg.DatabasePath})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	kanjiRepo := repository.NewKanjiRepository(db.DB)
+	imported, err := kanjiRepo.Import(ctx, entries)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+	links, err := kanjiRepo.LinkWords(ctx)
+	if err != nil {
+		log.Fatalf("Linking words failed: %v", err)
+	}
+
+	log.Printf("Imported %d kanji, words use kanji %d time(s)", imported, links)
+}
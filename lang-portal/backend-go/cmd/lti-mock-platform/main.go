@@ -0,0 +1,263 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"lang-portal/internal/lti"
+)
+
+// lti-mock-platform is a minimal LTI 1.3 platform for trying the tool locally.
+// It signs launches with a throwaway key, answers the client credentials grant
+// after checking the tool's assertion against its JWKS, and logs published scores.
+//
+// Start a launch by opening
+//
+//	http://localhost:9090/start?user=alice&context=course-1&activity=1
+//
+// optionally adding &group=<id> to launch a specific group.
+func main() {
+	addr := flag.String("addr", "localhost:9090", "address to listen on")
+	toolURL := flag.String("tool", "http://localhost:8080", "public base URL of the lang portal")
+	clientID := flag.String("client-id", "lang-portal", "client id of the tool registration")
+	deploymentID := flag.String("deployment-id", "1", "deployment id of the tool registration")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("Failed to generate platform key: %v", err)
+	}
+
+	p := &platform{
+		issuer:       "http://" + *addr,
+		toolURL:      strings.TrimRight(*toolURL, "/"),
+		clientID:     *clientID,
+		deploymentID: *deploymentID,
+		key:          key,
+	}
+
+	http.HandleFunc("/start", p.start)
+	http.HandleFunc("/auth", p.auth)
+	http.HandleFunc("/token", p.token)
+	http.HandleFunc("/jwks", p.jwks)
+	http.HandleFunc("/lineitems/", p.scores)
+
+	registration, _ := json.MarshalIndent(map[string]string{
+		"name":           "Mock platform",
+		"issuer":         p.issuer,
+		"client_id":      p.clientID,
+		"deployment_id":  p.deploymentID,
+		"auth_login_url": p.issuer + "/auth",
+		"auth_token_url": p.issuer + "/token",
+		"jwks_url":       p.issuer + "/jwks",
+	}, "", "  ")
+	log.Printf("Register this platform with POST %s/api/v1/lti/platforms:\n%s", p.toolURL, registration)
+	log.Printf("Mock platform listening on %s", p.issuer)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+// platform holds the mock registration of the tool
+type platform struct {
+	issuer       string
+	toolURL      string
+	clientID     string
+	deploymentID string
+	key          *rsa.PrivateKey
+
+	mu       sync.Mutex
+	received []json.RawMessage
+}
+
+// launchHint carries the launch parameters through the OIDC login as lti_message_hint
+type launchHint struct {
+	Context  string `json:"context"`
+	Activity string `json:"activity"`
+	Group    string `json:"group,omitempty"`
+}
+
+// start plays the LMS user clicking a link: it initiates the OIDC login with the tool
+func (p *platform) start(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	user := query.Get("user")
+	if user == "" {
+		user = "learner"
+	}
+	hint, _ := json.Marshal(launchHint{
+		Context:  query.Get("context"),
+		Activity: query.Get("activity"),
+		Group:    query.Get("group"),
+	})
+
+	login := url.Values{
+		"iss":               {p.issuer},
+		"login_hint":        {user},
+		"target_link_uri":   {p.toolURL + "/lti/launch"},
+		"lti_message_hint":  {string(hint)},
+		"client_id":         {p.clientID},
+		"lti_deployment_id": {p.deploymentID},
+	}
+	http.Redirect(w, r, p.toolURL+"/lti/login?"+login.Encode(), http.StatusFound)
+}
+
+// autoPost is the page posting the id token to the tool, as a real platform does
+var autoPost = template.Must(template.New("post").Parse(`<!DOCTYPE html>
+<html><body onload="document.forms[0].submit()">
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="id_token" value="{{.IDToken}}">
+<input type="hidden" name="state" value="{{.State}}">
+<noscript><button type="submit">Continue</button></noscript>
+</form>
+</body></html>
+`))
+
+// auth answers the tool's authorization request with a signed launch
+func (p *platform) auth(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != p.clientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+
+	var hint launchHint
+	if err := json.Unmarshal([]byte(query.Get("lti_message_hint")), &hint); err != nil {
+		http.Error(w, "invalid lti_message_hint", http.StatusBadRequest)
+		return
+	}
+	user := query.Get("login_hint")
+	custom := map[string]string{"study_activity_id": hint.Activity}
+	if hint.Group != "" {
+		custom["group_id"] = hint.Group
+	}
+
+	now := time.Now()
+	claims := &lti.LaunchClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.issuer,
+			Subject:   "user-" + user,
+			Audience:  jwt.ClaimStrings{p.clientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		},
+		Nonce:         query.Get("nonce"),
+		Name:          user,
+		Email:         user + "@example.com",
+		MessageType:   lti.MessageTypeResourceLink,
+		Version:       lti.Version,
+		DeploymentID:  p.deploymentID,
+		TargetLinkURI: p.toolURL + "/lti/launch",
+		ResourceLink:  lti.ResourceLink{ID: "link-" + hint.Activity, Title: "Study activity " + hint.Activity},
+		Roles:         []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Learner"},
+		Custom:        custom,
+		Endpoint: &lti.Endpoint{
+			Scope:    []string{lti.ScopeScore},
+			LineItem: p.issuer + "/lineitems/" + hint.Activity,
+		},
+	}
+	if hint.Context != "" {
+		claims.Context = &lti.Context{ID: hint.Context, Title: "Course " + hint.Context}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "mock"
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	autoPost.Execute(w, map[string]string{
+		"Action":  query.Get("redirect_uri"),
+		"IDToken": idToken,
+		"State":   query.Get("state"),
+	})
+}
+
+// token implements the client credentials grant with a JWT client assertion
+func (p *platform) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+		http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+		return
+	}
+
+	set, err := lti.FetchJWKS(r.Context(), http.DefaultClient, p.toolURL+"/lti/jwks")
+	if err != nil {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	_, err = jwt.Parse(r.PostForm.Get("client_assertion"),
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return set.Key(kid)
+		},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(p.clientID),
+		jwt.WithSubject(p.clientID),
+		jwt.WithAudience(p.issuer+"/token"),
+	)
+	if err != nil {
+		log.Printf("Rejected client assertion: %v", err)
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"scope":        r.PostForm.Get("scope"),
+	})
+}
+
+// jwks publishes the platform key
+func (p *platform) jwks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lti.JWKS{Keys: []lti.JWK{lti.NewJWK("mock", &p.key.PublicKey)}})
+}
+
+// scores receives score publications (POST) and lists the received ones (GET)
+func (p *platform) scores(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/scores") {
+		http.NotFound(w, r)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(p.received)
+	case http.MethodPost:
+		if r.Header.Get("Authorization") != "Bearer mock-access-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil || !json.Valid(body) {
+			http.Error(w, "invalid score", http.StatusBadRequest)
+			return
+		}
+		log.Printf("Score received for %s: %s", r.URL.Path, body)
+		p.received = append(p.received, body)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", fmt.Sprintf("%s, %s", http.MethodGet, http.MethodPost))
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -11,9 +12,15 @@ import (
 
 	"lang-portal/config"
 	"lang-portal/internal/database"
+	"lang-portal/internal/repository"
 )
 
 func main() {
+	// Paths are relative to the working directory, the backend module when run with go run
+	migrationDir := flag.String("migrations", "migrations", "directory of the SQL migrations")
+	seedDir := flag.String("seed", "seed", "directory of the JSON seed files")
+	flag.Parse()
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -35,22 +42,25 @@ func main() {
 	defer db.Close()
 
 	// Run migrations
-	if err := runMigrations(db.DB); err != nil {
+	if err := runMigrations(db.DB, *migrationDir); err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
 
 	// Seed database
-	if err := database.SeedDatabase(db.DB); err != nil {
+	if err := database.SeedDatabase(db.DB, *seedDir); err != nil {
 		log.Fatalf("Seeding failed: %v", err)
 	}
 
+	// Link the seeded words to their kanji, as the seeder inserts them directly
+	if _, err := repository.NewKanjiRepository(db.DB).LinkWords(context.Background()); err != nil {
+		log.Fatalf("Linking words failed: %v", err)
+	}
+
 	log.Println("Migrations and seeding completed successfully")
 }
 
-func runMigrations(db *sql.DB) error {
-	//TODO add absolute path
-	migrationDir := filepath.Join("c:\\Users\\nassima\\Desktop\\gen-ai-bootcamp-2025\\lang-portal\\backend-go", "migrations")
-
+// runMigrations applies the SQL files of migrationDir that were not applied yet, in order
+func runMigrations(db *sql.DB, migrationDir string) error {
 	// Read migration files
 	files, err := os.ReadDir(migrationDir)
 	if err != nil {
@@ -67,9 +77,32 @@ func runMigrations(db *sql.DB) error {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
+	// Track applied migrations so non-idempotent statements only run once
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			name TEXT PRIMARY KEY,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
 	// Execute each migration file
 	for _, file := range files {
 		if filepath.Ext(file.Name()) == ".sql" {
+			// Skip migrations that were already applied
+			var applied int
+			err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations WHERE name = ?`, file.Name()).Scan(&applied)
+			if err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to check migration %s: %w", file.Name(), err)
+			}
+			if applied > 0 {
+				continue
+			}
+
 			migrationPath := filepath.Join(migrationDir, file.Name())
 
 			// Read migration file
@@ -86,6 +119,13 @@ func runMigrations(db *sql.DB) error {
 				return fmt.Errorf("failed to execute migration %s: %w", file.Name(), err)
 			}
 
+			// Record migration
+			_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (name) VALUES (?)`, file.Name())
+			if err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to record migration %s: %w", file.Name(), err)
+			}
+
 			log.Printf("Applied migration: %s", file.Name())
 		}
 	}
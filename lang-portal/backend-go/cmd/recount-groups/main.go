@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lang-portal/config"
+	"lang-portal/internal/database"
+	"lang-portal/internal/repository"
+)
+
+// recount-groups repairs groups.words_count from the word_groups join table
+func main() {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create database connection
+	db, err := database.CreateDatabase(database.DatabaseConfig{Path: cfg.DatabasePath})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Recompute word counts
+	groupRepo := repository.NewGroupRepository(db.DB)
+	corrected, err := groupRepo.RecountWords(ctx)
+	if err != nil {
+		log.Fatalf("Recount failed: %v", err)
+	}
+
+	log.Printf("Recounted group words, %d group(s) corrected", corrected)
+}
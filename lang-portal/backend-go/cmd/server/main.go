@@ -1,13 +1,38 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata" // timezones work without a system zoneinfo database, e.g. on Windows
 
 	"lang-portal/config"
+	"lang-portal/internal/achievement"
+	"lang-portal/internal/content"
 	"lang-portal/internal/database"
+	"lang-portal/internal/events"
+	"lang-portal/internal/goals"
 	"lang-portal/internal/handlers"
+	"lang-portal/internal/launch"
+	"lang-portal/internal/leaderboard"
+	"lang-portal/internal/leech"
+	"lang-portal/internal/llm"
+	"lang-portal/internal/lti"
+	"lang-portal/internal/media"
+	"lang-portal/internal/quiz"
 	"lang-portal/internal/repository"
 	"lang-portal/internal/routes"
+	"lang-portal/internal/server"
+	"lang-portal/internal/streak"
+	"lang-portal/internal/stream"
+	"lang-portal/internal/tts"
+	"lang-portal/internal/vocab"
+	"lang-portal/internal/webhook"
+	"lang-portal/internal/xapi"
 )
 
 func main() {
@@ -29,19 +54,162 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
+
+	// Events raised by repository writes
+	eventBus := events.NewBus()
 
 	// Create repositories
 	groupRepo := repository.NewGroupRepository(db.DB)
+	wordRepo := repository.NewWordRepository(db.DB, eventBus)
 	studyActivityRepo := repository.NewStudyActivityRepository(db.DB)
-	studySessionRepo := repository.NewStudySessionRepository(db.DB)
-	dashboardRepo := repository.NewDashboardRepository(db.DB)
+	studySessionRepo := repository.NewStudySessionRepository(db.DB, eventBus)
+	streakService := streak.NewService(repository.NewStreakRepository(db.DB), cfg.Timezone, streak.DefaultRules)
+	goalRepo := repository.NewGoalRepository(db.DB)
+	goalService := goals.NewService(goalRepo, cfg.Timezone)
+	dashboardRepo := repository.NewDashboardRepository(db.DB, streakService, goalService)
+	xapiIRIs := xapi.NewIRIs(cfg.PublicBaseURL)
+	xapiRepo := repository.NewXAPIRepository(db.DB, xapiIRIs, eventBus)
+	ltiRepo := repository.NewLTIRepository(db.DB, eventBus)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	leechRepo := repository.NewLeechRepository(db.DB)
+	masteryRepo := repository.NewMasteryRepository(db.DB)
+	achievementRepo := repository.NewAchievementRepository(db.DB)
+	leaderboardRepo := repository.NewLeaderboardRepository(db.DB)
+	quizRepo := repository.NewQuizRepository(db.DB, eventBus)
+	wordContentRepo := repository.NewWordContentRepository(db.DB)
+	mediaRepo := repository.NewMediaRepository(db.DB)
+	kanjiRepo := repository.NewKanjiRepository(db.DB)
+	dictionaryRepo := repository.NewDictionaryRepository(db.DB)
+
+	// Background workers run until shutdown, after the HTTP server has drained
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	startWorker := func(run func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
+	}
+
+	// Deliver events to webhook subscriptions in the background
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, cfg.WebhookMaxAttempts, cfg.WebhookRetryBase)
+	eventBus.Subscribe(webhookDispatcher.Enqueue)
+	startWorker(webhookDispatcher.Run)
+
+	// Push dashboard updates to stream clients
+	dashboardFeed := stream.NewDashboardFeed(dashboardRepo, stream.NewHub(cfg.StreamMaxClients), 250*time.Millisecond)
+	eventBus.Subscribe(dashboardFeed.HandleEvent)
+	startWorker(dashboardFeed.Run)
+
+	// Collect leeches in the "Needs work" group as they appear
+	if cfg.LeechAutoGroup {
+		eventBus.Subscribe(leech.NewDetector(leechRepo, cfg.LeechThreshold).HandleEvent)
+	}
+
+	// Award badges from past history, then as sessions and reviews are written
+	achievementEngine := achievement.NewEngine(achievementRepo, eventBus, cfg.Timezone, streak.DefaultRules, 250*time.Millisecond)
+	eventBus.Subscribe(achievementEngine.HandleEvent)
+	startWorker(achievementEngine.Run)
+
+	// Host live quiz rooms
+	quizManager := quiz.NewManager(groupRepo, studyActivityRepo, studySessionRepo, cfg.QuizMaxRooms)
+
+	// Seal the answer keys of generated quizzes
+	if cfg.QuizSecret == "" {
+		log.Println("LANGPORTAL_QUIZ_SECRET is not set, generated quizzes cannot be scored after a restart")
+	}
+	quizKeys, err := quiz.NewAnswerKeys(cfg.QuizSecret, cfg.QuizAnswerKeyTTL)
+	if err != nil {
+		log.Fatalf("Failed to create quiz answer keys: %v", err)
+	}
+
+	// Language model generating word content
+	llmProvider, err := llm.New(llm.Config{
+		Provider: cfg.LLMProvider,
+		BaseURL:  cfg.LLMBaseURL,
+		Model:    cfg.LLMModel,
+		APIKey:   cfg.LLMAPIKey,
+		Timeout:  cfg.LLMTimeout,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create language model provider: %v", err)
+	}
+
+	// Link words created before kanji linking, such as the seeded ones, to their kanji
+	if _, err := kanjiRepo.LinkWords(context.Background()); err != nil {
+		log.Printf("Failed to link words to kanji: %v", err)
+	}
+
+	// Store pronunciation audio, uploaded or spoken by a text-to-speech service, and images
+	synthesizer, err := tts.New(tts.Config{
+		Provider: cfg.TTSProvider,
+		URL:      cfg.TTSURL,
+		Voice:    cfg.TTSVoice,
+		Timeout:  cfg.TTSTimeout,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create text-to-speech provider: %v", err)
+	}
+	mediaStore, err := media.NewStore(cfg.MediaDir)
+	if err != nil {
+		log.Fatalf("Failed to create media store: %v", err)
+	}
+	mediaService := media.NewService(mediaStore, mediaRepo, wordRepo, synthesizer, media.Config{
+		AudioMaxBytes: int64(cfg.AudioMaxBytes),
+		ImageMaxBytes: int64(cfg.ImageMaxBytes),
+		ThumbnailSize: cfg.ThumbnailSize,
+	})
+	// Drop the media of words deleted since the last start
+	if removed, err := mediaService.Prune(context.Background()); err != nil {
+		log.Printf("Failed to remove unused media: %v", err)
+	} else if removed > 0 {
+		log.Printf("Removed %d unused media files", removed)
+	}
+
+	// Create launch token signer
+	if cfg.LaunchSecret == "" {
+		log.Println("LANGPORTAL_LAUNCH_SECRET is not set, launch tokens will not survive a restart")
+	}
+	launchSigner, err := launch.NewSigner(cfg.LaunchSecret, cfg.LaunchTokenTTL)
+	if err != nil {
+		log.Fatalf("Failed to create launch signer: %v", err)
+	}
+
+	// Load the key the LTI tool signs its messages with
+	if cfg.LTIKeyPath == "" {
+		log.Println("LANGPORTAL_LTI_KEY_PATH is not set, platforms must refetch the LTI JWKS after a restart")
+	}
+	ltiKey, err := lti.LoadToolKey(cfg.LTIKeyPath)
+	if err != nil {
+		log.Fatalf("Failed to load LTI key: %v", err)
+	}
 
 	// Create handlers
 	groupHandler := handlers.NewGroupHandler(groupRepo)
 	studyActivityHandler := handlers.NewStudyActivityHandler(studyActivityRepo)
 	studySessionHandler := handlers.NewStudySessionHandler(studySessionRepo)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardRepo)
+	streakHandler := handlers.NewStreakHandler(streakService)
+	leechHandler := handlers.NewLeechHandler(leechRepo, cfg.LeechThreshold)
+	calendarHandler := handlers.NewCalendarHandler(dashboardRepo, studySessionRepo, cfg.Timezone)
+	masteryHandler := handlers.NewMasteryHandler(masteryRepo)
+	goalHandler := handlers.NewGoalHandler(goalRepo, goalService)
+	achievementHandler := handlers.NewAchievementHandler(achievementEngine)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardRepo, leaderboard.NewService(leaderboardRepo, cfg.Timezone, cfg.LeaderboardCacheTTL))
+	wordContentHandler := handlers.NewWordContentHandler(content.NewService(wordRepo, wordContentRepo, llmProvider))
+	vocabHandler := handlers.NewVocabHandler(vocab.NewGenerator(llmProvider, wordRepo, groupRepo))
+	mediaHandler := handlers.NewMediaHandler(mediaService)
+	assetHandler := handlers.NewAssetHandler(cfg.AssetsDir)
+	kanjiHandler := handlers.NewKanjiHandler(kanjiRepo)
+	dictionaryHandler := handlers.NewDictionaryHandler(dictionaryRepo, vocab.NewDrafter(dictionaryRepo, kanjiRepo, wordRepo))
+	launchHandler := handlers.NewLaunchHandler(studyActivityRepo, groupRepo, studySessionRepo, launchSigner, cfg.PublicBaseURL)
+	xapiHandler := handlers.NewXAPIHandler(xapiRepo, xapiIRIs)
+	wordHandler := handlers.NewWordHandler(wordRepo)
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo, webhookDispatcher)
+	dashboardStreamHandler := handlers.NewDashboardStreamHandler(dashboardFeed)
+	quizHandler := handlers.NewQuizHandler(quizManager, quiz.NewGenerator(groupRepo, quizRepo, quizKeys))
+	ltiHandler := handlers.NewLTIHandler(ltiRepo, studyActivityRepo, groupRepo, launchSigner, ltiKey, cfg.PublicBaseURL)
 
 	// Setup routes
 	router := routes.SetupRoutes(
@@ -49,10 +217,105 @@ func main() {
 		studyActivityHandler,
 		studySessionHandler,
 		dashboardHandler,
+		launchHandler,
+		launchSigner,
+		xapiHandler,
+		ltiHandler,
+		wordHandler,
+		webhookHandler,
+		dashboardStreamHandler,
+		quizHandler,
+		streakHandler,
+		leechHandler,
+		calendarHandler,
+		masteryHandler,
+		goalHandler,
+		achievementHandler,
+		leaderboardHandler,
+		wordContentHandler,
+		vocabHandler,
+		mediaHandler,
+		assetHandler,
+		kanjiHandler,
+		dictionaryHandler,
 	)
 
-	// Run server
-	if err := routes.RunServer(router, cfg.ServerPort); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// Create HTTP server
+	srv, err := server.New(router, server.Config{
+		Addr:              cfg.ListenAddress(),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		CertFile:          cfg.TLSCertFile,
+		KeyFile:           cfg.TLSKeyFile,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	// Dashboard streams never end on their own, so end them when the drain starts
+	srv.RegisterOnShutdown(dashboardFeed.Hub().Close)
+
+	// Run server until SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	// SIGHUP reloads the TLS certificate, e.g. after a renewal
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	running := true
+	for running {
+		select {
+		case err := <-serveErr:
+			log.Fatalf("Failed to start server: %v", err)
+		case <-reload:
+			if err := srv.ReloadCertificate(); err != nil {
+				log.Printf("Failed to reload TLS certificate: %v", err)
+			} else {
+				log.Println("Reloaded TLS certificate")
+			}
+		case <-ctx.Done():
+			running = false
+		}
+	}
+	stop()
+
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// 1. Stop accepting connections and let in-flight requests finish
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to drain HTTP server: %v", err)
+	}
+
+	// 2. End quiz rooms, which completes the players' study sessions
+	if err := quizManager.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to close quiz rooms: %v", err)
+	}
+
+	// 3. Stop background workers once nothing raises events anymore
+	stopWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		log.Println("Background workers did not stop in time")
+	}
+
+	// 4. Close the database last
+	if err := db.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
 	}
+	log.Println("Server stopped")
 }
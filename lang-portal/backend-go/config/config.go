@@ -1,15 +1,226 @@
 package config
 
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type Config struct {
 	DatabasePath string
 	ServerPort   string
-	// Add other configuration fields as needed
+	// BindAddress is the host or IP the server listens on, e.g. 0.0.0.0 inside a container
+	BindAddress string
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout bound slow or idle clients
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout is how long a shutdown waits for requests and background work to finish
+	ShutdownTimeout time.Duration
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set; renewed files are picked up without a restart
+	TLSCertFile string
+	TLSKeyFile  string
+	// PublicBaseURL is the externally reachable address of this server, used in launch callbacks
+	PublicBaseURL string
+	// LaunchSecret signs launch tokens handed to study activities
+	LaunchSecret string
+	// LaunchTokenTTL is how long a launch token stays valid
+	LaunchTokenTTL time.Duration
+	// LTIKeyPath points to the PEM encoded RSA private key the tool signs LTI messages with
+	LTIKeyPath string
+	// WebhookMaxAttempts is how many times a webhook delivery is tried before it is marked failed
+	WebhookMaxAttempts int
+	// WebhookRetryBase is the wait before the first retry, doubled after every further failure
+	WebhookRetryBase time.Duration
+	// StreamMaxClients limits concurrent dashboard stream connections
+	StreamMaxClients int
+	// Timezone is the IANA timezone study days are counted in, e.g. for streaks
+	Timezone *time.Location
+	// LeechThreshold is the number of wrong answers that makes a word a leech
+	LeechThreshold int
+	// LeechAutoGroup adds words to the "Needs work" group as soon as they become leeches
+	LeechAutoGroup bool
+	// QuizMaxRooms limits how many live quiz rooms can be open at once
+	QuizMaxRooms int
+	// QuizSecret seals the answer keys of generated quizzes
+	QuizSecret string
+	// QuizAnswerKeyTTL is how long a generated quiz can be scored
+	QuizAnswerKeyTTL time.Duration
+	// LLMProvider selects the language model generating word content: "stub" or "openai"
+	LLMProvider string
+	// LLMBaseURL is the address of an OpenAI-compatible API such as Ollama
+	LLMBaseURL string
+	// LLMModel is the model asked for completions
+	LLMModel string
+	// LLMAPIKey is sent as a bearer token when set
+	LLMAPIKey string
+	// LLMTimeout bounds a single completion request
+	LLMTimeout time.Duration
+	// LeaderboardCacheTTL is how long leaderboard statistics are reused before history is scanned again
+	LeaderboardCacheTTL time.Duration
+	// MediaDir is the directory uploaded and generated media files are stored in
+	MediaDir string
+	// AudioMaxBytes limits the size of uploaded pronunciation audio
+	AudioMaxBytes int
+	// ImageMaxBytes limits the size of uploaded images
+	ImageMaxBytes int
+	// ThumbnailSize is the side of the square image thumbnails are scaled to fit, in pixels
+	ThumbnailSize int
+	// AssetsDir is the directory static assets are served from under /assets
+	AssetsDir string
+	// TTSProvider selects the text-to-speech service pronouncing words: "stub" or "opea"
+	TTSProvider string
+	// TTSURL is the address of an OPEA text-to-speech service
+	TTSURL string
+	// TTSVoice is the voice asked for, when the service offers several
+	TTSVoice string
+	// TTSTimeout bounds a single speech request
+	TTSTimeout time.Duration
 }
 
+// LoadConfig reads configuration from LANGPORTAL_* environment variables, falling back to defaults
 func LoadConfig() (*Config, error) {
-	// TODO: Implement configuration loading
-	return &Config{
-		DatabasePath: "langportal.db",
-		ServerPort:   ":8080",
-	}, nil
+	cfg := &Config{
+		DatabasePath:  getEnv("LANGPORTAL_DB_PATH", "langportal.db"),
+		ServerPort:    getEnv("LANGPORTAL_PORT", ":8080"),
+		BindAddress:   getEnv("LANGPORTAL_BIND_ADDRESS", "localhost"),
+		TLSCertFile:   os.Getenv("LANGPORTAL_TLS_CERT_FILE"),
+		TLSKeyFile:    os.Getenv("LANGPORTAL_TLS_KEY_FILE"),
+		PublicBaseURL: getEnv("LANGPORTAL_PUBLIC_URL", "http://localhost:8080"),
+		LaunchSecret:  os.Getenv("LANGPORTAL_LAUNCH_SECRET"),
+		LTIKeyPath:    os.Getenv("LANGPORTAL_LTI_KEY_PATH"),
+		QuizSecret:    os.Getenv("LANGPORTAL_QUIZ_SECRET"),
+		LLMProvider:   getEnv("LANGPORTAL_LLM_PROVIDER", "stub"),
+		LLMBaseURL:    getEnv("LANGPORTAL_LLM_BASE_URL", "http://localhost:11434"),
+		LLMModel:      getEnv("LANGPORTAL_LLM_MODEL", "llama3.2:1b"),
+		LLMAPIKey:     os.Getenv("LANGPORTAL_LLM_API_KEY"),
+		MediaDir:      getEnv("LANGPORTAL_MEDIA_DIR", "media"),
+		AssetsDir:     getEnv("LANGPORTAL_ASSETS_DIR", "assets"),
+		TTSProvider:   getEnv("LANGPORTAL_TTS_PROVIDER", "stub"),
+		TTSURL:        getEnv("LANGPORTAL_TTS_URL", "http://localhost:9088"),
+		TTSVoice:      os.Getenv("LANGPORTAL_TTS_VOICE"),
+	}
+
+	var err error
+	if cfg.ReadHeaderTimeout, err = getEnvDuration("LANGPORTAL_READ_HEADER_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.ReadTimeout, err = getEnvDuration("LANGPORTAL_READ_TIMEOUT", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.WriteTimeout, err = getEnvDuration("LANGPORTAL_WRITE_TIMEOUT", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.IdleTimeout, err = getEnvDuration("LANGPORTAL_IDLE_TIMEOUT", 2*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.ShutdownTimeout, err = getEnvDuration("LANGPORTAL_SHUTDOWN_TIMEOUT", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.LeechThreshold, err = getEnvInt("LANGPORTAL_LEECH_THRESHOLD", 8); err != nil {
+		return nil, err
+	}
+	if cfg.LeechAutoGroup, err = getEnvBool("LANGPORTAL_LEECH_AUTO_GROUP", false); err != nil {
+		return nil, err
+	}
+	if cfg.Timezone, err = time.LoadLocation(getEnv("LANGPORTAL_TIMEZONE", "UTC")); err != nil {
+		return nil, fmt.Errorf("invalid LANGPORTAL_TIMEZONE: %w", err)
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, fmt.Errorf("LANGPORTAL_TLS_CERT_FILE and LANGPORTAL_TLS_KEY_FILE must be set together")
+	}
+	if cfg.LaunchTokenTTL, err = getEnvDuration("LANGPORTAL_LAUNCH_TOKEN_TTL", 2*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookMaxAttempts, err = getEnvInt("LANGPORTAL_WEBHOOK_MAX_ATTEMPTS", 8); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookRetryBase, err = getEnvDuration("LANGPORTAL_WEBHOOK_RETRY_BASE", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.StreamMaxClients, err = getEnvInt("LANGPORTAL_STREAM_MAX_CLIENTS", 100); err != nil {
+		return nil, err
+	}
+	if cfg.QuizMaxRooms, err = getEnvInt("LANGPORTAL_QUIZ_MAX_ROOMS", 50); err != nil {
+		return nil, err
+	}
+	if cfg.QuizAnswerKeyTTL, err = getEnvDuration("LANGPORTAL_QUIZ_ANSWER_KEY_TTL", 2*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.LLMTimeout, err = getEnvDuration("LANGPORTAL_LLM_TIMEOUT", 60*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.LeaderboardCacheTTL, err = getEnvDuration("LANGPORTAL_LEADERBOARD_CACHE_TTL", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.AudioMaxBytes, err = getEnvInt("LANGPORTAL_AUDIO_MAX_BYTES", 10<<20); err != nil {
+		return nil, err
+	}
+	if cfg.ImageMaxBytes, err = getEnvInt("LANGPORTAL_IMAGE_MAX_BYTES", 5<<20); err != nil {
+		return nil, err
+	}
+	if cfg.ThumbnailSize, err = getEnvInt("LANGPORTAL_THUMBNAIL_SIZE", 256); err != nil {
+		return nil, err
+	}
+	if cfg.TTSTimeout, err = getEnvDuration("LANGPORTAL_TTS_TIMEOUT", 30*time.Second); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// ListenAddress combines the bind address and port into a host:port address
+func (c *Config) ListenAddress() string {
+	return net.JoinHostPort(c.BindAddress, strings.TrimPrefix(c.ServerPort, ":"))
+}
+
+// getEnv returns the value of an environment variable or a default
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvDuration parses a duration environment variable such as "90s" or "2h"
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
+
+// getEnvInt parses a positive integer environment variable
+func getEnvInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s: must be a positive integer", key)
+	}
+	return n, nil
+}
+
+// getEnvBool parses a boolean environment variable such as "true" or "0"
+func getEnvBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: must be a boolean", key)
+	}
+	return b, nil
 }
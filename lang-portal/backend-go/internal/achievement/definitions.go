@@ -0,0 +1,76 @@
+package achievement
+
+// Rule kinds, each counting something in the study history
+const (
+	// KindReviews counts recorded reviews
+	KindReviews = "reviews"
+	// KindSessions counts started study sessions
+	KindSessions = "sessions"
+	// KindPerfectSessions counts completed sessions of at least MinReviews reviews, all correct
+	KindPerfectSessions = "perfect_sessions"
+	// KindWordsMastered counts the words mastered at the same time, of Group when it is set
+	KindWordsMastered = "words_mastered"
+	// KindStreak counts the days of the study streak
+	KindStreak = "streak"
+)
+
+// Rule is met once what its kind counts reaches Target
+type Rule struct {
+	Kind   string
+	Target int
+	// Group limits words_mastered to the words of the group with this slug, which unlike
+	// its name stays the same when the group is renamed
+	Group string
+	// MinReviews is the shortest session perfect_sessions counts
+	MinReviews int
+}
+
+// Definition declares a badge and the rule earning it
+type Definition struct {
+	ID          string
+	Name        string
+	Description string
+	Rule        Rule
+}
+
+// Definitions lists every badge, in the order they are shown. IDs are stored with awards, so
+// they must not change; new badges are awarded from past history by the next evaluation.
+var Definitions = []Definition{
+	{ID: "first-review", Name: "First steps", Description: "Review your first word", Rule: Rule{Kind: KindReviews, Target: 1}},
+	{ID: "reviews-100", Name: "Warming up", Description: "Review 100 words", Rule: Rule{Kind: KindReviews, Target: 100}},
+	{ID: "reviews-1000", Name: "Thousand reviews", Description: "Review 1000 words", Rule: Rule{Kind: KindReviews, Target: 1000}},
+	{ID: "sessions-10", Name: "Regular", Description: "Start 10 study sessions", Rule: Rule{Kind: KindSessions, Target: 10}},
+	{ID: "sessions-100", Name: "Dedicated", Description: "Start 100 study sessions", Rule: Rule{Kind: KindSessions, Target: 100}},
+	{ID: "perfect-session", Name: "Perfect session", Description: "Complete a session of at least 10 reviews without a mistake", Rule: Rule{Kind: KindPerfectSessions, Target: 1, MinReviews: 10}},
+	{ID: "perfect-sessions-10", Name: "Flawless", Description: "Complete 10 perfect sessions", Rule: Rule{Kind: KindPerfectSessions, Target: 10, MinReviews: 10}},
+	{ID: "words-mastered-10", Name: "Getting there", Description: "Master 10 words", Rule: Rule{Kind: KindWordsMastered, Target: 10}},
+	{ID: "words-mastered-100", Name: "Wordsmith", Description: "Master 100 words", Rule: Rule{Kind: KindWordsMastered, Target: 100}},
+	{ID: "adjectives-mastered-50", Name: "Descriptive", Description: "Master 50 adjectives", Rule: Rule{Kind: KindWordsMastered, Target: 50, Group: "core-adjectives"}},
+	{ID: "verbs-mastered-100", Name: "Verb virtuoso", Description: "Master 100 verbs", Rule: Rule{Kind: KindWordsMastered, Target: 100, Group: "core-verbs"}},
+	{ID: "streak-7", Name: "One week", Description: "Study 7 days in a row", Rule: Rule{Kind: KindStreak, Target: 7}},
+	{ID: "streak-30", Name: "30-day streak", Description: "Study 30 days in a row", Rule: Rule{Kind: KindStreak, Target: 30}},
+	{ID: "streak-100", Name: "Unstoppable", Description: "Study 100 days in a row", Rule: Rule{Kind: KindStreak, Target: 100}},
+}
+
+// Find returns the definition with the given ID
+func Find(id string) (Definition, bool) {
+	for _, d := range Definitions {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// groupSlugs returns the slugs of the groups the definitions refer to
+func groupSlugs(defs []Definition) []string {
+	var slugs []string
+	seen := map[string]bool{}
+	for _, d := range defs {
+		if d.Rule.Group != "" && !seen[d.Rule.Group] {
+			seen[d.Rule.Group] = true
+			slugs = append(slugs, d.Rule.Group)
+		}
+	}
+	return slugs
+}
@@ -0,0 +1,151 @@
+package achievement
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lang-portal/internal/events"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+	"lang-portal/internal/streak"
+)
+
+// Engine evaluates the badges when sessions or reviews are written and stores the awards
+type Engine struct {
+	repo        repository.AchievementRepository
+	publisher   events.Publisher
+	definitions []Definition
+	location    *time.Location
+	rules       streak.Rules
+	debounce    time.Duration
+	changed     chan struct{}
+}
+
+// NewEngine creates an engine counting streak days in loc; bursts of changes within debounce
+// cause a single evaluation, and new awards are published as achievement.awarded events
+func NewEngine(repo repository.AchievementRepository, publisher events.Publisher, loc *time.Location, rules streak.Rules, debounce time.Duration) *Engine {
+	return &Engine{
+		repo:        repo,
+		publisher:   publisher,
+		definitions: Definitions,
+		location:    loc,
+		rules:       rules,
+		debounce:    debounce,
+		changed:     make(chan struct{}, 1),
+	}
+}
+
+// HandleEvent is an events.Handler scheduling an evaluation for changes rules count
+func (e *Engine) HandleEvent(_ context.Context, event events.Event) {
+	switch event.Type {
+	case events.SessionCreated, events.SessionCompleted, events.ReviewCreated:
+		select {
+		case e.changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run backfills the awards from past history, then evaluates after changes until ctx is cancelled
+func (e *Engine) Run(ctx context.Context) {
+	if awarded, err := e.Backfill(ctx); err != nil {
+		log.Printf("Failed to backfill achievements: %v", err)
+	} else if len(awarded) > 0 {
+		log.Printf("Backfilled %d achievements", len(awarded))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.changed:
+		}
+
+		// Let a burst of reviews settle into one evaluation
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.debounce):
+		}
+		select {
+		case <-e.changed:
+		default:
+		}
+
+		awarded, err := e.award(ctx)
+		if err != nil {
+			log.Printf("Failed to evaluate achievements: %v", err)
+			continue
+		}
+		for _, a := range awarded {
+			e.publisher.Publish(ctx, events.Event{Type: events.AchievementAwarded, Data: a})
+		}
+	}
+}
+
+// Backfill awards the badges already earned by past history, at the time they were earned,
+// without publishing events
+func (e *Engine) Backfill(ctx context.Context) ([]models.AchievementAward, error) {
+	return e.award(ctx)
+}
+
+// award evaluates every badge and stores the awards not stored yet
+func (e *Engine) award(ctx context.Context) ([]models.AchievementAward, error) {
+	results, err := e.evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var awards []models.AchievementAward
+	for _, d := range e.definitions {
+		if a := results[d.ID].Award; a != nil {
+			a.AchievementID = d.ID
+			awards = append(awards, *a)
+		}
+	}
+	return e.repo.SaveAwards(ctx, awards)
+}
+
+// evaluate replays the whole history for every badge
+func (e *Engine) evaluate(ctx context.Context) (map[string]Result, error) {
+	history, err := e.repo.GetHistory(ctx, groupSlugs(e.definitions))
+	if err != nil {
+		return nil, err
+	}
+	return Evaluate(e.definitions, history, e.location, time.Now(), e.rules), nil
+}
+
+// List returns every badge with the progress towards it and its stored award
+func (e *Engine) List(ctx context.Context) ([]models.Achievement, error) {
+	results, err := e.evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := e.repo.ListAwards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	awards := make(map[string]models.AchievementAward, len(stored))
+	for _, a := range stored {
+		awards[a.AchievementID] = a
+	}
+
+	achievements := make([]models.Achievement, 0, len(e.definitions))
+	for _, d := range e.definitions {
+		achievement := models.Achievement{
+			ID:          d.ID,
+			Name:        d.Name,
+			Description: d.Description,
+			Progress:    results[d.ID].Progress,
+			Target:      d.Rule.Target,
+		}
+		if a, ok := awards[d.ID]; ok {
+			achievement.Earned = true
+			achievement.AwardedAt = &a.AwardedAt
+			achievement.StudySessionID = a.StudySessionID
+		}
+		achievements = append(achievements, achievement)
+	}
+	return achievements, nil
+}
@@ -0,0 +1,183 @@
+package achievement
+
+import (
+	"sort"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+	"lang-portal/internal/streak"
+)
+
+// Result is the outcome of a rule over the study history
+type Result struct {
+	// Progress is the current value of what the rule counts
+	Progress int
+	// Award is set when the rule was met, at the first time it was
+	Award *models.AchievementAward
+}
+
+// Evaluate replays the history and returns the result of every definition by ID. Rules are
+// replayed rather than checked against the current state, so an award gets the time the rule
+// was first met and badges are earned from history the same way as from new reviews.
+func Evaluate(defs []Definition, history *repository.AchievementHistory, loc *time.Location, now time.Time, rules streak.Rules) map[string]Result {
+	results := make(map[string]Result, len(defs))
+	var mastered []Definition
+	for _, d := range defs {
+		switch d.Rule.Kind {
+		case KindReviews:
+			results[d.ID] = countReviews(d.Rule, history.Reviews)
+		case KindSessions:
+			results[d.ID] = countSessions(d.Rule, history.Sessions)
+		case KindPerfectSessions:
+			results[d.ID] = countPerfectSessions(d.Rule, history)
+		case KindStreak:
+			results[d.ID] = measureStreak(d.Rule, history, loc, now, rules)
+		case KindWordsMastered:
+			mastered = append(mastered, d)
+		}
+	}
+	for id, result := range countMastered(mastered, history) {
+		results[id] = result
+	}
+	return results
+}
+
+// award creates an award earned at t, in session sessionID unless it is zero
+func award(at time.Time, sessionID int64) *models.AchievementAward {
+	a := &models.AchievementAward{AwardedAt: at.UTC()}
+	if sessionID != 0 {
+		a.StudySessionID = &sessionID
+	}
+	return a
+}
+
+// countReviews meets the rule with the review reaching the target
+func countReviews(rule Rule, reviews []repository.ReviewRecord) Result {
+	result := Result{Progress: len(reviews)}
+	if rule.Target > 0 && len(reviews) >= rule.Target {
+		review := reviews[rule.Target-1]
+		result.Award = award(review.CreatedAt, review.StudySessionID)
+	}
+	return result
+}
+
+// countSessions meets the rule with the session reaching the target
+func countSessions(rule Rule, sessions []repository.SessionRecord) Result {
+	result := Result{Progress: len(sessions)}
+	if rule.Target > 0 && len(sessions) >= rule.Target {
+		session := sessions[rule.Target-1]
+		result.Award = award(session.CreatedAt, session.ID)
+	}
+	return result
+}
+
+// countPerfectSessions meets the rule when the perfect session reaching the target is completed
+func countPerfectSessions(rule Rule, history *repository.AchievementHistory) Result {
+	type tally struct{ reviews, wrong int }
+	tallies := map[int64]*tally{}
+	for _, review := range history.Reviews {
+		t, ok := tallies[review.StudySessionID]
+		if !ok {
+			t = &tally{}
+			tallies[review.StudySessionID] = t
+		}
+		t.reviews++
+		if !review.Correct {
+			t.wrong++
+		}
+	}
+
+	// Sessions are only perfect once completed, in the order they were completed
+	var perfect []repository.SessionRecord
+	for _, session := range history.Sessions {
+		t, ok := tallies[session.ID]
+		if session.CompletedAt != nil && ok && t.reviews >= rule.MinReviews && t.wrong == 0 {
+			perfect = append(perfect, session)
+		}
+	}
+	sort.SliceStable(perfect, func(i, j int) bool {
+		return perfect[i].CompletedAt.Before(*perfect[j].CompletedAt)
+	})
+
+	result := Result{Progress: len(perfect)}
+	if rule.Target > 0 && len(perfect) >= rule.Target {
+		session := perfect[rule.Target-1]
+		result.Award = award(*session.CompletedAt, session.ID)
+	}
+	return result
+}
+
+// measureStreak meets the rule on the first day the streak reaches the target, at the first study of that day
+func measureStreak(rule Rule, history *repository.AchievementHistory, loc *time.Location, now time.Time, rules streak.Rules) Result {
+	activity := make([]time.Time, 0, len(history.Reviews)+len(history.Sessions))
+	for _, review := range history.Reviews {
+		activity = append(activity, review.CreatedAt)
+	}
+	for _, session := range history.Sessions {
+		activity = append(activity, session.CreatedAt)
+	}
+	sort.Slice(activity, func(i, j int) bool { return activity[i].Before(activity[j]) })
+
+	summary, days := streak.Calculate(activity, loc, now, rules)
+	result := Result{Progress: summary.CurrentStreak}
+	for _, day := range days {
+		if rule.Target <= 0 || day.Streak < rule.Target {
+			continue
+		}
+		for _, at := range activity {
+			if at.In(loc).Format(streak.DateFormat) == day.Date {
+				result.Award = award(at, 0)
+				break
+			}
+		}
+		break
+	}
+	return result
+}
+
+// countMastered replays reviews word by word; a rule is met by the review mastering the word
+// that brings the number of mastered words of its scope to the target
+func countMastered(defs []Definition, history *repository.AchievementHistory) map[string]Result {
+	results := make(map[string]Result, len(defs))
+	if len(defs) == 0 {
+		return results
+	}
+
+	words := map[int64]*models.MasteryRun{}
+	masteredCount := make([]int, len(defs))
+	for _, review := range history.Reviews {
+		w, ok := words[review.WordID]
+		if !ok {
+			w = &models.MasteryRun{}
+			words[review.WordID] = w
+		}
+		before := w.Level() == models.MasteryMastered
+		w.Review(review.Correct, review.CreatedAt)
+		after := w.Level() == models.MasteryMastered
+		if before == after {
+			continue
+		}
+
+		for i, d := range defs {
+			if d.Rule.Group != "" && !history.GroupWords[d.Rule.Group][review.WordID] {
+				continue
+			}
+			if after {
+				masteredCount[i]++
+			} else {
+				masteredCount[i]--
+			}
+			if _, met := results[d.ID]; !met && d.Rule.Target > 0 && masteredCount[i] >= d.Rule.Target {
+				results[d.ID] = Result{Award: award(review.CreatedAt, review.StudySessionID)}
+			}
+		}
+	}
+
+	for i, d := range defs {
+		result := results[d.ID]
+		result.Progress = masteredCount[i]
+		results[d.ID] = result
+	}
+	return results
+}
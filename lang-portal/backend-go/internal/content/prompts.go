@@ -0,0 +1,30 @@
+package content
+
+import (
+	"fmt"
+
+	"lang-portal/internal/llm"
+	"lang-portal/internal/models"
+)
+
+// systemPrompt sets the tone of every generated text
+const systemPrompt = "You are a Japanese teacher helping English-speaking beginners. " +
+	"Answer in plain text without headings or markdown, and keep it short."
+
+// instructions asks for each kind of content; %[1]s is the word, %[2]s its reading and %[3]s its meaning
+var instructions = map[string]string{
+	models.ContentExampleSentences: "Write 3 short example sentences using the Japanese word %[1]s (%[2]s, \"%[3]s\"). " +
+		"Put each sentence on its own line as: Japanese | romaji | English.",
+	models.ContentMnemonic: "Write a memorable mnemonic in at most 3 sentences that helps remember " +
+		"that the Japanese word %[1]s, read %[2]s, means \"%[3]s\".",
+	models.ContentGrammar: "Explain in at most 5 sentences how the Japanese word %[1]s (%[2]s, \"%[3]s\") is used: " +
+		"its part of speech, how it conjugates or combines with particles, and a common mistake learners make.",
+}
+
+// prompt builds the chat asking for one kind of content about a word
+func prompt(kind string, word *models.Word) []llm.Message {
+	return []llm.Message{
+		{Role: llm.RoleSystem, Content: systemPrompt},
+		{Role: llm.RoleUser, Content: fmt.Sprintf(instructions[kind], word.Kanji, word.Romaji, word.English)},
+	}
+}
@@ -0,0 +1,142 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lang-portal/internal/llm"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// Service generates word content with a language model and caches it; teachers can
+// replace cached content, and their edits are kept until they are explicitly overwritten
+type Service struct {
+	words    repository.WordRepository
+	repo     repository.WordContentRepository
+	provider llm.Provider
+}
+
+// NewService creates a service generating content with provider
+func NewService(words repository.WordRepository, repo repository.WordContentRepository, provider llm.Provider) *Service {
+	return &Service{words: words, repo: repo, provider: provider}
+}
+
+// List retrieves the content stored for a word, without generating any
+func (s *Service) List(ctx context.Context, wordID int64) ([]models.WordContent, error) {
+	if _, err := s.word(ctx, wordID); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, wordID)
+}
+
+// Get retrieves one kind of content of a word, generating it when none is cached
+func (s *Service) Get(ctx context.Context, wordID int64, kind string) (*models.WordContent, error) {
+	if err := checkKind(kind); err != nil {
+		return nil, err
+	}
+	cached, err := s.repo.Get(ctx, wordID, kind)
+	if err == nil {
+		return cached, nil
+	}
+	if err.Error() != "content not found" {
+		return nil, err
+	}
+	return s.Generate(ctx, wordID, kind, false)
+}
+
+// Generate asks the language model for one kind of content of a word and caches it.
+// Content written by a teacher is only replaced when overwrite is set.
+func (s *Service) Generate(ctx context.Context, wordID int64, kind string, overwrite bool) (*models.WordContent, error) {
+	if err := checkKind(kind); err != nil {
+		return nil, err
+	}
+	word, err := s.word(ctx, wordID)
+	if err != nil {
+		return nil, err
+	}
+	// Checked again when saving, as a teacher may edit the content while it is generated
+	if !overwrite {
+		existing, err := s.repo.Get(ctx, wordID, kind)
+		if err != nil && err.Error() != "content not found" {
+			return nil, err
+		}
+		if existing != nil && existing.Source == models.ContentTeacher {
+			return nil, fmt.Errorf("content was written by a teacher")
+		}
+	}
+
+	text, err := s.provider.Complete(ctx, prompt(kind, word))
+	if err != nil {
+		return nil, err
+	}
+	// Long rambling answers are cut rather than rejected
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > models.MaxWordContentLength {
+		text = string([]rune(text)[:models.MaxWordContentLength])
+	}
+
+	now := time.Now().UTC()
+	content := &models.WordContent{
+		WordID:    wordID,
+		Kind:      kind,
+		Content:   text,
+		Source:    models.ContentGenerated,
+		Model:     s.provider.Model(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Save(ctx, content, overwrite); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// Edit replaces one kind of content of a word with text written by a teacher
+func (s *Service) Edit(ctx context.Context, wordID int64, kind, text string) (*models.WordContent, error) {
+	now := time.Now().UTC()
+	content := &models.WordContent{
+		WordID:    wordID,
+		Kind:      kind,
+		Content:   strings.TrimSpace(text),
+		Source:    models.ContentTeacher,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := content.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, content, true); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// Delete removes one kind of content of a word, so the next Get generates it again
+func (s *Service) Delete(ctx context.Context, wordID int64, kind string) error {
+	if err := checkKind(kind); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, wordID, kind)
+}
+
+// word loads a word, translating the repository's missing row into "word not found"
+func (s *Service) word(ctx context.Context, wordID int64) (*models.Word, error) {
+	word, err := s.words.GetByID(ctx, wordID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("word not found")
+	}
+	return word, err
+}
+
+// checkKind rejects unknown kinds of content
+func checkKind(kind string) error {
+	if !models.IsWordContentKind(kind) {
+		return fmt.Errorf("kind must be one of: %s", strings.Join(models.WordContentKinds, ", "))
+	}
+	return nil
+}
@@ -20,8 +20,15 @@ type Word struct {
 	Parts   json.RawMessage `json:"parts"`
 }
 
-// SeedDatabase populates the database with initial data
-func SeedDatabase(db *sql.DB) error {
+// StudyActivity represents the structure of a study activity for seeding
+type StudyActivity struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	PreviewURL string `json:"preview_url"`
+}
+
+// SeedDatabase populates the database with the initial data of the JSON files in seedDir
+func SeedDatabase(db *sql.DB, seedDir string) error {
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -33,14 +40,14 @@ func SeedDatabase(db *sql.DB) error {
 	}
 
 	// Seed words
-	wordIDs, err := seedWords(tx)
+	wordIDs, err := seedWords(tx, seedDir)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to seed words: %w", err)
 	}
 
 	// Seed groups
-	groupIDs, err := seedGroups(tx, wordIDs)
+	groupIDs, err := seedGroups(tx, seedDir, wordIDs)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to seed groups: %w", err)
@@ -53,6 +60,13 @@ func SeedDatabase(db *sql.DB) error {
 		return fmt.Errorf("failed to seed word-groups associations: %w", err)
 	}
 
+	// Seed study activities
+	err = seedStudyActivities(tx, seedDir)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to seed study activities: %w", err)
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -61,11 +75,11 @@ func SeedDatabase(db *sql.DB) error {
 	return nil
 }
 
-func seedWords(tx *sql.Tx) ([]int64, error) {
+func seedWords(tx *sql.Tx, seedDir string) ([]int64, error) {
 	// Word files to seed
 	wordFiles := []string{
-		filepath.Join("c:\\Users\\nassima\\Desktop\\gen-ai-bootcamp-2025\\lang-portal\\backend-go", "seed", "words_adjectives.json"),
-		filepath.Join("c:\\Users\\nassima\\Desktop\\gen-ai-bootcamp-2025\\lang-portal\\backend-go", "seed", "words_verbs.json"),
+		filepath.Join(seedDir, "words_adjectives.json"),
+		filepath.Join(seedDir, "words_verbs.json"),
 	}
 
 	// Prepare word insert statement
@@ -118,23 +132,28 @@ func seedWords(tx *sql.Tx) ([]int64, error) {
 	return wordIDs, nil
 }
 
-func seedGroups(tx *sql.Tx, wordIDs []int64) ([]int64, error) {
+func seedGroups(tx *sql.Tx, seedDir string, wordIDs []int64) ([]int64, error) {
 	// Read seed data
-	seedPath := filepath.Join("c:\\Users\\nassima\\Desktop\\gen-ai-bootcamp-2025\\lang-portal\\backend-go", "seed", "groups.json")
+	seedPath := filepath.Join(seedDir, "groups.json")
 	data, err := os.ReadFile(seedPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read seed file: %w", err)
 	}
 
-	var groups []models.Group
+	// Slugs key the groups achievements refer to
+	var groups []struct {
+		models.Group
+		Slug *string `json:"slug"`
+	}
 	if err := json.Unmarshal(data, &groups); err != nil {
 		return nil, fmt.Errorf("failed to parse seed data: %w", err)
 	}
 
-	// Prepare group insert statement
+	// Prepare group insert statement; words_count is maintained by triggers. Seeding again
+	// adds groups without the slugs the first seeded groups already hold.
 	groupStmt, err := tx.Prepare(`
-		INSERT INTO groups (name, words_count)
-		VALUES (?, ?)
+		INSERT INTO groups (name, slug, words_count)
+		SELECT ?1, CASE WHEN EXISTS (SELECT 1 FROM groups WHERE slug = ?2) THEN NULL ELSE ?2 END, 0
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare group insert statement: %w", err)
@@ -155,7 +174,7 @@ func seedGroups(tx *sql.Tx, wordIDs []int64) ([]int64, error) {
 	var groupIDs []int64
 	for _, group := range groups {
 		// Insert group
-		result, err := groupStmt.Exec(group.Name, group.WordsCount)
+		result, err := groupStmt.Exec(group.Name, group.Slug)
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert group: %w", err)
 		}
@@ -209,6 +228,40 @@ func seedWordGroups(tx *sql.Tx, wordIDs []int64, groupIDs []int64) error {
 	return nil
 }
 
+func seedStudyActivities(tx *sql.Tx, seedDir string) error {
+	// Read seed data
+	seedPath := filepath.Join(seedDir, "study_activities.json")
+	data, err := os.ReadFile(seedPath)
+	if err != nil {
+		return fmt.Errorf("failed to read seed file: %w", err)
+	}
+
+	var activities []StudyActivity
+	if err := json.Unmarshal(data, &activities); err != nil {
+		return fmt.Errorf("failed to parse seed data: %w", err)
+	}
+
+	// Prepare study activity insert statement, skipping activities that are already registered
+	activityStmt, err := tx.Prepare(`
+		INSERT INTO study_activities (name, url, preview_url)
+		SELECT ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM study_activities WHERE name = ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare study activity insert statement: %w", err)
+	}
+	defer activityStmt.Close()
+
+	for _, activity := range activities {
+		_, err = activityStmt.Exec(activity.Name, activity.URL, activity.PreviewURL, activity.Name)
+		if err != nil {
+			return fmt.Errorf("failed to insert study activity: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // Helper function to get the minimum of two integers
 func min(a, b int) int {
 	if a < b {
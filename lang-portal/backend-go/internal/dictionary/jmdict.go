@@ -0,0 +1,117 @@
+package dictionary
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"lang-portal/internal/models"
+)
+
+// jmdictEntry is an <entry> of JMdict, reduced to what is imported
+type jmdictEntry struct {
+	Sequence int64 `xml:"ent_seq"`
+	Kanji    []struct {
+		Text     string   `xml:"keb"`
+		Priority []string `xml:"ke_pri"`
+	} `xml:"k_ele"`
+	Readings []struct {
+		Text         string    `xml:"reb"`
+		NoKanji      *struct{} `xml:"re_nokanji"`
+		Restrictions []string  `xml:"re_restr"`
+		Priority     []string  `xml:"re_pri"`
+	} `xml:"r_ele"`
+	Senses []struct {
+		KanjiRestrictions   []string `xml:"stagk"`
+		ReadingRestrictions []string `xml:"stagr"`
+		PartsOfSpeech       []string `xml:"pos"`
+		Misc                []string `xml:"misc"`
+		Glosses             []struct {
+			Lang  string `xml:"lang,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"gloss"`
+	} `xml:"sense"`
+}
+
+// commonPriorities are the priority markers JMdict counts as common words: the
+// first half of the newspaper ranking, Ichimango and the other frequency lists
+var commonPriorities = map[string]bool{
+	"news1": true, "ichi1": true, "spec1": true, "spec2": true, "gai1": true,
+}
+
+// isCommon reports whether priorities contain a common word marker
+func isCommon(priorities []string) bool {
+	for _, p := range priorities {
+		if commonPriorities[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// UsuallyKana is the note of senses whose word is usually written in kana alone
+const UsuallyKana = "word usually written using kana alone"
+
+// ReadJMdict streams the entries of a JMdict file to fn with their English senses; entries
+// without any are skipped. Parts of speech and notes are the descriptions of the DTD entities.
+func ReadJMdict(r io.Reader, fn func(*models.DictionaryEntry) error) error {
+	return eachElement(r, "entry", func(d *xml.Decoder, start *xml.StartElement) error {
+		var e jmdictEntry
+		if err := d.DecodeElement(&e, start); err != nil {
+			return fmt.Errorf("invalid JMdict entry: %w", err)
+		}
+		if e.Sequence == 0 || len(e.Readings) == 0 {
+			return fmt.Errorf("invalid JMdict entry %d: sequence number and reading are required", e.Sequence)
+		}
+
+		entry := &models.DictionaryEntry{
+			ID:       e.Sequence,
+			Kanji:    []models.DictionaryForm{},
+			Readings: []models.DictionaryReading{},
+			Senses:   []models.DictionarySense{},
+		}
+		for _, k := range e.Kanji {
+			common := isCommon(k.Priority)
+			entry.Kanji = append(entry.Kanji, models.DictionaryForm{Text: k.Text, Common: common})
+			entry.Common = entry.Common || common
+		}
+		for _, reading := range e.Readings {
+			common := isCommon(reading.Priority)
+			entry.Readings = append(entry.Readings, models.DictionaryReading{
+				Text:    reading.Text,
+				Common:  common,
+				Kanji:   reading.Restrictions,
+				NoKanji: reading.NoKanji != nil,
+			})
+			entry.Common = entry.Common || common
+		}
+
+		// Parts of speech carry over to the following senses until new ones are given
+		var partsOfSpeech []string
+		for _, s := range e.Senses {
+			if len(s.PartsOfSpeech) > 0 {
+				partsOfSpeech = s.PartsOfSpeech
+			}
+			sense := models.DictionarySense{
+				PartsOfSpeech: append([]string{}, partsOfSpeech...),
+				Glosses:       []string{},
+				Misc:          s.Misc,
+				Kanji:         s.KanjiRestrictions,
+				Readings:      s.ReadingRestrictions,
+			}
+			// Glosses without a language are English
+			for _, gloss := range s.Glosses {
+				if gloss.Lang == "" || gloss.Lang == "eng" {
+					sense.Glosses = append(sense.Glosses, gloss.Value)
+				}
+			}
+			if len(sense.Glosses) > 0 {
+				entry.Senses = append(entry.Senses, sense)
+			}
+		}
+		if len(entry.Senses) == 0 {
+			return nil
+		}
+		return fn(entry)
+	})
+}
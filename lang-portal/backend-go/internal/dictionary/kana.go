@@ -0,0 +1,229 @@
+package dictionary
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Hiragana folds the katakana of s into hiragana, so that readings written either way
+// compare equal; the long vowel mark and other characters are kept
+func Hiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 'ァ' + 'ぁ'
+		}
+		return r
+	}, s)
+}
+
+// HasJapanese reports whether s contains kanji or kana
+func HasJapanese(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+	return false
+}
+
+// smallKana are the kana written small after another to form a single mora
+const smallKana = "ゃゅょぁぃぅぇぉゎャュョァィゥェォヮ"
+
+// Morae splits kana into morae: each kana with the small kana following it, so that
+// きょう is きょ and う; other characters are a mora of their own
+func Morae(kana string) []string {
+	var morae []string
+	for _, r := range kana {
+		if len(morae) > 0 && strings.ContainsRune(smallKana, r) {
+			morae[len(morae)-1] += string(r)
+			continue
+		}
+		morae = append(morae, string(r))
+	}
+	return morae
+}
+
+// Romaji returns the Hepburn romaji syllable of each mora, as in the seed words: a small
+// っ is the consonant it doubles and ー repeats the vowel before it. It reports false
+// when a mora is not kana.
+func Romaji(morae []string) ([]string, bool) {
+	syllables := make([]string, len(morae))
+	for i, mora := range morae {
+		mora = Hiragana(mora)
+		switch mora {
+		case "っ":
+			continue
+		case "ー":
+			if i == 0 || syllables[i-1] == "" {
+				return nil, false
+			}
+			previous := syllables[i-1]
+			syllables[i] = previous[len(previous)-1:]
+			continue
+		}
+		syllable, ok := kanaRomaji[mora]
+		if !ok {
+			// Unusual combinations read as their kana one after the other
+			for _, r := range mora {
+				part, ok := kanaRomaji[string(r)]
+				if !ok {
+					return nil, false
+				}
+				syllable += part
+			}
+		}
+		syllables[i] = syllable
+	}
+
+	// A small っ takes the consonant of the next syllable, t before ch
+	for i, mora := range morae {
+		if Hiragana(mora) != "っ" {
+			continue
+		}
+		syllables[i] = "t"
+		if i+1 < len(syllables) {
+			next := syllables[i+1]
+			if next != "" && !strings.ContainsRune("aiueo", rune(next[0])) && !strings.HasPrefix(next, "ch") {
+				syllables[i] = next[:1]
+			}
+		}
+	}
+	return syllables, true
+}
+
+// FoldLong drops the marks of long vowels from hiragana: ー and the vowels lengthening
+// the mora before them, as in おう, えい and ああ, so that readings compare equal however
+// long vowels were written or typed; こーひー and こおひい fold to こひ
+func FoldLong(kana string) string {
+	var folded strings.Builder
+	vowel := byte(0)
+	for _, mora := range Morae(kana) {
+		syllables, ok := Romaji([]string{mora})
+		if mora == "ー" || !ok {
+			if mora != "ー" {
+				folded.WriteString(mora)
+				vowel = 0
+			}
+			continue
+		}
+		syllable := syllables[0]
+		if len(syllable) == 1 && vowel != 0 &&
+			(syllable[0] == vowel || (syllable == "u" && vowel == 'o') || (syllable == "i" && vowel == 'e')) {
+			continue
+		}
+		folded.WriteString(mora)
+		vowel = 0
+		if last := syllable[len(syllable)-1]; strings.IndexByte("aiueo", last) >= 0 {
+			vowel = last
+		}
+	}
+	return folded.String()
+}
+
+// Kana converts romaji to hiragana, taking Hepburn spellings along with common
+// alternatives such as si and sya: a doubled consonant is a small っ, n' and n before a
+// consonant are ん, and macrons lengthen vowels. It reports false when some letters
+// are not romaji.
+func Kana(romaji string) (string, bool) {
+	romaji = strings.NewReplacer(
+		"ā", "aa", "ī", "ii", "ū", "uu", "ē", "ee", "ō", "ou", "â", "aa", "î", "ii", "û", "uu", "ê", "ee", "ô", "ou",
+		" ", "", "-", "",
+	).Replace(strings.ToLower(romaji))
+
+	var kana strings.Builder
+	for i := 0; i < len(romaji); {
+		c := romaji[i]
+		switch {
+		case c == '\'':
+			i++
+			continue
+		case c == 'n' && i+1 < len(romaji) && romaji[i+1] == '\'':
+			kana.WriteString("ん")
+			i += 2
+			continue
+		case c == 'm' && i+1 < len(romaji) && strings.IndexByte("bmp", romaji[i+1]) >= 0:
+			kana.WriteString("ん")
+			i++
+			continue
+		case i+1 < len(romaji) && strings.IndexByte("aiueon", c) < 0 &&
+			(romaji[i+1] == c || (c == 't' && strings.HasPrefix(romaji[i+1:], "ch"))):
+			kana.WriteString("っ")
+			i++
+			continue
+		}
+
+		matched := false
+		for length := min(3, len(romaji)-i); length > 0; length-- {
+			if mora, ok := romajiKana[romaji[i:i+length]]; ok {
+				kana.WriteString(mora)
+				i += length
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "", false
+		}
+	}
+	return kana.String(), kana.Len() > 0
+}
+
+// romajiKana maps romaji to the hiragana typed for it: kanaRomaji reversed, leaving
+// out small and archaic kana, along with other common spellings
+var romajiKana = func() map[string]string {
+	m := map[string]string{}
+	for mora, romaji := range kanaRomaji {
+		if strings.ContainsAny(mora, "ゐゑをぢづゕゖ") || (len([]rune(mora)) == 1 && strings.Contains(smallKana, mora)) {
+			continue
+		}
+		m[romaji] = mora
+	}
+	for romaji, mora := range map[string]string{
+		"si": "し", "hu": "ふ", "zi": "じ", "wo": "を",
+		"sya": "しゃ", "syu": "しゅ", "syo": "しょ", "tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+		"zya": "じゃ", "zyu": "じゅ", "zyo": "じょ", "jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+	} {
+		m[romaji] = mora
+	}
+	return m
+}()
+
+// kanaRomaji maps hiragana morae to Hepburn romaji
+var kanaRomaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ん": "n",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ゔ": "vu",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo", "ゎ": "wa", "ゕ": "ka", "ゖ": "ke",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+	"つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso",
+	"いぇ": "ye",
+}
@@ -0,0 +1,94 @@
+package dictionary
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"lang-portal/internal/models"
+)
+
+// kanjidicCharacter is a <character> entry of KANJIDIC2, reduced to what is imported
+type kanjidicCharacter struct {
+	Literal  string `xml:"literal"`
+	Radicals []struct {
+		Type  string `xml:"rad_type,attr"`
+		Value int    `xml:",chardata"`
+	} `xml:"radical>rad_value"`
+	Misc struct {
+		Grade       string `xml:"grade"`
+		StrokeCount []int  `xml:"stroke_count"`
+		Frequency   string `xml:"freq"`
+		JLPT        string `xml:"jlpt"`
+	} `xml:"misc"`
+	Groups []struct {
+		Readings []struct {
+			Type  string `xml:"r_type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"reading"`
+		Meanings []struct {
+			Lang  string `xml:"m_lang,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"meaning"`
+	} `xml:"reading_meaning>rmgroup"`
+}
+
+// ReadKANJIDIC2 streams the characters of a KANJIDIC2 file to fn, with their English
+// meanings and Japanese readings; later stroke counts, which are common miscounts, are dropped
+func ReadKANJIDIC2(r io.Reader, fn func(*models.Kanji) error) error {
+	return eachElement(r, "character", func(d *xml.Decoder, start *xml.StartElement) error {
+		var c kanjidicCharacter
+		if err := d.DecodeElement(&c, start); err != nil {
+			return fmt.Errorf("invalid KANJIDIC2 character: %w", err)
+		}
+		if c.Literal == "" || len(c.Misc.StrokeCount) == 0 {
+			return fmt.Errorf("invalid KANJIDIC2 character %q: literal and stroke count are required", c.Literal)
+		}
+
+		kanji := &models.Kanji{
+			Literal:     c.Literal,
+			Meanings:    []string{},
+			OnReadings:  []string{},
+			KunReadings: []string{},
+			StrokeCount: c.Misc.StrokeCount[0],
+			Radicals:    []models.Radical{},
+			Grade:       optionalInt(c.Misc.Grade),
+			JLPT:        optionalInt(c.Misc.JLPT),
+			Frequency:   optionalInt(c.Misc.Frequency),
+		}
+		for _, radical := range c.Radicals {
+			kanji.Radicals = append(kanji.Radicals, models.Radical{
+				Type:   radical.Type,
+				Number: radical.Value,
+				Symbol: models.RadicalSymbol(radical.Value),
+			})
+		}
+		for _, group := range c.Groups {
+			for _, reading := range group.Readings {
+				switch reading.Type {
+				case "ja_on":
+					kanji.OnReadings = append(kanji.OnReadings, reading.Value)
+				case "ja_kun":
+					kanji.KunReadings = append(kanji.KunReadings, reading.Value)
+				}
+			}
+			// Meanings without a language are English
+			for _, meaning := range group.Meanings {
+				if meaning.Lang == "" || meaning.Lang == "en" {
+					kanji.Meanings = append(kanji.Meanings, meaning.Value)
+				}
+			}
+		}
+		return fn(kanji)
+	})
+}
+
+// optionalInt parses an optional number, which is nil when absent or invalid
+func optionalInt(value string) *int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,73 @@
+package dictionary
+
+import (
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// entityDeclaration matches the <!ENTITY name "value"> declarations of an internal DTD
+var entityDeclaration = regexp.MustCompile(`<!ENTITY\s+([\w.-]+)\s+"([^"]*)"\s*>`)
+
+// Open opens a dictionary file, decompressing it when its name ends in .gz as the
+// files are distributed
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary: %w", err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return f, nil
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to decompress dictionary: %w", err)
+	}
+	return &gzipFile{Reader: gz, file: f}, nil
+}
+
+// gzipFile closes both the decompressor and the file underneath
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+// Close closes the decompressor and the file
+func (g *gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+// eachElement decodes every element named name of an EDRDG XML file with decode. The files
+// declare entities in their DTD, which the decoder learns before reaching the entries.
+func eachElement(r io.Reader, name string, decode func(d *xml.Decoder, start *xml.StartElement) error) error {
+	d := xml.NewDecoder(r)
+	d.Entity = map[string]string{}
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid dictionary XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			for _, m := range entityDeclaration.FindAllStringSubmatch(string(t), -1) {
+				d.Entity[m[1]] = m[2]
+			}
+		case xml.StartElement:
+			if t.Name.Local == name {
+				if err := decode(d, &t); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types raised by the repositories
+const (
+	SessionCreated   = "session.created"
+	SessionCompleted = "session.completed"
+	ReviewCreated    = "review.created"
+	ReviewDeleted    = "review.deleted"
+	WordUpdated      = "word.updated"
+)
+
+// AchievementAwarded is raised when a badge is earned by new study activity
+const AchievementAwarded = "achievement.awarded"
+
+// Types lists every event type, in the order they are documented
+var Types = []string{SessionCreated, SessionCompleted, ReviewCreated, ReviewDeleted, WordUpdated, AchievementAwarded}
+
+// IsType reports whether t is a known event type
+func IsType(t string) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a change that happened in the portal
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Publisher accepts events once the change they describe is committed
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// Discard is a Publisher dropping every event, for tools that do not notify anyone
+var Discard Publisher = discard{}
+
+type discard struct{}
+
+func (discard) Publish(context.Context, Event) {}
+
+// Handler receives published events; it runs on the publishing goroutine so it must not block
+type Handler func(ctx context.Context, event Event)
+
+// Bus is an in-process Publisher fanning events out to subscribed handlers
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for every later event
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish stamps the event with an id and time and hands it to every handler
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if event.ID == "" {
+		event.ID = newID()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	// The change is already committed, a failing handler must not fail the request
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("event handler panicked on %s: %v", event.Type, r)
+				}
+			}()
+			handler(context.WithoutCancel(ctx), event)
+		}()
+	}
+}
+
+// Batch collects events raised inside a transaction, to publish once it commits
+type Batch struct {
+	events []Event
+}
+
+// Add queues an event
+func (b *Batch) Add(eventType string, data interface{}) {
+	b.events = append(b.events, Event{Type: eventType, OccurredAt: time.Now().UTC(), Data: data})
+}
+
+// Publish hands the queued events to a publisher and empties the batch
+func (b *Batch) Publish(ctx context.Context, publisher Publisher) {
+	for _, event := range b.events {
+		publisher.Publish(ctx, event)
+	}
+	b.events = nil
+}
+
+// newID returns a random event id
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,189 @@
+package goals
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// DashboardDays is the number of days of daily goal attainment shown on the dashboard
+const DashboardDays = 7
+
+// Service measures learner goals against the recorded study activity
+type Service struct {
+	repo     repository.GoalRepository
+	location *time.Location
+}
+
+// NewService creates a service counting days in loc unless a request asks for another timezone
+func NewService(repo repository.GoalRepository, loc *time.Location) *Service {
+	return &Service{repo: repo, location: loc}
+}
+
+// Location returns the default timezone of the service
+func (s *Service) Location() *time.Location {
+	return s.location
+}
+
+// measured is the active goals of a learner along with the daily activity covering their periods
+type measured struct {
+	goals  []models.Goal
+	totals map[string]models.GoalTotals
+}
+
+// measure loads the active goals of a learner and the activity of the last periods(goal) periods
+// of each goal, up to and including the current one
+func (s *Service) measure(ctx context.Context, learnerID *int64, loc *time.Location, now time.Time, periods func(models.Goal) int) (*measured, error) {
+	goals, err := s.repo.ListGoals(ctx, learnerID, true)
+	if err != nil {
+		return nil, err
+	}
+	m := &measured{goals: goals}
+	if len(goals) == 0 {
+		return m, nil
+	}
+
+	var from, to time.Time
+	for i, g := range goals {
+		current := g.PeriodStart(now, loc)
+		first := current
+		for n := 1; n < periods(g); n++ {
+			first = g.PeriodStart(first.Add(-time.Hour), loc)
+		}
+		if i == 0 || first.Before(from) {
+			from = first
+		}
+		if next := g.NextPeriod(current); next.After(to) {
+			to = next
+		}
+	}
+
+	m.totals, err = s.repo.GetDailyTotals(ctx, learnerID, from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// result measures goal g over the period starting at start
+func (m *measured) result(g *models.Goal, start time.Time, current bool) models.GoalPeriodResult {
+	var sum models.GoalTotals
+	for day := start; day.Before(g.NextPeriod(start)); day = day.AddDate(0, 0, 1) {
+		sum.Add(m.totals[day.Format(models.HistoryDateFormat)])
+	}
+	return models.NewGoalPeriodResult(g, start, sum.Value(g.Metric), current)
+}
+
+// history measures goal g over its last periods periods, oldest first
+func (m *measured) history(g *models.Goal, loc *time.Location, now time.Time, periods int) models.GoalHistory {
+	h := models.GoalHistory{Goal: *g, Periods: make([]models.GoalPeriodResult, periods)}
+	start := g.PeriodStart(now, loc)
+	for i := periods - 1; i >= 0; i-- {
+		result := m.result(g, start, i == periods-1)
+		h.Periods[i] = result
+		if !result.Current && !result.BeforeGoal {
+			h.Ended++
+			if result.Met {
+				h.Met++
+			}
+		}
+		start = g.PeriodStart(start.Add(-time.Hour), loc)
+	}
+	if h.Ended > 0 {
+		h.Attainment = 100 * h.Met / h.Ended
+	}
+	return h
+}
+
+// Progress returns the active goals of a learner with their progress in the current period
+func (s *Service) Progress(ctx context.Context, learnerID *int64, loc *time.Location, now time.Time) ([]models.GoalProgress, error) {
+	m, err := s.measure(ctx, learnerID, loc, now, func(models.Goal) int { return 1 })
+	if err != nil {
+		return nil, err
+	}
+	progress := make([]models.GoalProgress, 0, len(m.goals))
+	for i := range m.goals {
+		g := &m.goals[i]
+		progress = append(progress, models.GoalProgress{Goal: *g, Progress: m.result(g, g.PeriodStart(now, loc), true)})
+	}
+	return progress, nil
+}
+
+// History returns the attainment of the active goals of a learner over their last periods
+// periods, up to and including the current one. Past periods are measured against the current
+// target, and those that started before the goal was created do not count towards attainment.
+func (s *Service) History(ctx context.Context, learnerID *int64, loc *time.Location, now time.Time, periods int) ([]models.GoalHistory, error) {
+	m, err := s.measure(ctx, learnerID, loc, now, func(models.Goal) int { return periods })
+	if err != nil {
+		return nil, err
+	}
+	histories := make([]models.GoalHistory, 0, len(m.goals))
+	for i := range m.goals {
+		histories = append(histories, m.history(&m.goals[i], loc, now, periods))
+	}
+	return histories, nil
+}
+
+// Dashboard returns the progress of the portal user's goals along with the daily goals met
+// on each of the last DashboardDays days
+func (s *Service) Dashboard(ctx context.Context, loc *time.Location, now time.Time) (*models.DashboardGoals, error) {
+	m, err := s.measure(ctx, nil, loc, now, func(g models.Goal) int {
+		if g.Period == models.GoalPeriodDaily {
+			return DashboardDays
+		}
+		return 1
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &models.DashboardGoals{
+		Goals: make([]models.GoalProgress, 0, len(m.goals)),
+		Total: len(m.goals),
+		Days:  make([]models.GoalDay, DashboardDays),
+	}
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	for i := range dashboard.Days {
+		dashboard.Days[i].Date = today.AddDate(0, 0, i-DashboardDays+1).Format(models.HistoryDateFormat)
+	}
+
+	for i := range m.goals {
+		g := &m.goals[i]
+		progress := m.result(g, g.PeriodStart(now, loc), true)
+		dashboard.Goals = append(dashboard.Goals, models.GoalProgress{Goal: *g, Progress: progress})
+		if progress.Met {
+			dashboard.Met++
+		}
+		if g.Period != models.GoalPeriodDaily {
+			continue
+		}
+		for day, result := range m.history(g, loc, now, DashboardDays).Periods {
+			if result.BeforeGoal {
+				continue
+			}
+			dashboard.Days[day].Total++
+			if result.Met {
+				dashboard.Days[day].Met++
+			}
+		}
+	}
+	return dashboard, nil
+}
+
+// GoalsMet counts the active goals of the portal user reached in their current period, in the
+// default timezone; it implements repository.GoalSource
+func (s *Service) GoalsMet(ctx context.Context) (met, total int, err error) {
+	progress, err := s.Progress(ctx, nil, s.location, time.Now())
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, p := range progress {
+		if p.Progress.Met {
+			met++
+		}
+	}
+	return met, len(progress), nil
+}
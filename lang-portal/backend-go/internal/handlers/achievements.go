@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+
+	"lang-portal/internal/achievement"
+	"lang-portal/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AchievementHandler handles HTTP requests related to achievements
+type AchievementHandler struct {
+	engine *achievement.Engine
+}
+
+// NewAchievementHandler creates a new handler for achievements
+func NewAchievementHandler(engine *achievement.Engine) *AchievementHandler {
+	return &AchievementHandler{engine: engine}
+}
+
+// ListAchievements handles GET /api/v1/achievements
+func (h *AchievementHandler) ListAchievements(c *gin.Context) {
+	achievements, err := h.engine.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve achievements",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	earned := 0
+	for _, a := range achievements {
+		if a.Earned {
+			earned++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  achievements,
+		"earned": earned,
+		"total":  len(achievements),
+	})
+}
+
+// GetAchievement handles GET /api/v1/achievements/:id
+func (h *AchievementHandler) GetAchievement(c *gin.Context) {
+	id := c.Param("id")
+	if _, ok := achievement.Find(id); !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Achievement not found",
+			"details": "unknown achievement " + id,
+		})
+		return
+	}
+
+	achievements, err := h.engine.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve achievement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	for _, a := range achievements {
+		if a.ID == id {
+			c.JSON(http.StatusOK, a)
+			return
+		}
+	}
+}
+
+// BackfillAchievements handles POST /api/v1/achievements/backfill
+func (h *AchievementHandler) BackfillAchievements(c *gin.Context) {
+	awarded, err := h.engine.Backfill(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to backfill achievements",
+			"details": err.Error(),
+		})
+		return
+	}
+	if awarded == nil {
+		awarded = []models.AchievementAward{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"awarded": awarded,
+	})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AssetHandler serves static assets, such as the preview images of study activities
+type AssetHandler struct {
+	root http.FileSystem
+}
+
+// NewAssetHandler creates a handler serving the files under dir
+func NewAssetHandler(dir string) *AssetHandler {
+	return &AssetHandler{root: http.Dir(dir)}
+}
+
+// GetAsset handles GET /assets/*path. Assets can be replaced under the same path, so they
+// are revalidated hourly with an ETag derived from their size and modification time.
+func (h *AssetHandler) GetAsset(c *gin.Context) {
+	name := c.Param("path")
+	f, err := h.root.Open(name)
+	if err != nil {
+		respondAssetNotFound(c, name)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	// Directories are not listed, and hidden files such as .gitkeep are not served
+	if err != nil || info.IsDir() || strings.Contains(name, "/.") {
+		respondAssetNotFound(c, name)
+		return
+	}
+
+	c.Header("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	c.Header("Cache-Control", "public, max-age=3600")
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), f)
+}
+
+// respondAssetNotFound responds 404 for a missing asset
+func respondAssetNotFound(c *gin.Context, name string) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error":   "Asset not found",
+		"details": fmt.Sprintf("no asset at %s", name),
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler serves the study activity heatmap
+type CalendarHandler struct {
+	dashboardRepo    repository.DashboardRepository
+	studySessionRepo repository.StudySessionRepository
+	location         *time.Location
+}
+
+// NewCalendarHandler creates a new handler for the calendar, counting days in loc by default
+func NewCalendarHandler(dashboardRepo repository.DashboardRepository, studySessionRepo repository.StudySessionRepository, loc *time.Location) *CalendarHandler {
+	return &CalendarHandler{
+		dashboardRepo:    dashboardRepo,
+		studySessionRepo: studySessionRepo,
+		location:         loc,
+	}
+}
+
+// GetCalendar handles GET /api/v1/dashboard/calendar
+func (h *CalendarHandler) GetCalendar(c *gin.Context) {
+	loc, err := queryLocation(c, h.location)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timezone",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	year := time.Now().In(loc).Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		year, err = strconv.Atoi(yearStr)
+		if err != nil || year < 1970 || year > 9999 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid year",
+				"details": "year must be a number between 1970 and 9999",
+			})
+			return
+		}
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	days, err := h.dashboardRepo.GetCalendarDays(c.Request.Context(), from, from.AddDate(1, 0, 0), loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve calendar",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewCalendar(year, loc.String(), days))
+}
+
+// GetCalendarDay handles GET /api/v1/dashboard/calendar/:date
+func (h *CalendarHandler) GetCalendarDay(c *gin.Context) {
+	loc, err := queryLocation(c, h.location)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timezone",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	date, err := time.ParseInLocation(models.HistoryDateFormat, c.Param("date"), loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid date",
+			"details": "date must be formatted as YYYY-MM-DD",
+		})
+		return
+	}
+	next := date.AddDate(0, 0, 1)
+
+	days, err := h.dashboardRepo.GetCalendarDays(c.Request.Context(), date, next, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve calendar",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// The sessions of the day, in the order they were studied and paginated like the
+	// study session list
+	params := models.DefaultStudySessionQueryParams()
+	params.Order = models.OrderAsc
+	params.StartedFrom = date
+	params.StartedBefore = next
+	if page, err := strconv.Atoi(c.DefaultQuery("page", "1")); err == nil && page > 0 {
+		params.Page = page
+	}
+	if perPage, err := strconv.Atoi(c.DefaultQuery("sessions_per_page", "100")); err == nil && perPage > 0 {
+		params.PerPage = perPage
+	}
+	sessions, totalSessions, err := h.studySessionRepo.List(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve study sessions",
+			"details": err.Error(),
+		})
+		return
+	}
+	if sessions == nil {
+		sessions = []repository.StudySessionListItem{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":           days[0].Date,
+		"timezone":       loc.String(),
+		"reviews":        days[0].Reviews,
+		"sessions":       days[0].Sessions,
+		"items":          sessions,
+		"total_sessions": totalSessions,
+		"current_page":   params.Page,
+		"total_pages":    (totalSessions + params.PerPage - 1) / params.PerPage,
+	})
+}
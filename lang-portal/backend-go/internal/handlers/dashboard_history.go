@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lang-portal/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseHistoryParams reads from, to, bucket, group_id and study_activity_id
+func parseHistoryParams(c *gin.Context) (models.HistoryQueryParams, error) {
+	params := models.DefaultHistoryQueryParams(time.Now())
+	params.Bucket = c.DefaultQuery("bucket", params.Bucket)
+
+	if value := c.Query("to"); value != "" {
+		to, err := time.Parse(models.HistoryDateFormat, value)
+		if err != nil {
+			return params, fmt.Errorf("to must be a date formatted as YYYY-MM-DD")
+		}
+		// Keep the default 30 day window when only the end moves
+		if c.Query("from") == "" {
+			params.From = to.AddDate(0, 0, -29)
+		}
+		params.To = to
+	}
+	if value := c.Query("from"); value != "" {
+		from, err := time.Parse(models.HistoryDateFormat, value)
+		if err != nil {
+			return params, fmt.Errorf("from must be a date formatted as YYYY-MM-DD")
+		}
+		params.From = from
+	}
+
+	for name, target := range map[string]**int64{
+		"group_id":          &params.GroupID,
+		"study_activity_id": &params.StudyActivityID,
+	} {
+		if value := c.Query(name); value != "" {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return params, fmt.Errorf("%s must be a valid integer", name)
+			}
+			*target = &id
+		}
+	}
+
+	return params, params.Validate()
+}
+
+// historyResponse wraps a series with the range it covers
+func historyResponse(params models.HistoryQueryParams, items interface{}) gin.H {
+	from, to := params.Range()
+	return gin.H{
+		"bucket": params.Bucket,
+		"from":   from.Format(models.HistoryDateFormat),
+		"to":     to.Format(models.HistoryDateFormat),
+		"items":  items,
+	}
+}
+
+// GetReviewHistory handles GET /api/v1/dashboard/history/reviews
+func (h *DashboardHandler) GetReviewHistory(c *gin.Context) {
+	params, err := parseHistoryParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	points, err := h.dashboardRepo.GetReviewHistory(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve review history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, historyResponse(params, points))
+}
+
+// GetNewWordsHistory handles GET /api/v1/dashboard/history/new-words
+func (h *DashboardHandler) GetNewWordsHistory(c *gin.Context) {
+	params, err := parseHistoryParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	points, err := h.dashboardRepo.GetNewWordsHistory(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve new words history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, historyResponse(params, points))
+}
+
+// GetTimeSpentHistory handles GET /api/v1/dashboard/history/time-spent
+func (h *DashboardHandler) GetTimeSpentHistory(c *gin.Context) {
+	params, err := parseHistoryParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	points, err := h.dashboardRepo.GetTimeSpentHistory(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve time spent history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, historyResponse(params, points))
+}
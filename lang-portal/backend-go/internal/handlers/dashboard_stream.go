@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"lang-portal/internal/stream"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// Dashboard stream tuning
+const (
+	// streamHeartbeat keeps proxies from closing idle streams
+	streamHeartbeat = 15 * time.Second
+	// streamWriteTimeout drops clients that stop reading
+	streamWriteTimeout = 10 * time.Second
+	// streamRetry tells browsers how long to wait before reconnecting, in milliseconds
+	streamRetry = 3000
+)
+
+// DashboardStreamHandler pushes dashboard updates over Server-Sent Events
+type DashboardStreamHandler struct {
+	feed *stream.DashboardFeed
+}
+
+// NewDashboardStreamHandler creates a new handler for the dashboard stream
+func NewDashboardStreamHandler(feed *stream.DashboardFeed) *DashboardStreamHandler {
+	return &DashboardStreamHandler{feed: feed}
+}
+
+// StreamDashboard handles GET /api/v1/dashboard/stream
+func (h *DashboardStreamHandler) StreamDashboard(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	client, err := h.feed.Hub().Subscribe()
+	if err != nil {
+		message := "Too many dashboard streams"
+		if err == stream.ErrHubClosed {
+			message = "Server is shutting down"
+		}
+		c.Header("Retry-After", "30")
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+		return
+	}
+	defer h.feed.Hub().Unsubscribe(client)
+
+	// Start with the current state, so clients need not call the polling endpoints
+	snapshot, err := h.feed.Snapshot(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve dashboard",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	write := func(render func() error) bool {
+		// A failed deadline only means the server does not support it
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if err := render(); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+	send := func(messages []stream.Message) bool {
+		return write(func() error {
+			for _, message := range messages {
+				err := sse.Encode(c.Writer, sse.Event{
+					Id:    strconv.FormatUint(message.ID, 10),
+					Event: message.Event,
+					Retry: streamRetry,
+					Data:  message.Data,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	if !send(snapshot) {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.feed.Hub().Closed():
+			// The server is shutting down; browsers reconnect after the retry delay
+			return
+		case <-client.Ready():
+			if !send(client.Drain()) {
+				return
+			}
+		case <-heartbeat.C:
+			ok := write(func() error {
+				_, err := c.Writer.WriteString(": heartbeat\n\n")
+				return err
+			})
+			if !ok {
+				return
+			}
+		}
+	}
+}
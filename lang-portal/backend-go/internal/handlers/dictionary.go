@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"lang-portal/internal/repository"
+	"lang-portal/internal/vocab"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DictionaryHandler handles dictionary lookups and words created from dictionary entries
+type DictionaryHandler struct {
+	dictionaryRepo repository.DictionaryRepository
+	drafter        *vocab.Drafter
+}
+
+// NewDictionaryHandler creates a new handler for the dictionary
+func NewDictionaryHandler(dictionaryRepo repository.DictionaryRepository, drafter *vocab.Drafter) *DictionaryHandler {
+	return &DictionaryHandler{dictionaryRepo: dictionaryRepo, drafter: drafter}
+}
+
+// Lookup handles GET /api/v1/dictionary/lookup, searching by kanji, kana or English
+func (h *DictionaryHandler) Lookup(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	limit := repository.DefaultLookupLimit
+	err := queryInt(c, "limit", &limit)
+	if err == nil && query == "" {
+		err = fmt.Errorf("q is required")
+	}
+	if err == nil && (limit < 1 || limit > repository.MaxLookupLimit) {
+		err = fmt.Errorf("limit must be between 1 and %d", repository.MaxLookupLimit)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	entries, err := h.dictionaryRepo.Lookup(c.Request.Context(), query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to look up dictionary",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": entries,
+	})
+}
+
+// GetEntry handles GET /api/v1/dictionary/entries/:id
+func (h *DictionaryHandler) GetEntry(c *gin.Context) {
+	entryID, ok := parseEntryID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.dictionaryRepo.GetEntry(c.Request.Context(), entryID)
+	if err != nil {
+		respondDictionaryError(c, "Failed to retrieve dictionary entry", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// DraftWord handles GET /api/v1/dictionary/entries/:id/word, filling the fields of a word
+// from the entry for the word form without saving it
+func (h *DictionaryHandler) DraftWord(c *gin.Context) {
+	entryID, ok := parseEntryID(c)
+	if !ok {
+		return
+	}
+	var options vocab.DraftOptions
+	if err := c.ShouldBindQuery(&options); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	draft, err := h.drafter.Draft(c.Request.Context(), entryID, options)
+	if err != nil {
+		respondDictionaryError(c, "Failed to draft word", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
+// CreateWord handles POST /api/v1/dictionary/entries/:id/word, creating the word drafted
+// from the entry with the optional choices of the body
+func (h *DictionaryHandler) CreateWord(c *gin.Context) {
+	entryID, ok := parseEntryID(c)
+	if !ok {
+		return
+	}
+	var options vocab.DraftOptions
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&options); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	word, err := h.drafter.Create(c.Request.Context(), entryID, options)
+	if err != nil {
+		respondDictionaryError(c, "Failed to create word", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, word)
+}
+
+// parseEntryID reads the dictionary entry ID of the URL, responding when it is invalid
+func parseEntryID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid dictionary entry ID",
+			"details": "ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondDictionaryError maps dictionary errors to responses
+func respondDictionaryError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch msg := err.Error(); {
+	case strings.HasSuffix(msg, "not found"):
+		status = http.StatusNotFound
+	case strings.HasPrefix(msg, "word already exists"):
+		status = http.StatusConflict
+	case strings.HasPrefix(msg, "entry cannot be made into a word"):
+		status = http.StatusUnprocessableEntity
+	case strings.HasPrefix(msg, "kanji "), strings.HasPrefix(msg, "reading "), strings.HasPrefix(msg, "sense "):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
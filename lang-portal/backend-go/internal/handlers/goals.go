@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lang-portal/internal/goals"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultGoalHistoryPeriods is the number of periods of a goal history unless a request asks for more
+const defaultGoalHistoryPeriods = 14
+
+// GoalHandler handles HTTP requests related to learner goals
+type GoalHandler struct {
+	goalRepo repository.GoalRepository
+	service  *goals.Service
+}
+
+// NewGoalHandler creates a new handler for goals
+func NewGoalHandler(repo repository.GoalRepository, service *goals.Service) *GoalHandler {
+	return &GoalHandler{goalRepo: repo, service: service}
+}
+
+// goalRequest is the body of goal create and update requests; metric, period and learner
+// are only read on create, omitted fields are left unchanged on update
+type goalRequest struct {
+	LearnerID *int64 `json:"learner_id"`
+	Metric    string `json:"metric"`
+	Period    string `json:"period"`
+	Target    *int   `json:"target"`
+	Active    *bool  `json:"active"`
+}
+
+// apply copies the target and active flag present in the request onto a goal
+func (r *goalRequest) apply(g *models.Goal) {
+	if r.Target != nil {
+		g.Target = *r.Target
+	}
+	if r.Active != nil {
+		g.Active = *r.Active
+	}
+}
+
+// queryLearnerID returns the learner of the learner_id query parameter, or nil for the portal's own user
+func queryLearnerID(c *gin.Context) (*int64, error) {
+	value := c.Query("learner_id")
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("learner_id must be a valid integer")
+	}
+	return &id, nil
+}
+
+// goalScope reads the learner and timezone of a goal progress request, responding 400 when they are invalid
+func (h *GoalHandler) goalScope(c *gin.Context) (*int64, *time.Location, bool) {
+	learnerID, err := queryLearnerID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return nil, nil, false
+	}
+	loc, err := queryLocation(c, h.service.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timezone",
+			"details": err.Error(),
+		})
+		return nil, nil, false
+	}
+	return learnerID, loc, true
+}
+
+// ListGoals handles GET /api/v1/goals
+func (h *GoalHandler) ListGoals(c *gin.Context) {
+	learnerID, err := queryLearnerID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	list, err := h.goalRepo.ListGoals(c.Request.Context(), learnerID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve goals",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": list,
+	})
+}
+
+// GetGoal handles GET /api/v1/goals/:id
+func (h *GoalHandler) GetGoal(c *gin.Context) {
+	id, ok := parseGoalID(c)
+	if !ok {
+		return
+	}
+
+	goal, err := h.goalRepo.GetGoal(c.Request.Context(), id)
+	if err != nil {
+		respondGoalError(c, "Failed to retrieve goal", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// CreateGoal handles POST /api/v1/goals
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	var req goalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	goal := models.Goal{LearnerID: req.LearnerID, Metric: req.Metric, Period: req.Period, Active: true}
+	req.apply(&goal)
+	if err := goal.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid goal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.goalRepo.CreateGoal(c.Request.Context(), &goal); err != nil {
+		respondGoalError(c, "Failed to create goal", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+// UpdateGoal handles PATCH /api/v1/goals/:id
+func (h *GoalHandler) UpdateGoal(c *gin.Context) {
+	id, ok := parseGoalID(c)
+	if !ok {
+		return
+	}
+
+	var req goalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.LearnerID != nil || req.Metric != "" || req.Period != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid goal",
+			"details": "learner_id, metric and period cannot be changed, create a new goal instead",
+		})
+		return
+	}
+
+	goal, err := h.goalRepo.GetGoal(c.Request.Context(), id)
+	if err != nil {
+		respondGoalError(c, "Failed to update goal", err)
+		return
+	}
+	req.apply(goal)
+	if err := goal.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid goal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.goalRepo.UpdateGoal(c.Request.Context(), goal); err != nil {
+		respondGoalError(c, "Failed to update goal", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// DeleteGoal handles DELETE /api/v1/goals/:id
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+	id, ok := parseGoalID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.goalRepo.DeleteGoal(c.Request.Context(), id); err != nil {
+		respondGoalError(c, "Failed to delete goal", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetGoalProgress handles GET /api/v1/goals/progress
+func (h *GoalHandler) GetGoalProgress(c *gin.Context) {
+	learnerID, loc, ok := h.goalScope(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.service.Progress(c.Request.Context(), learnerID, loc, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve goal progress",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timezone": loc.String(),
+		"items":    progress,
+	})
+}
+
+// GetGoalHistory handles GET /api/v1/goals/history
+func (h *GoalHandler) GetGoalHistory(c *gin.Context) {
+	learnerID, loc, ok := h.goalScope(c)
+	if !ok {
+		return
+	}
+
+	periods := defaultGoalHistoryPeriods
+	if err := queryInt(c, "periods", &periods); err != nil || periods < 1 || periods > models.MaxGoalHistoryPeriods {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": fmt.Sprintf("periods must be between 1 and %d", models.MaxGoalHistoryPeriods),
+		})
+		return
+	}
+
+	histories, err := h.service.History(c.Request.Context(), learnerID, loc, time.Now(), periods)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve goal history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timezone": loc.String(),
+		"items":    histories,
+	})
+}
+
+// GetDashboardGoals handles GET /api/v1/dashboard/goals
+func (h *GoalHandler) GetDashboardGoals(c *gin.Context) {
+	loc, err := queryLocation(c, h.service.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timezone",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	dashboard, err := h.service.Dashboard(c.Request.Context(), loc, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve goals",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// parseGoalID reads the goal ID from the URL, responding 400 when it is invalid
+func parseGoalID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid goal ID",
+			"details": "ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondGoalError maps goal repository errors to responses
+func respondGoalError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch err.Error() {
+	case "goal not found", "learner not found":
+		status = http.StatusNotFound
+	case "goal already exists":
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
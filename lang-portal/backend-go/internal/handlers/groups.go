@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"lang-portal/internal/models"
 	"lang-portal/internal/repository"
 
 	"github.com/gin-gonic/gin"
@@ -35,8 +38,22 @@ func (h *GroupHandler) GetGroups(c *gin.Context) {
 		groupsPerPage = 100
 	}
 
+	// Parse sorting parameters
+	params := models.DefaultGroupQueryParams()
+	params.Page = page
+	params.PerPage = groupsPerPage
+	params.SortBy = c.DefaultQuery("sort_by", params.SortBy)
+	params.Order = c.DefaultQuery("order", params.Order)
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Fetch groups
-	groups, totalGroups, err := h.groupRepo.List(c.Request.Context(), page, groupsPerPage)
+	groups, totalGroups, err := h.groupRepo.List(c.Request.Context(), params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to retrieve groups",
@@ -110,8 +127,47 @@ func (h *GroupHandler) GetGroupWords(c *gin.Context) {
 		wordsPerPage = 100
 	}
 
+	// Parse sorting and filter parameters
+	params := models.DefaultGroupWordQueryParams()
+	params.Page = page
+	params.PerPage = wordsPerPage
+	params.SortBy = c.DefaultQuery("sort_by", params.SortBy)
+	params.Order = c.DefaultQuery("order", params.Order)
+
+	if maxAccuracyStr := c.Query("max_accuracy"); maxAccuracyStr != "" {
+		maxAccuracy, err := strconv.ParseFloat(maxAccuracyStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid max_accuracy",
+				"details": "max_accuracy must be a number",
+			})
+			return
+		}
+		params.MaxAccuracy = &maxAccuracy
+	}
+
+	if neverReviewedStr := c.Query("never_reviewed"); neverReviewedStr != "" {
+		neverReviewed, err := strconv.ParseBool(neverReviewedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid never_reviewed",
+				"details": "never_reviewed must be a boolean",
+			})
+			return
+		}
+		params.NeverReviewed = neverReviewed
+	}
+
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Fetch group words
-	words, totalWords, err := h.groupRepo.GetGroupWords(c.Request.Context(), groupID, page, wordsPerPage)
+	words, totalWords, err := h.groupRepo.GetGroupWords(c.Request.Context(), groupID, params)
 	if err != nil {
 		if err.Error() == "group not found" {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -236,3 +292,204 @@ func (h *GroupHandler) GetGroupWordsRaw(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// groupRequest represents the body for creating or renaming a group
+type groupRequest struct {
+	Name    string  `json:"name" binding:"required"`
+	WordIDs []int64 `json:"word_ids"`
+}
+
+// groupWordsRequest represents the body for bulk adding or removing group words
+type groupWordsRequest struct {
+	WordIDs []int64 `json:"word_ids" binding:"required,min=1"`
+}
+
+// CreateGroup handles POST /api/v1/groups
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	var req groupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Group name is required",
+		})
+		return
+	}
+
+	group, err := h.groupRepo.Create(c.Request.Context(), name, req.WordIDs)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "words not found") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid word IDs",
+				"details": err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to create group",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// UpdateGroup handles PATCH /api/v1/groups/:id
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	// Parse group ID from URL
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid group ID",
+			"details": "Group ID must be a valid integer",
+		})
+		return
+	}
+
+	var req groupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Group name is required",
+		})
+		return
+	}
+
+	group, err := h.groupRepo.Rename(c.Request.Context(), groupID, name)
+	if err != nil {
+		if err.Error() == "group not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Group not found",
+				"details": err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to update group",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup handles DELETE /api/v1/groups/:id
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	// Parse group ID from URL
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid group ID",
+			"details": "Group ID must be a valid integer",
+		})
+		return
+	}
+
+	if err := h.groupRepo.Delete(c.Request.Context(), groupID); err != nil {
+		if err.Error() == "group not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Group not found",
+				"details": err.Error(),
+			})
+		} else if err.Error() == "group has study sessions" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Failed to delete group",
+				"details": err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to delete group",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddGroupWords handles POST /api/v1/groups/:id/words
+func (h *GroupHandler) AddGroupWords(c *gin.Context) {
+	h.changeGroupWords(c, "added", h.groupRepo.AddWords)
+}
+
+// RemoveGroupWords handles DELETE /api/v1/groups/:id/words
+func (h *GroupHandler) RemoveGroupWords(c *gin.Context) {
+	h.changeGroupWords(c, "removed", h.groupRepo.RemoveWords)
+}
+
+// changeGroupWords applies a bulk word change to a group and reports the new word count
+func (h *GroupHandler) changeGroupWords(c *gin.Context, verb string, change func(ctx context.Context, groupID int64, wordIDs []int64) (int, error)) {
+	// Parse group ID from URL
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid group ID",
+			"details": "Group ID must be a valid integer",
+		})
+		return
+	}
+
+	var req groupWordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	changed, err := change(c.Request.Context(), groupID, req.WordIDs)
+	if err != nil {
+		switch {
+		case err.Error() == "group not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Group not found",
+				"details": err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "words not found"):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid word IDs",
+				"details": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to update group words",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	group, err := h.groupRepo.GetByID(c.Request.Context(), groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve group",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group_id":         groupID,
+		verb:               changed,
+		"total_word_count": group.TotalWordCount,
+	})
+}
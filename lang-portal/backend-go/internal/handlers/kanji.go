@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"lang-portal/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KanjiHandler handles the kanji dictionary and the kanji of words
+type KanjiHandler struct {
+	kanjiRepo repository.KanjiRepository
+}
+
+// NewKanjiHandler creates a new handler for kanji
+func NewKanjiHandler(kanjiRepo repository.KanjiRepository) *KanjiHandler {
+	return &KanjiHandler{kanjiRepo: kanjiRepo}
+}
+
+// GetKanji handles GET /api/v1/kanji/:char, listing the words using the kanji
+func (h *KanjiHandler) GetKanji(c *gin.Context) {
+	literal := c.Param("char")
+	if utf8.RuneCountInString(literal) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid kanji",
+			"details": "char must be a single character",
+		})
+		return
+	}
+
+	kanji, err := h.kanjiRepo.Get(c.Request.Context(), literal)
+	if err != nil {
+		respondKanjiError(c, "Failed to retrieve kanji", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, kanji)
+}
+
+// GetWordKanji handles GET /api/v1/words/:id/kanji
+func (h *KanjiHandler) GetWordKanji(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	kanji, err := h.kanjiRepo.ListWordKanji(c.Request.Context(), wordID)
+	if err != nil {
+		respondKanjiError(c, "Failed to retrieve word kanji", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": kanji,
+	})
+}
+
+// respondKanjiError maps kanji errors to responses
+func respondKanjiError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	if strings.HasSuffix(err.Error(), "not found") {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lang-portal/internal/launch"
+	"lang-portal/internal/middleware"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LaunchHandler handles launching study activities and their token-authenticated callbacks
+type LaunchHandler struct {
+	studyActivityRepo repository.StudyActivityRepository
+	groupRepo         repository.GroupRepository
+	studySessionRepo  repository.StudySessionRepository
+	signer            *launch.Signer
+	callbackURL       string
+}
+
+// NewLaunchHandler creates a new handler for activity launches.
+// publicBaseURL is the externally reachable address of the server.
+func NewLaunchHandler(
+	studyActivityRepo repository.StudyActivityRepository,
+	groupRepo repository.GroupRepository,
+	studySessionRepo repository.StudySessionRepository,
+	signer *launch.Signer,
+	publicBaseURL string,
+) *LaunchHandler {
+	return &LaunchHandler{
+		studyActivityRepo: studyActivityRepo,
+		groupRepo:         groupRepo,
+		studySessionRepo:  studySessionRepo,
+		signer:            signer,
+		callbackURL:       strings.TrimRight(publicBaseURL, "/") + "/api/v1/launch",
+	}
+}
+
+// LaunchStudyActivity handles POST /api/v1/study-activities/:id/launch
+func (h *LaunchHandler) LaunchStudyActivity(c *gin.Context) {
+	// Parse activity ID from URL
+	activityID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid study activity ID",
+			"details": "ID must be a valid integer",
+		})
+		return
+	}
+
+	// Define request body struct
+	type LaunchRequest struct {
+		GroupID int64 `json:"group_id" binding:"required"`
+	}
+
+	var req LaunchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Validate the activity and group
+	activity, err := h.studyActivityRepo.GetByID(c.Request.Context(), activityID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Study activity not found",
+			"details": err.Error(),
+		})
+		return
+	}
+	if activity.RetiredAt != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Study activity has been retired",
+		})
+		return
+	}
+
+	group, err := h.groupRepo.GetByID(c.Request.Context(), req.GroupID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Group not found",
+			"details": err.Error(),
+		})
+		return
+	}
+	if group.TotalWordCount == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Group has no words to study",
+		})
+		return
+	}
+
+	// Refuse bad templates before a session is created that nobody could open
+	if err := launch.ValidateTemplate(activity.URL); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Study activity has an invalid launch URL",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Create the study session
+	session := &models.StudySession{
+		GroupID:         group.ID,
+		StudyActivityID: activity.ID,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if err := h.studySessionRepo.Create(c.Request.Context(), session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create study session",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Sign a token the activity can use to report back
+	token, expiresAt, err := h.signer.Sign(session.ID, session.GroupID, session.StudyActivityID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to sign launch token",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	launchURL, err := launch.BuildURL(activity.URL, launch.URLParams{
+		GroupID:        session.GroupID,
+		StudySessionID: session.ID,
+		CallbackURL:    h.callbackURL,
+		Token:          token,
+	})
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Study activity has an invalid launch URL",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"study_session_id":  session.ID,
+		"group_id":          session.GroupID,
+		"study_activity_id": session.StudyActivityID,
+		"launch_url":        launchURL,
+		"callback_url":      h.callbackURL,
+		"token":             token,
+		"expires_at":        expiresAt.Format(time.RFC3339),
+	})
+}
+
+// GetLaunchSession handles GET /api/v1/launch/session, returning the launched session and its words
+func (h *LaunchHandler) GetLaunchSession(c *gin.Context) {
+	claims := c.MustGet(middleware.LaunchClaimsKey).(*launch.Claims)
+
+	group, err := h.groupRepo.GetByID(c.Request.Context(), claims.GroupID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Group not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	words, err := h.groupRepo.GetGroupWordsRaw(c.Request.Context(), claims.GroupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve group words",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"study_session_id":  claims.StudySessionID,
+		"study_activity_id": claims.StudyActivityID,
+		"group_id":          group.ID,
+		"group_name":        group.Name,
+		"words":             words,
+		"expires_at":        time.Unix(claims.ExpiresAt, 0).UTC().Format(time.RFC3339),
+	})
+}
+
+// CreateLaunchReview handles POST /api/v1/launch/reviews, recording a review for the launched session
+func (h *LaunchHandler) CreateLaunchReview(c *gin.Context) {
+	claims := c.MustGet(middleware.LaunchClaimsKey).(*launch.Claims)
+
+	// Define request body struct
+	type LaunchReviewRequest struct {
+		WordID  int64 `json:"word_id" binding:"required"`
+		Correct bool  `json:"correct"`
+	}
+
+	var req LaunchReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Only words of the launched group can be reviewed
+	inGroup, err := h.groupRepo.HasWord(c.Request.Context(), claims.GroupID, req.WordID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to validate word",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !inGroup {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Word does not belong to the launched group",
+		})
+		return
+	}
+
+	review := &models.WordReviewItem{
+		WordID:         req.WordID,
+		StudySessionID: claims.StudySessionID,
+		Correct:        req.Correct,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := h.studySessionRepo.CreateWordReview(c.Request.Context(), review); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create word review",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":          true,
+		"word_id":          review.WordID,
+		"study_session_id": review.StudySessionID,
+		"correct":          review.Correct,
+		"created_at":       review.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+// CompleteLaunchSession handles POST /api/v1/launch/complete, finishing the launched session
+func (h *LaunchHandler) CompleteLaunchSession(c *gin.Context) {
+	claims := c.MustGet(middleware.LaunchClaimsKey).(*launch.Claims)
+
+	session, err := h.studySessionRepo.Complete(c.Request.Context(), claims.StudySessionID)
+	if err != nil {
+		respondCompleteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
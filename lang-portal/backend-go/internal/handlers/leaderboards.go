@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lang-portal/internal/leaderboard"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeaderboardHandler handles HTTP requests related to leaderboards and learner privacy
+type LeaderboardHandler struct {
+	leaderboardRepo repository.LeaderboardRepository
+	service         *leaderboard.Service
+}
+
+// NewLeaderboardHandler creates a new handler for leaderboards
+func NewLeaderboardHandler(repo repository.LeaderboardRepository, service *leaderboard.Service) *LeaderboardHandler {
+	return &LeaderboardHandler{leaderboardRepo: repo, service: service}
+}
+
+// GetLeaderboard handles GET /api/v1/leaderboards
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	params := models.DefaultLeaderboardQueryParams()
+	if metric := c.Query("metric"); metric != "" {
+		params.Metric = metric
+	}
+	if period := c.Query("period"); period != "" {
+		params.Period = period
+	}
+	for name, target := range map[string]*int{
+		"limit":       &params.Limit,
+		"min_reviews": &params.MinReviews,
+	} {
+		if err := queryInt(c, name, target); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+	if value := c.Query("class_id"); value != "" {
+		classID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": "class_id must be a valid integer",
+			})
+			return
+		}
+		params.ClassID = &classID
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if params.ClassID != nil {
+		if _, err := h.leaderboardRepo.GetClass(c.Request.Context(), *params.ClassID); err != nil {
+			respondLeaderboardError(c, "Failed to retrieve leaderboard", err)
+			return
+		}
+	}
+
+	board, err := h.service.Leaderboard(c.Request.Context(), params, time.Now())
+	if err != nil {
+		respondLeaderboardError(c, "Failed to retrieve leaderboard", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// ListClasses handles GET /api/v1/leaderboards/classes
+func (h *LeaderboardHandler) ListClasses(c *gin.Context) {
+	classes, err := h.leaderboardRepo.ListClasses(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve classes",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": classes,
+	})
+}
+
+// GetLearnerPrivacy handles GET /api/v1/learners/:id/privacy
+func (h *LeaderboardHandler) GetLearnerPrivacy(c *gin.Context) {
+	id, ok := parseLearnerID(c)
+	if !ok {
+		return
+	}
+
+	privacy, err := h.leaderboardRepo.GetPrivacy(c.Request.Context(), id)
+	if err != nil {
+		respondLeaderboardError(c, "Failed to retrieve learner privacy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, privacy)
+}
+
+// UpdateLearnerPrivacy handles PATCH /api/v1/learners/:id/privacy
+func (h *LeaderboardHandler) UpdateLearnerPrivacy(c *gin.Context) {
+	id, ok := parseLearnerID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		DisplayName       *string `json:"display_name"`
+		LeaderboardOptOut *bool   `json:"leaderboard_opt_out"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	privacy, err := h.leaderboardRepo.GetPrivacy(c.Request.Context(), id)
+	if err != nil {
+		respondLeaderboardError(c, "Failed to update learner privacy", err)
+		return
+	}
+	if req.DisplayName != nil {
+		privacy.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.LeaderboardOptOut != nil {
+		privacy.LeaderboardOptOut = *req.LeaderboardOptOut
+	}
+	if err := privacy.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid privacy settings",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.leaderboardRepo.UpdatePrivacy(c.Request.Context(), privacy); err != nil {
+		respondLeaderboardError(c, "Failed to update learner privacy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, privacy)
+}
+
+// parseLearnerID reads the learner ID from the URL, responding 400 when it is invalid
+func parseLearnerID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid learner ID",
+			"details": "ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondLeaderboardError maps leaderboard repository errors to responses
+func respondLeaderboardError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch err.Error() {
+	case "class not found", "learner not found":
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeechHandler handles HTTP requests about frequently failed words
+type LeechHandler struct {
+	leechRepo repository.LeechRepository
+	threshold int
+}
+
+// NewLeechHandler creates a new handler for leeches, flagging words at threshold lapses by default
+func NewLeechHandler(repo repository.LeechRepository, threshold int) *LeechHandler {
+	return &LeechHandler{leechRepo: repo, threshold: threshold}
+}
+
+// queryInt reads an optional integer query parameter into target
+func queryInt(c *gin.Context, name string, target *int) error {
+	value := c.Query(name)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s must be a valid integer", name)
+	}
+	*target = n
+	return nil
+}
+
+// GetLeeches handles GET /api/v1/words/leeches
+func (h *LeechHandler) GetLeeches(c *gin.Context) {
+	params := models.DefaultLeechQueryParams(h.threshold)
+	for name, target := range map[string]*int{
+		"page":      &params.Page,
+		"per_page":  &params.PerPage,
+		"threshold": &params.Threshold,
+		"recent":    &params.Recent,
+	} {
+		if err := queryInt(c, name, target); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PerPage < 1 {
+		params.PerPage = 50
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	words, total, err := h.leechRepo.ListLeeches(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve leeches",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":        words,
+		"threshold":    params.Threshold,
+		"total_count":  total,
+		"current_page": params.Page,
+		"total_pages":  (total + params.PerPage - 1) / params.PerPage,
+	})
+}
+
+// GetGroupProblemWords handles GET /api/v1/groups/:id/problem-words
+func (h *LeechHandler) GetGroupProblemWords(c *gin.Context) {
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid group ID",
+			"details": "Group ID must be a valid integer",
+		})
+		return
+	}
+
+	params := models.DefaultProblemWordQueryParams(h.threshold)
+	for name, target := range map[string]*int{
+		"limit":       &params.Limit,
+		"min_reviews": &params.MinReviews,
+		"recent":      &params.Recent,
+	} {
+		if err := queryInt(c, name, target); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	words, err := h.leechRepo.GetGroupProblemWords(c.Request.Context(), groupID, params)
+	if err != nil {
+		if err.Error() == "group not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Group not found",
+				"details": err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retrieve problem words",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group_id": groupID,
+		"recent":   params.Recent,
+		"items":    words,
+	})
+}
+
+// MoveLeechesToNeedsWork handles POST /api/v1/words/leeches/needs-work
+func (h *LeechHandler) MoveLeechesToNeedsWork(c *gin.Context) {
+	threshold := h.threshold
+	if err := queryInt(c, "threshold", &threshold); err != nil || threshold < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": "threshold must be a positive integer",
+		})
+		return
+	}
+
+	ids, err := h.leechRepo.LeechIDs(c.Request.Context(), threshold)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve leeches",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	group, added, err := h.leechRepo.AddToNeedsWork(c.Request.Context(), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to add leeches to group",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group":   group,
+		"leeches": len(ids),
+		"added":   added,
+	})
+}
@@ -0,0 +1,507 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lang-portal/internal/launch"
+	"lang-portal/internal/lti"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ltiStateTTL bounds the time between an OIDC login and the launch it answers
+const ltiStateTTL = 10 * time.Minute
+
+// LTIHandler handles LTI 1.3 logins, launches, platform registrations and grade passback
+type LTIHandler struct {
+	ltiRepo           repository.LTIRepository
+	studyActivityRepo repository.StudyActivityRepository
+	groupRepo         repository.GroupRepository
+	signer            *launch.Signer
+	key               *lti.ToolKey
+	ags               *lti.AGSClient
+	baseURL           string
+	callbackURL       string
+}
+
+// NewLTIHandler creates a new handler for LTI 1.3.
+// publicBaseURL is the externally reachable address of the server.
+func NewLTIHandler(
+	ltiRepo repository.LTIRepository,
+	studyActivityRepo repository.StudyActivityRepository,
+	groupRepo repository.GroupRepository,
+	signer *launch.Signer,
+	key *lti.ToolKey,
+	publicBaseURL string,
+) *LTIHandler {
+	base := strings.TrimRight(publicBaseURL, "/")
+	return &LTIHandler{
+		ltiRepo:           ltiRepo,
+		studyActivityRepo: studyActivityRepo,
+		groupRepo:         groupRepo,
+		signer:            signer,
+		key:               key,
+		ags:               lti.NewAGSClient(key),
+		baseURL:           base,
+		callbackURL:       base + "/api/v1/launch",
+	}
+}
+
+// Login handles GET/POST /lti/login, answering a platform's OIDC login initiation
+func (h *LTIHandler) Login(c *gin.Context) {
+	var req lti.LoginRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid login request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	platform, err := h.ltiRepo.FindPlatform(c.Request.Context(), req.Issuer, req.ClientID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "LTI platform not found" || strings.HasPrefix(err.Error(), "client_id is required") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Unknown LTI platform",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.DeploymentID != "" && req.DeploymentID != platform.DeploymentID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unknown LTI deployment",
+		})
+		return
+	}
+
+	// The state ties the launch to this login, the nonce ties the id token to it
+	state, err := lti.RandomString()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to start LTI login",
+			"details": err.Error(),
+		})
+		return
+	}
+	nonce, err := lti.RandomString()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to start LTI login",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := h.ltiRepo.SaveState(c.Request.Context(), state, nonce, platform.ID, time.Now().Add(ltiStateTTL)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to start LTI login",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	authURL, err := lti.AuthRequestURL(platform.AuthLoginURL, platform.ClientID, h.baseURL+"/lti/launch", req, state, nonce)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to start LTI login",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Launch handles POST /lti/launch, validating the id token and starting a study session
+func (h *LTIHandler) Launch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Define form body struct
+	type LaunchForm struct {
+		IDToken string `form:"id_token" binding:"required"`
+		State   string `form:"state" binding:"required"`
+	}
+
+	var form LaunchForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid launch request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	nonce, platformID, err := h.ltiRepo.ConsumeState(ctx, form.State)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasSuffix(err.Error(), "LTI state") {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{
+			"error":   "Invalid launch state",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	platform, err := h.ltiRepo.GetPlatform(ctx, platformID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unknown LTI platform",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	keys := lti.KeySource{PublicKeyPEM: platform.PublicKeyPEM, JWKSURL: platform.JWKSURL}
+	claims, err := lti.ParseLaunch(ctx, form.IDToken, keys, platform.Issuer, platform.ClientID, platform.DeploymentID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid id token",
+			"details": err.Error(),
+		})
+		return
+	}
+	if claims.Nonce != nonce {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Id token nonce does not match the login",
+		})
+		return
+	}
+	if err := h.ltiRepo.UseNonce(ctx, platform.ID, claims.Nonce, claims.ExpiresAt.Time); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "nonce already used" {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{
+			"error":   "Id token rejected",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// The resource link chooses the activity; the group comes from the link or the course
+	activityID, err := strconv.ParseInt(claims.Custom["study_activity_id"], 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Resource link is missing the study_activity_id custom parameter",
+			"details": "study_activity_id must be a valid integer",
+		})
+		return
+	}
+	activity, err := h.studyActivityRepo.GetByID(ctx, activityID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Study activity not found",
+			"details": err.Error(),
+		})
+		return
+	}
+	if activity.RetiredAt != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Study activity has been retired",
+		})
+		return
+	}
+
+	var groupID int64
+	switch {
+	case claims.Custom["group_id"] != "":
+		if groupID, err = strconv.ParseInt(claims.Custom["group_id"], 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid group_id custom parameter",
+				"details": "group_id must be a valid integer",
+			})
+			return
+		}
+	case claims.Context != nil && claims.Context.ID != "":
+		title := claims.Context.Title
+		if title == "" {
+			title = claims.Context.Label
+		}
+		if groupID, err = h.ltiRepo.LinkContext(ctx, platform.ID, claims.Context.ID, title); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to map LTI context to a group",
+				"details": err.Error(),
+			})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Launch has neither a group_id custom parameter nor a context",
+		})
+		return
+	}
+
+	group, err := h.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Group not found",
+			"details": err.Error(),
+		})
+		return
+	}
+	if group.TotalWordCount == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":    "Group has no words to study",
+			"group_id": group.ID,
+		})
+		return
+	}
+
+	learnerID, err := h.ltiRepo.LinkLearner(ctx, platform.ID, claims.Subject, claims.Name, claims.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to map LTI user to a learner",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Refuse bad templates before a session is created that nobody could open
+	if err := launch.ValidateTemplate(activity.URL); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Study activity has an invalid launch URL",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	session := &models.StudySession{
+		GroupID:         group.ID,
+		StudyActivityID: activity.ID,
+		LearnerID:       &learnerID,
+		CreatedAt:       time.Now().UTC(),
+	}
+	ltiLaunch := &models.LTILaunch{
+		PlatformID: platform.ID,
+		Subject:    claims.Subject,
+	}
+	if claims.Endpoint.CanPublishScores() {
+		ltiLaunch.LineItemURL = claims.Endpoint.LineItem
+	}
+	if err := h.ltiRepo.CreateLaunchSession(ctx, session, ltiLaunch); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create study session",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	token, _, err := h.signer.Sign(session.ID, session.GroupID, session.StudyActivityID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to sign launch token",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	launchURL, err := launch.BuildURL(activity.URL, launch.URLParams{
+		GroupID:        session.GroupID,
+		StudySessionID: session.ID,
+		CallbackURL:    h.callbackURL,
+		Token:          token,
+	})
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Study activity has an invalid launch URL",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// 303 turns the platform's form post into a GET of the activity
+	c.Redirect(http.StatusSeeOther, launchURL)
+}
+
+// GetJWKS handles GET /lti/jwks, publishing the tool's public key
+func (h *LTIHandler) GetJWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.key.JWKS())
+}
+
+// ListPlatforms handles GET /api/v1/lti/platforms
+func (h *LTIHandler) ListPlatforms(c *gin.Context) {
+	platforms, err := h.ltiRepo.ListPlatforms(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve LTI platforms",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": platforms,
+		"tool": gin.H{
+			"login_url":    h.baseURL + "/lti/login",
+			"redirect_uri": h.baseURL + "/lti/launch",
+			"jwks_url":     h.baseURL + "/lti/jwks",
+		},
+	})
+}
+
+// CreatePlatform handles POST /api/v1/lti/platforms
+func (h *LTIHandler) CreatePlatform(c *gin.Context) {
+	var platform models.LTIPlatform
+	if err := c.ShouldBindJSON(&platform); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := platform.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid LTI platform",
+			"details": err.Error(),
+		})
+		return
+	}
+	if platform.PublicKeyPEM != "" {
+		if _, err := lti.ParsePublicKeyPEM(platform.PublicKeyPEM); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid LTI platform",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.ltiRepo.CreatePlatform(c.Request.Context(), &platform); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "LTI platform already registered" {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to register LTI platform",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, platform)
+}
+
+// DeletePlatform handles DELETE /api/v1/lti/platforms/:id
+func (h *LTIHandler) DeletePlatform(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid LTI platform ID",
+			"details": "ID must be a valid integer",
+		})
+		return
+	}
+
+	if err := h.ltiRepo.DeletePlatform(c.Request.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "LTI platform not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to delete LTI platform",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PublishScore handles POST /api/v1/lti/sessions/:id/score, sending the session score to the platform gradebook
+func (h *LTIHandler) PublishScore(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid study session ID",
+			"details": "ID must be a valid integer",
+		})
+		return
+	}
+
+	ltiLaunch, err := h.ltiRepo.GetLaunch(ctx, sessionID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "study session was not launched through LTI" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to retrieve LTI launch",
+			"details": err.Error(),
+		})
+		return
+	}
+	if ltiLaunch.LineItemURL == "" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Platform did not grant score passback for this launch",
+		})
+		return
+	}
+
+	correct, total, completed, err := h.ltiRepo.SessionScore(ctx, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to compute session score",
+			"details": err.Error(),
+		})
+		return
+	}
+	if total == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Study session has no reviews to score",
+		})
+		return
+	}
+
+	platform, err := h.ltiRepo.GetPlatform(ctx, ltiLaunch.PlatformID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve LTI platform",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	now := time.Now().UTC()
+	score := lti.Score{
+		UserID:           ltiLaunch.Subject,
+		ScoreGiven:       float64(correct),
+		ScoreMaximum:     float64(total),
+		ActivityProgress: lti.ActivityInProgress,
+		GradingProgress:  lti.GradingFullyGraded,
+		Timestamp:        now.Format(time.RFC3339),
+	}
+	if completed {
+		score.ActivityProgress = lti.ActivityCompleted
+	}
+
+	if err := h.ags.PublishScore(ctx, platform.ClientID, platform.AuthTokenURL, ltiLaunch.LineItemURL, score); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Failed to publish score to the platform",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := h.ltiRepo.MarkScorePublished(ctx, sessionID, now); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Score published but not recorded",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"study_session_id": sessionID,
+		"score":            score,
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"lang-portal/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MasteryHandler handles HTTP requests about word mastery
+type MasteryHandler struct {
+	masteryRepo repository.MasteryRepository
+}
+
+// NewMasteryHandler creates a new handler for mastery
+func NewMasteryHandler(repo repository.MasteryRepository) *MasteryHandler {
+	return &MasteryHandler{masteryRepo: repo}
+}
+
+// GetGroupMastery handles GET /api/v1/groups/:id/mastery
+func (h *MasteryHandler) GetGroupMastery(c *gin.Context) {
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid group ID",
+			"details": "Group ID must be a valid integer",
+		})
+		return
+	}
+
+	mastery, err := h.masteryRepo.GetGroupMastery(c.Request.Context(), groupID)
+	if err != nil {
+		if err.Error() == "group not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Group not found",
+				"details": err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retrieve group mastery",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, mastery)
+}
+
+// GetMasteryBreakdown handles GET /api/v1/dashboard/mastery
+func (h *MasteryHandler) GetMasteryBreakdown(c *gin.Context) {
+	breakdown, err := h.masteryRepo.GetMasteryBreakdown(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve mastery",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"lang-portal/internal/media"
+	"lang-portal/internal/tts"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is the room left for multipart headers around an uploaded file
+const multipartOverhead = 1 << 20
+
+// MediaHandler handles the audio and images of words and study activities and serves stored media
+type MediaHandler struct {
+	service *media.Service
+}
+
+// NewMediaHandler creates a new handler for media
+func NewMediaHandler(service *media.Service) *MediaHandler {
+	return &MediaHandler{service: service}
+}
+
+// GetWordAudio handles GET /api/v1/words/:id/audio
+func (h *MediaHandler) GetWordAudio(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	audio, err := h.service.GetWordAudio(c.Request.Context(), wordID)
+	if err != nil {
+		respondMediaError(c, "Failed to retrieve word audio", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audio)
+}
+
+// UploadWordAudio handles PUT /api/v1/words/:id/audio with the recording in the
+// "file" field of a multipart form
+func (h *MediaHandler) UploadWordAudio(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	file, ok := h.formFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	audio, err := h.service.UploadWordAudio(c.Request.Context(), wordID, file)
+	if err != nil {
+		respondMediaError(c, "Failed to upload word audio", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audio)
+}
+
+// GenerateWordAudio handles POST /api/v1/words/:id/audio/generate, speaking the word
+// or the optional text of the body
+func (h *MediaHandler) GenerateWordAudio(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Text string `json:"text"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	audio, err := h.service.GenerateWordAudio(c.Request.Context(), wordID, req.Text)
+	if err != nil {
+		respondMediaError(c, "Failed to generate word audio", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audio)
+}
+
+// DeleteWordAudio handles DELETE /api/v1/words/:id/audio
+func (h *MediaHandler) DeleteWordAudio(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteWordAudio(c.Request.Context(), wordID); err != nil {
+		respondMediaError(c, "Failed to delete word audio", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWordImage handles GET /api/v1/words/:id/image
+func (h *MediaHandler) GetWordImage(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	image, err := h.service.GetWordImage(c.Request.Context(), wordID)
+	if err != nil {
+		respondMediaError(c, "Failed to retrieve word image", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// UploadWordImage handles PUT /api/v1/words/:id/image with the image in the "file"
+// field of a multipart form
+func (h *MediaHandler) UploadWordImage(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+	file, ok := h.formFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	image, err := h.service.UploadWordImage(c.Request.Context(), wordID, file)
+	if err != nil {
+		respondMediaError(c, "Failed to upload word image", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// DeleteWordImage handles DELETE /api/v1/words/:id/image
+func (h *MediaHandler) DeleteWordImage(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteWordImage(c.Request.Context(), wordID); err != nil {
+		respondMediaError(c, "Failed to delete word image", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetStudyActivityThumbnail handles GET /api/v1/study-activities/:id/thumbnail
+func (h *MediaHandler) GetStudyActivityThumbnail(c *gin.Context) {
+	activityID, ok := parseStudyActivityID(c)
+	if !ok {
+		return
+	}
+
+	image, err := h.service.GetStudyActivityImage(c.Request.Context(), activityID)
+	if err != nil {
+		respondMediaError(c, "Failed to retrieve study activity thumbnail", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// UploadStudyActivityThumbnail handles PUT /api/v1/study-activities/:id/thumbnail with the
+// image in the "file" field of a multipart form; the activity's preview_url points at it
+func (h *MediaHandler) UploadStudyActivityThumbnail(c *gin.Context) {
+	activityID, ok := parseStudyActivityID(c)
+	if !ok {
+		return
+	}
+	file, ok := h.formFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	image, err := h.service.UploadStudyActivityImage(c.Request.Context(), activityID, file)
+	if err != nil {
+		respondMediaError(c, "Failed to upload study activity thumbnail", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// DeleteStudyActivityThumbnail handles DELETE /api/v1/study-activities/:id/thumbnail
+func (h *MediaHandler) DeleteStudyActivityThumbnail(c *gin.Context) {
+	activityID, ok := parseStudyActivityID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteStudyActivityImage(c.Request.Context(), activityID); err != nil {
+		respondMediaError(c, "Failed to delete study activity thumbnail", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMedia handles GET /api/v1/media/:hash. Files never change under their hash, so they
+// are cached for good; range requests let players seek.
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	file, f, err := h.service.Open(c.Request.Context(), c.Param("hash"))
+	if err != nil {
+		respondMediaError(c, "Failed to retrieve media", err)
+		return
+	}
+	defer f.Close()
+
+	c.Header("Content-Type", file.ContentType)
+	c.Header("ETag", `"`+file.Hash+`"`)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(c.Writer, c.Request, "", file.CreatedAt, f)
+}
+
+// formFile opens the "file" field of a multipart upload, responding 400 when it is
+// missing or the request is too large
+func (h *MediaHandler) formFile(c *gin.Context) (multipart.File, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxUploadSize()+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		details := "file is required"
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			details = fmt.Sprintf("file must be at most %d bytes", h.service.MaxUploadSize())
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": details,
+		})
+		return nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to read upload",
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+	return file, true
+}
+
+// parseStudyActivityID reads the study activity ID from the URL, responding 400 when it is invalid
+func parseStudyActivityID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid study activity ID",
+			"details": "ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondMediaError maps media errors to responses
+func respondMediaError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, tts.ErrUnavailable):
+		status = http.StatusBadGateway
+	case strings.HasSuffix(err.Error(), "not found"):
+		status = http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "audio file "), strings.HasPrefix(err.Error(), "image file "),
+		strings.HasPrefix(err.Error(), "text "):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
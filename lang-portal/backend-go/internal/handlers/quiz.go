@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lang-portal/internal/quiz"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// maxPlayerNameLength bounds player names, in characters
+const maxPlayerNameLength = 32
+
+// QuizHandler handles live quiz rooms and generated quizzes
+type QuizHandler struct {
+	manager   *quiz.Manager
+	generator *quiz.Generator
+	upgrader  websocket.Upgrader
+}
+
+// NewQuizHandler creates a new handler for quiz rooms and generated quizzes
+func NewQuizHandler(manager *quiz.Manager, generator *quiz.Generator) *QuizHandler {
+	return &QuizHandler{
+		manager:   manager,
+		generator: generator,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Rooms are joined with codes and tokens rather than cookies,
+			// so connections from other origins (the frontend) are fine
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// CreateQuizRoom handles POST /api/v1/quiz/rooms
+func (h *QuizHandler) CreateQuizRoom(c *gin.Context) {
+	// Define request body struct
+	type CreateRoomRequest struct {
+		GroupID         int64 `json:"group_id" binding:"required"`
+		StudyActivityID int64 `json:"study_activity_id" binding:"required"`
+		QuestionCount   int   `json:"question_count"`
+		QuestionSeconds int   `json:"question_seconds"`
+	}
+
+	var req CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	room, err := h.manager.CreateRoom(c.Request.Context(), quiz.Settings{
+		GroupID:         req.GroupID,
+		StudyActivityID: req.StudyActivityID,
+		QuestionCount:   req.QuestionCount,
+		QuestionTime:    time.Duration(req.QuestionSeconds) * time.Second,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.HasPrefix(err.Error(), "question_"):
+			status = http.StatusBadRequest
+		case err.Error() == "study activity has been retired":
+			status = http.StatusConflict
+		case strings.HasPrefix(err.Error(), "group needs"):
+			status = http.StatusUnprocessableEntity
+		case err.Error() == "too many quiz rooms", err.Error() == "quiz rooms are shutting down":
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to create quiz room",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	info, _ := room.Info()
+	wsPath := "/api/v1/quiz/rooms/" + room.Code + "/ws"
+	c.JSON(http.StatusCreated, gin.H{
+		"room":       info,
+		"host_token": room.HostToken,
+		"host_url":   wsPath + "?host_token=" + room.HostToken,
+		"join_url":   wsPath + "?name=",
+	})
+}
+
+// GetQuizRoom handles GET /api/v1/quiz/rooms/:code
+func (h *QuizHandler) GetQuizRoom(c *gin.Context) {
+	room, ok := h.manager.Room(strings.ToUpper(c.Param("code")))
+	var info quiz.Info
+	if ok {
+		info, ok = room.Info()
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Quiz room not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// ConnectQuizRoom handles GET /api/v1/quiz/rooms/:code/ws, upgrading to a WebSocket.
+// Hosts pass host_token; players pass name, or player_token to rejoin.
+func (h *QuizHandler) ConnectQuizRoom(c *gin.Context) {
+	room, ok := h.manager.Room(strings.ToUpper(c.Param("code")))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Quiz room not found",
+		})
+		return
+	}
+
+	hostToken := c.Query("host_token")
+	playerToken := c.Query("player_token")
+	name := strings.TrimSpace(c.Query("name"))
+
+	switch {
+	case hostToken != "":
+		if !room.IsHost(hostToken) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Invalid host token",
+			})
+			return
+		}
+	case playerToken == "" && (name == "" || utf8.RuneCountInString(name) > maxPlayerNameLength):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid player name",
+			"details": "name is required and must be at most 32 characters",
+		})
+		return
+	}
+
+	ws, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader already responded
+		return
+	}
+
+	conn := quiz.NewConn(ws)
+	if hostToken != "" {
+		room.ServeHost(conn)
+	} else {
+		room.ServePlayer(conn, name, playerToken)
+	}
+}
+
+// GenerateQuiz handles POST /api/v1/quizzes
+func (h *QuizHandler) GenerateQuiz(c *gin.Context) {
+	// Define request body struct
+	type GenerateQuizRequest struct {
+		GroupID       int64  `json:"group_id" binding:"required"`
+		Type          string `json:"type" binding:"required"`
+		QuestionCount int    `json:"question_count"`
+		ChoiceCount   int    `json:"choice_count"`
+	}
+
+	var req GenerateQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	generated, err := h.generator.Generate(c.Request.Context(), quiz.QuizSettings{
+		GroupID:       req.GroupID,
+		Type:          req.Type,
+		QuestionCount: req.QuestionCount,
+		ChoiceCount:   req.ChoiceCount,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case err.Error() == "group not found":
+			status = http.StatusNotFound
+		case strings.HasPrefix(err.Error(), "type "), strings.HasPrefix(err.Error(), "question_"),
+			strings.HasPrefix(err.Error(), "choice_"):
+			status = http.StatusBadRequest
+		case strings.HasPrefix(err.Error(), "group needs"):
+			status = http.StatusUnprocessableEntity
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to generate quiz",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, generated)
+}
+
+// ScoreQuiz handles POST /api/v1/quizzes/score
+func (h *QuizHandler) ScoreQuiz(c *gin.Context) {
+	// Define request body struct
+	type ScoreQuizRequest struct {
+		AnswerKey      string        `json:"answer_key" binding:"required"`
+		StudySessionID int64         `json:"study_session_id" binding:"required"`
+		Answers        []quiz.Answer `json:"answers"`
+	}
+
+	var req ScoreQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.generator.Score(c.Request.Context(), req.AnswerKey, req.StudySessionID, req.Answers)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, quiz.ErrInvalidAnswerKey), strings.HasPrefix(err.Error(), "answers: "),
+			err.Error() == "study session is not for the quiz's group":
+			status = http.StatusBadRequest
+		case errors.Is(err, quiz.ErrExpiredAnswerKey):
+			status = http.StatusGone
+		case strings.HasSuffix(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case err.Error() == "quiz already scored", err.Error() == "study session already completed":
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to score quiz",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
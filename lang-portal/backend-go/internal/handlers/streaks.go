@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"lang-portal/internal/streak"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStreakHistoryDays bounds the range of the streak history
+const maxStreakHistoryDays = 400
+
+// StreakHandler handles HTTP requests related to study streaks
+type StreakHandler struct {
+	service *streak.Service
+}
+
+// NewStreakHandler creates a new handler for streaks
+func NewStreakHandler(service *streak.Service) *StreakHandler {
+	return &StreakHandler{service: service}
+}
+
+// queryLocation returns the timezone of the tz query parameter, or fallback
+func queryLocation(c *gin.Context, fallback *time.Location) (*time.Location, error) {
+	tz := c.Query("tz")
+	if tz == "" {
+		return fallback, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("tz must be an IANA timezone such as Europe/Paris")
+	}
+	return loc, nil
+}
+
+// GetStreak handles GET /api/v1/streaks
+func (h *StreakHandler) GetStreak(c *gin.Context) {
+	loc, err := queryLocation(c, h.service.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timezone",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	summary, _, err := h.service.Summary(c.Request.Context(), loc, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to calculate streak",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetStreakHistory handles GET /api/v1/streaks/history
+func (h *StreakHandler) GetStreakHistory(c *gin.Context) {
+	loc, err := queryLocation(c, h.service.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timezone",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Default to the last 30 days, today included
+	now := time.Now()
+	to := now.In(loc).Format(streak.DateFormat)
+	if value := c.Query("to"); value != "" {
+		to = value
+	}
+	toDate, err := time.Parse(streak.DateFormat, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": "to must be a date formatted as YYYY-MM-DD",
+		})
+		return
+	}
+	fromDate := toDate.AddDate(0, 0, -29)
+	if value := c.Query("from"); value != "" {
+		if fromDate, err = time.Parse(streak.DateFormat, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": "from must be a date formatted as YYYY-MM-DD",
+			})
+			return
+		}
+	}
+	if toDate.Before(fromDate) || toDate.Sub(fromDate) >= maxStreakHistoryDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": fmt.Sprintf("from must not be after to, and the range must span at most %d days", maxStreakHistoryDays),
+		})
+		return
+	}
+
+	summary, days, err := h.service.Summary(c.Request.Context(), loc, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to calculate streak",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Days before the first study day or after today have no streak state
+	from := fromDate.Format(streak.DateFormat)
+	items := []streak.Day{}
+	for _, day := range days {
+		if day.Date >= from && day.Date <= toDate.Format(streak.DateFormat) {
+			items = append(items, day)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timezone": summary.Timezone,
+		"from":     from,
+		"to":       toDate.Format(streak.DateFormat),
+		"summary":  summary,
+		"items":    items,
+	})
+}
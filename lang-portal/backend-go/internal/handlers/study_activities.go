@@ -3,8 +3,10 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"lang-portal/internal/models"
 	"lang-portal/internal/repository"
 )
 
@@ -35,8 +37,10 @@ func (h *StudyActivityHandler) ListStudyActivities(c *gin.Context) {
 		pageSize = 100
 	}
 
+	includeRetired, _ := strconv.ParseBool(c.DefaultQuery("include_retired", "false"))
+
 	// Fetch study activities
-	activities, totalCount, err := h.studyActivityRepo.List(c.Request.Context(), page, pageSize)
+	activities, totalCount, err := h.studyActivityRepo.List(c.Request.Context(), page, pageSize, includeRetired)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to retrieve study activities",
@@ -50,6 +54,8 @@ func (h *StudyActivityHandler) ListStudyActivities(c *gin.Context) {
 		"current_page": page,
 		"total_pages":  (totalCount + pageSize - 1) / pageSize,
 		"total_count":  totalCount,
+		// total_activities is kept for clients following the original spec
+		"total_activities": totalCount,
 	})
 }
 
@@ -76,5 +82,164 @@ func (h *StudyActivityHandler) GetStudyActivity(c *gin.Context) {
 		return
 	}
 
+	// Fetch usage statistics
+	details, err := h.studyActivityRepo.GetActivityDetails(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve study activity details",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, repository.StudyActivityListItem{
+		StudyActivity:        *activity,
+		StudyActivityDetails: *details,
+	})
+}
+
+// studyActivityRequest represents the body for registering or updating a study activity.
+// Pointer fields allow PATCH requests to leave values unchanged.
+type studyActivityRequest struct {
+	Name               *string   `json:"name"`
+	URL                *string   `json:"url"`
+	ThumbnailURL       *string   `json:"thumbnail_url"`
+	Description        *string   `json:"description"`
+	SupportedModes     *[]string `json:"supported_modes"`
+	RequiredWordFields *[]string `json:"required_word_fields"`
+}
+
+// apply copies the provided fields onto an activity
+func (req studyActivityRequest) apply(activity *models.StudyActivity) {
+	if req.Name != nil {
+		activity.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.URL != nil {
+		activity.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.ThumbnailURL != nil {
+		activity.ThumbnailURL = strings.TrimSpace(*req.ThumbnailURL)
+	}
+	if req.Description != nil {
+		activity.Description = *req.Description
+	}
+	if req.SupportedModes != nil {
+		activity.SupportedModes = *req.SupportedModes
+	}
+	if req.RequiredWordFields != nil {
+		activity.RequiredWordFields = *req.RequiredWordFields
+	}
+}
+
+// CreateStudyActivity handles POST /api/v1/study-activities
+func (h *StudyActivityHandler) CreateStudyActivity(c *gin.Context) {
+	var req studyActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var activity models.StudyActivity
+	req.apply(&activity)
+	if err := activity.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid study activity",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.studyActivityRepo.Create(c.Request.Context(), &activity); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create study activity",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, activity)
+}
+
+// UpdateStudyActivity handles PATCH /api/v1/study-activities/:id
+func (h *StudyActivityHandler) UpdateStudyActivity(c *gin.Context) {
+	// Parse activity ID from URL
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid study activity ID",
+			"details": "ID must be a valid integer",
+		})
+		return
+	}
+
+	var req studyActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Merge the changes into the stored activity
+	activity, err := h.studyActivityRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Study activity not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	req.apply(activity)
+	if err := activity.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid study activity",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.studyActivityRepo.Update(c.Request.Context(), activity); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update study activity",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, activity)
 }
+
+// RetireStudyActivity handles DELETE /api/v1/study-activities/:id
+func (h *StudyActivityHandler) RetireStudyActivity(c *gin.Context) {
+	// Parse activity ID from URL
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid study activity ID",
+			"details": "ID must be a valid integer",
+		})
+		return
+	}
+
+	if err := h.studyActivityRepo.Retire(c.Request.Context(), id); err != nil {
+		if err.Error() == "study activity not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Study activity not found",
+				"details": err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retire study activity",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
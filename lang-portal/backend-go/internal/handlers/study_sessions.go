@@ -64,8 +64,24 @@ func (h *StudySessionHandler) ListStudySessions(c *gin.Context) {
 		}
 	}
 
+	// Parse sorting parameters
+	params := models.DefaultStudySessionQueryParams()
+	params.Page = page
+	params.PerPage = sessionsPerPage
+	params.StudyActivityID = activityID
+	params.GroupID = groupID
+	params.SortBy = c.DefaultQuery("sort_by", params.SortBy)
+	params.Order = c.DefaultQuery("order", params.Order)
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Fetch study sessions
-	sessions, totalSessions, err := h.studySessionRepo.List(c.Request.Context(), activityID, groupID, page, sessionsPerPage)
+	sessions, totalSessions, err := h.studySessionRepo.List(c.Request.Context(), params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to retrieve study sessions",
@@ -149,6 +165,42 @@ func (h *StudySessionHandler) GetStudySessionDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, sessionDetails)
 }
 
+// CompleteStudySession handles POST /api/v1/study-sessions/:id/complete
+func (h *StudySessionHandler) CompleteStudySession(c *gin.Context) {
+	// Parse session ID from URL
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid study session ID",
+			"details": "ID must be a valid integer",
+		})
+		return
+	}
+
+	session, err := h.studySessionRepo.Complete(c.Request.Context(), sessionID)
+	if err != nil {
+		respondCompleteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// respondCompleteError maps study session completion errors to responses
+func respondCompleteError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch err.Error() {
+	case "study session not found":
+		status = http.StatusNotFound
+	case "study session already completed":
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   "Failed to complete study session",
+		"details": err.Error(),
+	})
+}
+
 // CreateWordReview handles POST /study-sessions/:id/words/:word-id/review
 func (h *StudySessionHandler) CreateWordReview(c *gin.Context) {
 	// Parse study session ID from URL
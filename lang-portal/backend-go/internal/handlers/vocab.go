@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"lang-portal/internal/llm"
+	"lang-portal/internal/vocab"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VocabHandler handles groups generated by a language model
+type VocabHandler struct {
+	generator *vocab.Generator
+}
+
+// NewVocabHandler creates a new handler for generated groups
+func NewVocabHandler(generator *vocab.Generator) *VocabHandler {
+	return &VocabHandler{generator: generator}
+}
+
+// GenerateGroup handles POST /api/v1/groups/generate, previewing a themed word list
+func (h *VocabHandler) GenerateGroup(c *gin.Context) {
+	var req struct {
+		Theme string `json:"theme" binding:"required"`
+		Size  int    `json:"size"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	preview, err := h.generator.Preview(c.Request.Context(), req.Theme, req.Size)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, llm.ErrUnavailable), errors.Is(err, llm.ErrInvalidReply):
+			status = http.StatusBadGateway
+		case strings.HasPrefix(err.Error(), "theme "), strings.HasPrefix(err.Error(), "size "):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to generate words",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// ConfirmGeneratedGroup handles POST /api/v1/groups/generate/confirm, creating the
+// group and words of a reviewed preview
+func (h *VocabHandler) ConfirmGeneratedGroup(c *gin.Context) {
+	var req struct {
+		Name  string       `json:"name" binding:"required"`
+		Words []vocab.Word `json:"words" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	group, created, err := h.generator.Confirm(c.Request.Context(), req.Name, req.Words)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case strings.HasPrefix(err.Error(), "name "), strings.HasPrefix(err.Error(), "words"):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to create group",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"group":         group,
+		"words_created": created,
+		"words_reused":  len(req.Words) - created,
+	})
+}
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"lang-portal/internal/events"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+	"lang-portal/internal/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles webhook subscriptions and their delivery log
+type WebhookHandler struct {
+	webhookRepo repository.WebhookRepository
+	dispatcher  *webhook.Dispatcher
+}
+
+// NewWebhookHandler creates a new handler for webhooks
+func NewWebhookHandler(repo repository.WebhookRepository, dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{webhookRepo: repo, dispatcher: dispatcher}
+}
+
+// webhookRequest is the body of webhook create and update requests; omitted fields are left unchanged on update
+type webhookRequest struct {
+	URL        *string  `json:"url"`
+	Secret     *string  `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// apply copies the fields present in the request onto a subscription
+func (r *webhookRequest) apply(s *models.WebhookSubscription) {
+	if r.URL != nil {
+		s.URL = *r.URL
+	}
+	if r.Secret != nil {
+		s.Secret = *r.Secret
+	}
+	if r.EventTypes != nil {
+		s.EventTypes = r.EventTypes
+	}
+	if r.Active != nil {
+		s.Active = *r.Active
+	}
+}
+
+// ListWebhooks handles GET /api/v1/webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	subscriptions, err := h.webhookRepo.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve webhooks",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Secrets are only shown when they are created
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       subscriptions,
+		"event_types": events.Types,
+	})
+}
+
+// GetWebhook handles GET /api/v1/webhooks/:id
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.webhookRepo.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, "Failed to retrieve webhook", err)
+		return
+	}
+
+	subscription.Secret = ""
+	c.JSON(http.StatusOK, subscription)
+}
+
+// CreateWebhook handles POST /api/v1/webhooks
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	subscription := models.WebhookSubscription{Active: true}
+	req.apply(&subscription)
+	if err := subscription.Validate(events.IsType); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid webhook",
+			"details": err.Error(),
+		})
+		return
+	}
+	if subscription.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to create webhook",
+				"details": err.Error(),
+			})
+			return
+		}
+		subscription.Secret = secret
+	}
+
+	if err := h.webhookRepo.CreateSubscription(c.Request.Context(), &subscription); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create webhook",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// UpdateWebhook handles PATCH /api/v1/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.Secret != nil && *req.Secret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Secret must not be empty",
+		})
+		return
+	}
+
+	subscription, err := h.webhookRepo.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, "Failed to update webhook", err)
+		return
+	}
+	req.apply(subscription)
+	if err := subscription.Validate(events.IsType); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid webhook",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.webhookRepo.UpdateSubscription(c.Request.Context(), subscription); err != nil {
+		respondWebhookError(c, "Failed to update webhook", err)
+		return
+	}
+
+	subscription.Secret = ""
+	c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteWebhook handles DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookRepo.DeleteSubscription(c.Request.Context(), id); err != nil {
+		respondWebhookError(c, "Failed to delete webhook", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles GET /api/v1/webhooks/:id/deliveries
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "50"))
+	if err != nil || perPage < 1 || perPage > 500 {
+		perPage = 50
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": "status must be pending, delivered or failed",
+		})
+		return
+	}
+
+	if _, err := h.webhookRepo.GetSubscription(c.Request.Context(), id); err != nil {
+		respondWebhookError(c, "Failed to retrieve webhook deliveries", err)
+		return
+	}
+
+	deliveries, totalCount, err := h.webhookRepo.ListDeliveries(c.Request.Context(), id, status, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve webhook deliveries",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	totalPages := (totalCount + perPage - 1) / perPage
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":        deliveries,
+		"total_count":  totalCount,
+		"current_page": page,
+		"total_pages":  totalPages,
+	})
+}
+
+// RetryWebhookDelivery handles POST /api/v1/webhooks/deliveries/:delivery_id/retry
+func (h *WebhookHandler) RetryWebhookDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid delivery ID",
+			"details": "ID must be a valid integer",
+		})
+		return
+	}
+
+	delivery, err := h.webhookRepo.RetryDelivery(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, "Failed to retry webhook delivery", err)
+		return
+	}
+	h.dispatcher.Wake()
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// parseWebhookID reads the webhook ID from the URL, responding 400 when it is invalid
+func parseWebhookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid webhook ID",
+			"details": "ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondWebhookError maps webhook repository errors to responses
+func respondWebhookError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch err.Error() {
+	case "webhook subscription not found", "webhook delivery not found":
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
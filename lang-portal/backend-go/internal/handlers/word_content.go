@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"lang-portal/internal/content"
+	"lang-portal/internal/llm"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WordContentHandler handles generated and teacher-written content about words
+type WordContentHandler struct {
+	service *content.Service
+}
+
+// NewWordContentHandler creates a new handler for word content
+func NewWordContentHandler(service *content.Service) *WordContentHandler {
+	return &WordContentHandler{service: service}
+}
+
+// ListWordContent handles GET /api/v1/words/:id/content
+func (h *WordContentHandler) ListWordContent(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	contents, err := h.service.List(c.Request.Context(), wordID)
+	if err != nil {
+		respondWordContentError(c, "Failed to retrieve word content", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": contents,
+	})
+}
+
+// GetWordContent handles GET /api/v1/words/:id/content/:kind, generating missing content
+func (h *WordContentHandler) GetWordContent(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	wordContent, err := h.service.Get(c.Request.Context(), wordID, c.Param("kind"))
+	if err != nil {
+		respondWordContentError(c, "Failed to retrieve word content", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, wordContent)
+}
+
+// GenerateWordContent handles POST /api/v1/words/:id/content/:kind/generate
+func (h *WordContentHandler) GenerateWordContent(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+	overwrite, err := strconv.ParseBool(c.DefaultQuery("overwrite", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": "overwrite must be a boolean",
+		})
+		return
+	}
+
+	wordContent, err := h.service.Generate(c.Request.Context(), wordID, c.Param("kind"), overwrite)
+	if err != nil {
+		respondWordContentError(c, "Failed to generate word content", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, wordContent)
+}
+
+// UpdateWordContent handles PUT /api/v1/words/:id/content/:kind, saving a teacher's text
+func (h *WordContentHandler) UpdateWordContent(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	wordContent, err := h.service.Edit(c.Request.Context(), wordID, c.Param("kind"), req.Content)
+	if err != nil {
+		respondWordContentError(c, "Failed to update word content", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, wordContent)
+}
+
+// DeleteWordContent handles DELETE /api/v1/words/:id/content/:kind
+func (h *WordContentHandler) DeleteWordContent(c *gin.Context) {
+	wordID, ok := parseWordID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), wordID, c.Param("kind")); err != nil {
+		respondWordContentError(c, "Failed to delete word content", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseWordID reads the word ID from the URL, responding 400 when it is invalid
+func parseWordID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid word ID",
+			"details": "ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondWordContentError maps word content errors to responses
+func respondWordContentError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, llm.ErrUnavailable):
+		status = http.StatusBadGateway
+	case strings.HasSuffix(err.Error(), "not found"):
+		status = http.StatusNotFound
+	case err.Error() == "content was written by a teacher":
+		status = http.StatusConflict
+	case strings.HasPrefix(err.Error(), "kind "), strings.HasPrefix(err.Error(), "content "):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
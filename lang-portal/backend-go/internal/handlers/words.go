@@ -156,8 +156,22 @@ func (h *WordHandler) UpdateWord(c *gin.Context) {
 		return
 	}
 
+	// Ensure parts is valid JSON
+	if !json.Valid(word.Parts) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parts JSON",
+		})
+		return
+	}
+
 	// Update word
 	if err := h.wordRepo.Update(c.Request.Context(), &word); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Word not found",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to update word",
 			"details": err.Error(),
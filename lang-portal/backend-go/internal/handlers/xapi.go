@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"lang-portal/internal/repository"
+	"lang-portal/internal/xapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// XAPIHandler handles the xAPI statements resource of the Learning Record Store
+type XAPIHandler struct {
+	xapiRepo repository.XAPIRepository
+	iris     xapi.IRIs
+}
+
+// NewXAPIHandler creates a new handler for xAPI statements
+func NewXAPIHandler(repo repository.XAPIRepository, iris xapi.IRIs) *XAPIHandler {
+	return &XAPIHandler{xapiRepo: repo, iris: iris}
+}
+
+// PostStatements handles POST /xapi/statements
+func (h *XAPIHandler) PostStatements(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	raws, err := xapi.ParseStatements(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	statements, ok := h.prepareStatements(c, raws)
+	if !ok {
+		return
+	}
+
+	if !h.saveStatements(c, statements) {
+		return
+	}
+
+	ids := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		ids = append(ids, statement.ID)
+	}
+	c.JSON(http.StatusOK, ids)
+}
+
+// PutStatement handles PUT /xapi/statements?statementId=
+func (h *XAPIHandler) PutStatement(c *gin.Context) {
+	statementID := c.Query("statementId")
+	if statementID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "statementId is required",
+		})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// The statement id comes from the query string and must match the body if present
+	var properties map[string]interface{}
+	if err := json.Unmarshal(body, &properties); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": "statement must be a JSON object",
+		})
+		return
+	}
+	if id, ok := properties["id"].(string); ok && !strings.EqualFold(id, statementID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "statementId does not match the statement id",
+		})
+		return
+	}
+	properties["id"] = statementID
+	raw, err := json.Marshal(properties)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to encode statement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	statements, ok := h.prepareStatements(c, []json.RawMessage{raw})
+	if !ok {
+		return
+	}
+
+	if !h.saveStatements(c, statements) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetStatements handles GET /xapi/statements
+func (h *XAPIHandler) GetStatements(c *gin.Context) {
+	c.Header("X-Experience-API-Consistent-Through", time.Now().UTC().Format(time.RFC3339Nano))
+
+	// Single statement lookups
+	statementID := c.Query("statementId")
+	voidedStatementID := c.Query("voidedStatementId")
+	if statementID != "" || voidedStatementID != "" {
+		if statementID != "" && voidedStatementID != "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "statementId and voidedStatementId cannot be combined",
+			})
+			return
+		}
+
+		id, voided := statementID, false
+		if voidedStatementID != "" {
+			id, voided = voidedStatementID, true
+		}
+
+		statement, err := h.xapiRepo.GetStatement(c.Request.Context(), id, voided)
+		if err != nil {
+			if err.Error() == "statement not found" {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Statement not found",
+				})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   "Failed to retrieve statement",
+					"details": err.Error(),
+				})
+			}
+			return
+		}
+
+		c.Data(http.StatusOK, "application/json; charset=utf-8", statement)
+		return
+	}
+
+	filter, err := parseStatementFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	statements, nextCursor, err := h.xapiRepo.QueryStatements(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to query statements",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result := xapi.StatementResult{Statements: statements}
+	if nextCursor > 0 {
+		query := c.Request.URL.Query()
+		query.Set("cursor", strconv.FormatInt(nextCursor, 10))
+		result.More = c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetReviewStatements handles GET /api/v1/xapi/reviews, emitting portal reviews as xAPI statements
+func (h *XAPIHandler) GetReviewStatements(c *gin.Context) {
+	after, err := strconv.ParseInt(c.DefaultQuery("after", "0"), 10, 64)
+	if err != nil || after < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid after",
+			"details": "after must be a review ID",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(xapi.MaxLimit)))
+	if err != nil || limit < 1 {
+		limit = xapi.MaxLimit
+	}
+
+	statements, nextCursor, err := h.xapiRepo.ReviewStatements(c.Request.Context(), after, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve review statements",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result := xapi.StatementResult{Statements: statements}
+	if nextCursor > 0 {
+		query := url.Values{}
+		query.Set("after", strconv.FormatInt(nextCursor, 10))
+		query.Set("limit", strconv.Itoa(limit))
+		result.More = c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	c.Header("X-Experience-API-Version", xapi.Version)
+	c.JSON(http.StatusOK, result)
+}
+
+// prepareStatements validates submitted statements, writing a 400 response on failure
+func (h *XAPIHandler) prepareStatements(c *gin.Context, raws []json.RawMessage) ([]*xapi.StoredStatement, bool) {
+	now := time.Now().UTC()
+	statements := make([]*xapi.StoredStatement, 0, len(raws))
+	for i, raw := range raws {
+		statement, err := xapi.Prepare(raw, h.iris.Authority(), now)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid statement",
+				"details": "statement " + strconv.Itoa(i) + ": " + err.Error(),
+			})
+			return nil, false
+		}
+		statements = append(statements, statement)
+	}
+	return statements, true
+}
+
+// saveStatements stores prepared statements, writing an error response on failure
+func (h *XAPIHandler) saveStatements(c *gin.Context, statements []*xapi.StoredStatement) bool {
+	err := h.xapiRepo.SaveStatements(c.Request.Context(), statements)
+	if err == nil {
+		return true
+	}
+
+	switch {
+	case strings.HasPrefix(err.Error(), "statement conflict"):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Statement conflict",
+			"details": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "voided statement not found"),
+		strings.HasPrefix(err.Error(), "cannot void"):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid voiding statement",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to store statements",
+			"details": err.Error(),
+		})
+	}
+	return false
+}
+
+// parseStatementFilter reads the statement query filters from the request
+func parseStatementFilter(c *gin.Context) (xapi.StatementFilter, error) {
+	filter := xapi.StatementFilter{
+		Verb:         c.Query("verb"),
+		Activity:     c.Query("activity"),
+		Registration: c.Query("registration"),
+	}
+
+	if agentJSON := c.Query("agent"); agentJSON != "" {
+		var agent xapi.Agent
+		if err := json.Unmarshal([]byte(agentJSON), &agent); err != nil {
+			return filter, fmt.Errorf("agent must be an Agent JSON object")
+		}
+		filter.Agent = agent.Identifier()
+		if filter.Agent == "" {
+			return filter, fmt.Errorf("agent must have an inverse functional identifier")
+		}
+	}
+
+	for name, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := c.Query(name); value != "" {
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be an ISO 8601 timestamp", name)
+			}
+			*target = &t
+		}
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return filter, fmt.Errorf("limit must be a non-negative integer")
+		}
+		filter.Limit = limit
+	}
+
+	if value := c.Query("ascending"); value != "" {
+		ascending, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("ascending must be a boolean")
+		}
+		filter.Ascending = ascending
+	}
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || cursor < 0 {
+			return filter, fmt.Errorf("cursor must be a value returned in more")
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
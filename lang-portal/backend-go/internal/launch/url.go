@@ -0,0 +1,66 @@
+package launch
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// URLParams are the values substituted into an activity's launch URL template
+type URLParams struct {
+	GroupID        int64
+	StudySessionID int64
+	CallbackURL    string
+	Token          string
+}
+
+// BuildURL fills the {group_id}, {session_id}, {callback_url} and {token} placeholders
+// of a launch URL template. Values missing from the template are appended as query
+// parameters so every activity receives the full launch context.
+func BuildURL(template string, params URLParams) (string, error) {
+	values := []struct {
+		key   string
+		value string
+	}{
+		{"group_id", strconv.FormatInt(params.GroupID, 10)},
+		{"session_id", strconv.FormatInt(params.StudySessionID, 10)},
+		{"callback_url", params.CallbackURL},
+		{"token", params.Token},
+	}
+
+	missing := url.Values{}
+	filled := template
+	for _, v := range values {
+		placeholder := "{" + v.key + "}"
+		if strings.Contains(filled, placeholder) {
+			filled = strings.ReplaceAll(filled, placeholder, url.QueryEscape(v.value))
+		} else {
+			missing.Set(v.key, v.value)
+		}
+	}
+
+	launchURL, err := url.Parse(filled)
+	if err != nil {
+		return "", fmt.Errorf("invalid launch URL template: %w", err)
+	}
+	if launchURL.Scheme == "" || launchURL.Host == "" {
+		return "", fmt.Errorf("launch URL must be absolute: %s", template)
+	}
+
+	if len(missing) > 0 {
+		if launchURL.RawQuery != "" {
+			launchURL.RawQuery += "&"
+		}
+		launchURL.RawQuery += missing.Encode()
+	}
+
+	return launchURL.String(), nil
+}
+
+// ValidateTemplate checks that a launch URL template builds an absolute URL, so that a
+// launch can be refused before its study session is created
+func ValidateTemplate(template string) error {
+	_, err := BuildURL(template, URLParams{})
+	return err
+}
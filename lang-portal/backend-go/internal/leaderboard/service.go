@@ -0,0 +1,247 @@
+package leaderboard
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+	"lang-portal/internal/streak"
+)
+
+// counts are the review statistics of a learner over a period
+type counts struct {
+	reviews  int
+	correct  int
+	mastered int
+}
+
+// learnerStats are the statistics of a learner that leaderboards rank
+type learnerStats struct {
+	allTime       counts
+	weekly        counts
+	currentStreak int
+	longestStreak int
+}
+
+// snapshot holds the statistics of every learner of a scope, as computed at one time
+type snapshot struct {
+	computedAt time.Time
+	weekStart  time.Time
+	learners   map[int64]*learnerStats
+}
+
+// cacheKey identifies a snapshot; a new week needs a new snapshot
+type cacheKey struct {
+	classID   int64
+	weekStart time.Time
+}
+
+// Service ranks learners; statistics are cached for ttl so requests do not rescan the history
+type Service struct {
+	repo     repository.LeaderboardRepository
+	location *time.Location
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[cacheKey]*snapshot
+}
+
+// NewService creates a service counting days and weeks in loc
+func NewService(repo repository.LeaderboardRepository, loc *time.Location, ttl time.Duration) *Service {
+	return &Service{repo: repo, location: loc, ttl: ttl, cache: map[cacheKey]*snapshot{}}
+}
+
+// Leaderboard ranks the learners who have not opted out by the metric and period of params
+func (s *Service) Leaderboard(ctx context.Context, params models.LeaderboardQueryParams, now time.Time) (*models.Leaderboard, error) {
+	snap, err := s.snapshot(ctx, params.ClassID, now)
+	if err != nil {
+		return nil, err
+	}
+	// Privacy settings are read on every request so opting out applies immediately
+	settings, err := s.repo.ListPrivacy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &models.Leaderboard{
+		Metric:     params.Metric,
+		Period:     params.Period,
+		ClassID:    params.ClassID,
+		Timezone:   s.location.String(),
+		ComputedAt: snap.computedAt,
+		Items:      []models.LeaderboardEntry{},
+	}
+	if params.Period == models.LeaderboardWeekly {
+		board.WeekStart = snap.weekStart.Format(models.HistoryDateFormat)
+	}
+
+	for _, privacy := range settings {
+		stats, ok := snap.learners[privacy.LearnerID]
+		if !ok || privacy.LeaderboardOptOut {
+			continue
+		}
+		entry, ranked := rankValue(stats, params)
+		if !ranked {
+			continue
+		}
+		entry.LearnerID = privacy.LearnerID
+		entry.Name = privacy.PublicName()
+		board.Items = append(board.Items, entry)
+	}
+
+	sort.SliceStable(board.Items, func(i, j int) bool {
+		a, b := board.Items[i], board.Items[j]
+		if a.Value != b.Value {
+			return a.Value > b.Value
+		}
+		if a.Reviews != b.Reviews {
+			return a.Reviews > b.Reviews
+		}
+		return a.LearnerID < b.LearnerID
+	})
+	for i := range board.Items {
+		board.Items[i].Rank = i + 1
+		if i > 0 && board.Items[i].Value == board.Items[i-1].Value {
+			board.Items[i].Rank = board.Items[i-1].Rank
+		}
+	}
+
+	board.TotalCount = len(board.Items)
+	if len(board.Items) > params.Limit {
+		board.Items = board.Items[:params.Limit]
+	}
+	return board, nil
+}
+
+// rankValue returns the entry of a learner for the metric and period of params, and whether
+// the learner is ranked at all: nothing done in the period leaves a learner off the board
+func rankValue(stats *learnerStats, params models.LeaderboardQueryParams) (models.LeaderboardEntry, bool) {
+	period := stats.allTime
+	if params.Period == models.LeaderboardWeekly {
+		period = stats.weekly
+	}
+
+	var entry models.LeaderboardEntry
+	switch params.Metric {
+	case models.LeaderboardAccuracy:
+		if period.reviews < params.MinReviews {
+			return entry, false
+		}
+		entry.Value = 100 * period.correct / period.reviews
+		entry.Reviews = period.reviews
+	case models.LeaderboardStreak:
+		entry.Value = stats.longestStreak
+		if params.Period == models.LeaderboardWeekly {
+			entry.Value = stats.currentStreak
+		}
+	case models.LeaderboardWordsMastered:
+		entry.Value = period.mastered
+	default:
+		entry.Value = period.reviews
+	}
+	return entry, entry.Value > 0
+}
+
+// snapshot returns the cached statistics of a scope, computing them when missing or expired
+func (s *Service) snapshot(ctx context.Context, classID *int64, now time.Time) (*snapshot, error) {
+	key := cacheKey{weekStart: weekStart(now, s.location)}
+	if classID != nil {
+		key.classID = *classID
+	}
+
+	// Holding the lock while computing lets concurrent requests share one scan
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if snap, ok := s.cache[key]; ok && now.Sub(snap.computedAt) < s.ttl {
+		return snap, nil
+	}
+
+	activity, err := s.repo.GetLearnerActivity(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	snap := compute(activity, key.weekStart, s.location, now)
+
+	// Drop snapshots of past weeks and expired ones
+	for k, cached := range s.cache {
+		if !k.weekStart.Equal(key.weekStart) || now.Sub(cached.computedAt) >= s.ttl {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = snap
+	return snap, nil
+}
+
+// weekStart returns the local midnight of the Monday starting the week of t
+func weekStart(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// compute replays the activity of every learner, oldest first
+func compute(activity []repository.LearnerActivity, week time.Time, loc *time.Location, now time.Time) *snapshot {
+	type wordKey struct{ learnerID, wordID int64 }
+	snap := &snapshot{computedAt: now, weekStart: week, learners: map[int64]*learnerStats{}}
+	words := map[wordKey]*models.MasteryRun{}
+	// masteredAt is when each mastered word last became mastered
+	masteredAt := map[wordKey]time.Time{}
+	studyTimes := map[int64][]time.Time{}
+
+	for _, a := range activity {
+		stats, ok := snap.learners[a.LearnerID]
+		if !ok {
+			stats = &learnerStats{}
+			snap.learners[a.LearnerID] = stats
+		}
+		studyTimes[a.LearnerID] = append(studyTimes[a.LearnerID], a.CreatedAt)
+		if a.WordID == 0 {
+			continue
+		}
+
+		thisWeek := !a.CreatedAt.Before(week)
+		stats.allTime.reviews++
+		if thisWeek {
+			stats.weekly.reviews++
+		}
+		if a.Correct {
+			stats.allTime.correct++
+			if thisWeek {
+				stats.weekly.correct++
+			}
+		}
+
+		key := wordKey{a.LearnerID, a.WordID}
+		run, ok := words[key]
+		if !ok {
+			run = &models.MasteryRun{}
+			words[key] = run
+		}
+		before := run.Level() == models.MasteryMastered
+		run.Review(a.Correct, a.CreatedAt)
+		after := run.Level() == models.MasteryMastered
+		switch {
+		case after && !before:
+			masteredAt[key] = a.CreatedAt
+		case before && !after:
+			delete(masteredAt, key)
+		}
+	}
+
+	for key, at := range masteredAt {
+		stats := snap.learners[key.learnerID]
+		stats.allTime.mastered++
+		if !at.Before(week) {
+			stats.weekly.mastered++
+		}
+	}
+	for learnerID, times := range studyTimes {
+		summary, _ := streak.Calculate(times, loc, now, streak.DefaultRules)
+		snap.learners[learnerID].currentStreak = summary.CurrentStreak
+		snap.learners[learnerID].longestStreak = summary.LongestStreak
+	}
+	return snap
+}
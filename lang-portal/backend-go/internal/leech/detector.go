@@ -0,0 +1,53 @@
+package leech
+
+import (
+	"context"
+	"log"
+
+	"lang-portal/internal/events"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// Detector moves words into the "Needs work" group as they become leeches
+type Detector struct {
+	repo      repository.LeechRepository
+	threshold int
+}
+
+// NewDetector creates a detector flagging words at threshold lapses
+func NewDetector(repo repository.LeechRepository, threshold int) *Detector {
+	return &Detector{repo: repo, threshold: threshold}
+}
+
+// HandleEvent is an events.Handler checking words after every wrong answer
+func (d *Detector) HandleEvent(ctx context.Context, event events.Event) {
+	if event.Type != events.ReviewCreated {
+		return
+	}
+	review, ok := event.Data.(models.WordReviewItem)
+	if !ok || review.Correct {
+		return
+	}
+
+	// Reviews may be committed in batches before their events are published, so count
+	// the lapses as of this review rather than the current total
+	lapses, err := d.repo.CountLapsesUpTo(ctx, review.WordID, review.ID)
+	if err != nil {
+		log.Printf("Failed to check word %d for leeches: %v", review.WordID, err)
+		return
+	}
+	// Only act when the threshold is crossed, so a word taken out of the group stays out
+	if lapses != d.threshold {
+		return
+	}
+
+	group, added, err := d.repo.AddToNeedsWork(ctx, []int64{review.WordID})
+	if err != nil {
+		log.Printf("Failed to move leech %d to %q: %v", review.WordID, models.NeedsWorkGroupName, err)
+		return
+	}
+	if added > 0 {
+		log.Printf("Word %d became a leech after %d lapses, added to group %d", review.WordID, lapses, group.ID)
+	}
+}
@@ -0,0 +1,46 @@
+package llm
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake stands in for a model in tests: it replies with canned replies in order,
+// repeating the last one, and records the chats it was sent
+type Fake struct {
+	mu       sync.Mutex
+	replies  []string
+	requests [][]Message
+}
+
+// NewFake creates a fake replying with replies
+func NewFake(replies ...string) *Fake {
+	return &Fake{replies: replies}
+}
+
+// Model names the fake
+func (f *Fake) Model() string {
+	return "fake"
+}
+
+// Complete records messages and returns the next canned reply
+func (f *Fake) Complete(_ context.Context, messages []Message) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, messages)
+	if len(f.replies) == 0 {
+		return "", ErrUnavailable
+	}
+	reply := f.replies[0]
+	if len(f.replies) > 1 {
+		f.replies = f.replies[1:]
+	}
+	return reply, nil
+}
+
+// Requests returns the chats sent so far
+func (f *Fake) Requests() [][]Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]Message(nil), f.requests...)
+}
@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxResponseSize bounds the body read from the API
+const maxResponseSize = 1 << 20
+
+// OpenAIClient talks to an OpenAI-compatible chat completions API, which Ollama serves too
+type OpenAIClient struct {
+	baseURL string
+	model   string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewOpenAIClient creates a client for the API at baseURL, e.g. http://localhost:11434 for Ollama
+func NewOpenAIClient(baseURL, model, apiKey string, timeout time.Duration) *OpenAIClient {
+	return &OpenAIClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Model names the model replies come from
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+// Complete returns the reply to messages from POST /v1/chat/completions
+func (c *OpenAIClient) Complete(ctx context.Context, messages []Message) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":    c.model,
+		"messages": messages,
+		"stream":   false,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode completion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("invalid language model URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("%w: %s responded %s", ErrUnavailable, c.baseURL, resp.Status)
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message Message `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(data, &completion); err != nil {
+		return "", fmt.Errorf("%w: invalid completion response: %v", ErrUnavailable, err)
+	}
+	if len(completion.Choices) == 0 || strings.TrimSpace(completion.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("%w: empty completion", ErrUnavailable)
+	}
+	return strings.TrimSpace(completion.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Provider names accepted by New
+const (
+	ProviderStub   = "stub"
+	ProviderOpenAI = "openai"
+)
+
+// Providers lists the accepted provider names
+var Providers = []string{ProviderStub, ProviderOpenAI}
+
+// Message roles
+const (
+	RoleSystem = "system"
+	RoleUser   = "user"
+)
+
+// Message is one turn of a chat
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Common errors
+var (
+	// ErrUnavailable wraps failures to reach a provider or to get an answer from it
+	ErrUnavailable = errors.New("language model unavailable")
+	// ErrInvalidReply wraps replies that do not have the shape a prompt asked for
+	ErrInvalidReply = errors.New("invalid language model reply")
+)
+
+// Provider generates the next message of a chat
+type Provider interface {
+	// Complete returns the reply to messages
+	Complete(ctx context.Context, messages []Message) (string, error)
+
+	// Model names the model replies come from, as stored with generated content
+	Model() string
+}
+
+// Config selects and configures a provider
+type Config struct {
+	// Provider is one of Providers
+	Provider string
+	// BaseURL is the address of an OpenAI-compatible API, e.g. an Ollama server
+	BaseURL string
+	Model   string
+	// APIKey is sent as a bearer token when set
+	APIKey  string
+	Timeout time.Duration
+}
+
+// New creates the provider named in cfg
+func New(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case ProviderStub:
+		return Stub{}, nil
+	case ProviderOpenAI:
+		if cfg.BaseURL == "" || cfg.Model == "" {
+			return nil, fmt.Errorf("the %s provider needs a base URL and a model", ProviderOpenAI)
+		}
+		return NewOpenAIClient(cfg.BaseURL, cfg.Model, cfg.APIKey, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("provider must be one of: %s", strings.Join(Providers, ", "))
+	}
+}
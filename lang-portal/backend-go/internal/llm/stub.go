@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Stub is a deterministic provider for development and demos without a model server:
+// the same messages always get the same reply, which says what was asked. Requests for
+// a word list get sample words instead, so that generated groups can be tried out.
+type Stub struct{}
+
+// Model names the stub
+func (Stub) Model() string {
+	return ProviderStub
+}
+
+// wordListRequest matches the prompts of the vocabulary generator, capturing the number of words
+var wordListRequest = regexp.MustCompile(`generate (\d+) [^\n]*as a JSON array`)
+
+// stubWords are the sample words of word lists, in the shape of the seed files
+var stubWords = []string{
+	`{"kanji": "水", "romaji": "mizu", "english": "water", "parts": [{"kanji": "水", "romaji": ["mi", "zu"]}]}`,
+	`{"kanji": "山", "romaji": "yama", "english": "mountain", "parts": [{"kanji": "山", "romaji": ["ya", "ma"]}]}`,
+	`{"kanji": "川", "romaji": "kawa", "english": "river", "parts": [{"kanji": "川", "romaji": ["ka", "wa"]}]}`,
+	`{"kanji": "本", "romaji": "hon", "english": "book", "parts": [{"kanji": "本", "romaji": ["ho", "n"]}]}`,
+	`{"kanji": "猫", "romaji": "neko", "english": "cat", "parts": [{"kanji": "猫", "romaji": ["ne", "ko"]}]}`,
+	`{"kanji": "犬", "romaji": "inu", "english": "dog", "parts": [{"kanji": "犬", "romaji": ["i", "nu"]}]}`,
+	`{"kanji": "花", "romaji": "hana", "english": "flower", "parts": [{"kanji": "花", "romaji": ["ha", "na"]}]}`,
+	`{"kanji": "雨", "romaji": "ame", "english": "rain", "parts": [{"kanji": "雨", "romaji": ["a", "me"]}]}`,
+	`{"kanji": "空", "romaji": "sora", "english": "sky", "parts": [{"kanji": "空", "romaji": ["so", "ra"]}]}`,
+	`{"kanji": "魚", "romaji": "sakana", "english": "fish", "parts": [{"kanji": "魚", "romaji": ["sa", "ka", "na"]}]}`,
+	`{"kanji": "食べる", "romaji": "taberu", "english": "to eat", "parts": [{"kanji": "食", "romaji": ["ta"]}, {"kanji": "べ", "romaji": ["be"]}, {"kanji": "る", "romaji": ["ru"]}]}`,
+	`{"kanji": "飲む", "romaji": "nomu", "english": "to drink", "parts": [{"kanji": "飲", "romaji": ["no"]}, {"kanji": "む", "romaji": ["mu"]}]}`,
+	`{"kanji": "学校", "romaji": "gakkou", "english": "school", "parts": [{"kanji": "学", "romaji": ["ga", "k"]}, {"kanji": "校", "romaji": ["ko", "u"]}]}`,
+	`{"kanji": "友達", "romaji": "tomodachi", "english": "friend", "parts": [{"kanji": "友", "romaji": ["to", "mo"]}, {"kanji": "達", "romaji": ["da", "chi"]}]}`,
+	`{"kanji": "先生", "romaji": "sensei", "english": "teacher", "parts": [{"kanji": "先", "romaji": ["se", "n"]}, {"kanji": "生", "romaji": ["se", "i"]}]}`,
+	`{"kanji": "大きい", "romaji": "ookii", "english": "big", "parts": [{"kanji": "大", "romaji": ["o", "o"]}, {"kanji": "き", "romaji": ["ki"]}, {"kanji": "い", "romaji": ["i"]}]}`,
+}
+
+// Complete replies with the first line of the last user message and a fingerprint of the chat,
+// or with as many sample words as a word list request asks for, at most all of them
+func (Stub) Complete(_ context.Context, messages []Message) (string, error) {
+	hash := fnv.New32a()
+	request, question := "", ""
+	for _, m := range messages {
+		hash.Write([]byte(m.Role))
+		hash.Write([]byte(m.Content))
+		if m.Role == RoleUser {
+			request = m.Content
+			question, _, _ = strings.Cut(strings.TrimSpace(m.Content), "\n")
+		}
+	}
+
+	if match := wordListRequest.FindStringSubmatch(request); match != nil {
+		size, err := strconv.Atoi(match[1])
+		if err != nil || size > len(stubWords) {
+			size = len(stubWords)
+		}
+		// Different requests start at different words
+		first := int(hash.Sum32() % uint32(len(stubWords)))
+		words := make([]string, 0, size)
+		for i := 0; i < size; i++ {
+			words = append(words, stubWords[(first+i)%len(stubWords)])
+		}
+		return "[" + strings.Join(words, ",\n") + "]", nil
+	}
+	return fmt.Sprintf("[stub %08x] %s", hash.Sum32(), question), nil
+}
@@ -0,0 +1,145 @@
+package lti
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Activity and grading progress values of a score
+const (
+	ActivityInProgress = "InProgress"
+	ActivityCompleted  = "Completed"
+	GradingFullyGraded = "FullyGraded"
+)
+
+// Score is an Assignment and Grade Services score publication
+type Score struct {
+	UserID           string  `json:"userId"`
+	ScoreGiven       float64 `json:"scoreGiven"`
+	ScoreMaximum     float64 `json:"scoreMaximum"`
+	ActivityProgress string  `json:"activityProgress"`
+	GradingProgress  string  `json:"gradingProgress"`
+	Timestamp        string  `json:"timestamp"`
+	Comment          string  `json:"comment,omitempty"`
+}
+
+// AGSClient publishes scores to platforms, authenticating with signed client assertions
+type AGSClient struct {
+	key    *ToolKey
+	client *http.Client
+}
+
+// NewAGSClient creates a client signing its assertions with the tool key
+func NewAGSClient(key *ToolKey) *AGSClient {
+	return &AGSClient{key: key, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// AccessToken requests a bearer token from a platform with the client credentials grant
+func (c *AGSClient) AccessToken(ctx context.Context, clientID, tokenURL string, scopes ...string) (string, error) {
+	now := time.Now()
+	jti, err := RandomString()
+	if err != nil {
+		return "", err
+	}
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    clientID,
+		Subject:   clientID,
+		Audience:  jwt.ClaimStrings{tokenURL},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		ID:        jti,
+	})
+	assertion.Header["kid"] = c.key.KID
+	signed, err := assertion.SignedString(c.key.Private)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign client assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {signed},
+		"scope":                 {strings.Join(scopes, " ")},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("invalid token URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := c.do(req, &token); err != nil {
+		return "", fmt.Errorf("failed to obtain access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("failed to obtain access token: empty token")
+	}
+	return token.AccessToken, nil
+}
+
+// PublishScore posts a score to the scores endpoint of a line item
+func (c *AGSClient) PublishScore(ctx context.Context, clientID, tokenURL, lineItemURL string, score Score) error {
+	scoresURL, err := ScoresURL(lineItemURL)
+	if err != nil {
+		return err
+	}
+	accessToken, err := c.AccessToken(ctx, clientID, tokenURL, ScopeScore)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(score)
+	if err != nil {
+		return fmt.Errorf("failed to encode score: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scoresURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid scores URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.ims.lis.v1.score+json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	if err := c.do(req, nil); err != nil {
+		return fmt.Errorf("failed to publish score: %w", err)
+	}
+	return nil
+}
+
+// do sends a request and decodes a JSON response into out when given
+func (c *AGSClient) do(req *http.Request, out interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("platform returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ScoresURL derives the scores endpoint of a line item, keeping its query string
+func ScoresURL(lineItemURL string) (string, error) {
+	u, err := url.Parse(lineItemURL)
+	if err != nil || u.Scheme == "" {
+		return "", fmt.Errorf("invalid line item URL %q", lineItemURL)
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + "/scores"
+	return u.String(), nil
+}
@@ -0,0 +1,167 @@
+package lti
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+)
+
+// ToolKey is the RSA key the tool signs its own messages with
+type ToolKey struct {
+	KID     string
+	Private *rsa.PrivateKey
+}
+
+// LoadToolKey reads a PEM encoded RSA private key (PKCS#1 or PKCS#8).
+// An empty path generates a key, which means platforms have to refetch
+// the JWKS after every server restart.
+func LoadToolKey(path string) (*ToolKey, error) {
+	if path == "" {
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate LTI key: %w", err)
+		}
+		return newToolKey(private)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LTI key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("LTI key %s is not PEM encoded", path)
+	}
+
+	if private, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return newToolKey(private)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse LTI key: %w", err)
+	}
+	private, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("LTI key %s is not an RSA key", path)
+	}
+	return newToolKey(private)
+}
+
+// newToolKey derives the key id from the public key, so it is stable for a given key
+func newToolKey(private *rsa.PrivateKey) (*ToolKey, error) {
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode LTI public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return &ToolKey{KID: base64.RawURLEncoding.EncodeToString(sum[:12]), Private: private}, nil
+}
+
+// JWKS returns the public half of the key as a key set
+func (k *ToolKey) JWKS() JWKS {
+	return JWKS{Keys: []JWK{NewJWK(k.KID, &k.Private.PublicKey)}}
+}
+
+// JWK is an RSA JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK encodes an RSA public key as a signing JWK
+func NewJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// PublicKey decodes the RSA public key of a JWK
+func (k JWK) PublicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key exponent: %w", err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
+
+// Key finds a key by id; a set with a single key matches any id
+func (s JWKS) Key(kid string) (*rsa.PublicKey, error) {
+	for _, key := range s.Keys {
+		if key.Kid == kid || (kid == "" && len(s.Keys) == 1) {
+			return key.PublicKey()
+		}
+	}
+	return nil, fmt.Errorf("no key with id %q", kid)
+}
+
+// FetchJWKS downloads a platform key set
+func FetchJWKS(ctx context.Context, client *http.Client, url string) (*JWKS, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWKS URL: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: platform returned %s", resp.Status)
+	}
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+	return &set, nil
+}
+
+// ParsePublicKeyPEM decodes a PEM encoded RSA public key (PKIX or PKCS#1)
+func ParsePublicKeyPEM(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("public key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an RSA key")
+	}
+	return key, nil
+}
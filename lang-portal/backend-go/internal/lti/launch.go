@@ -0,0 +1,138 @@
+package lti
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LTI 1.3 message constants
+const (
+	Version                 = "1.3.0"
+	MessageTypeResourceLink = "LtiResourceLinkRequest"
+	ScopeScore              = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+	ClaimPrefix             = "https://purl.imsglobal.org/spec/lti/claim/"
+)
+
+// ErrInvalidLaunch is returned for id tokens that are not acceptable launches
+var ErrInvalidLaunch = errors.New("invalid LTI launch")
+
+// ResourceLink identifies the placement of the tool in the platform
+type ResourceLink struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// Context identifies the course the launch happened in
+type Context struct {
+	ID    string   `json:"id"`
+	Label string   `json:"label,omitempty"`
+	Title string   `json:"title,omitempty"`
+	Type  []string `json:"type,omitempty"`
+}
+
+// Endpoint is the Assignment and Grade Services claim
+type Endpoint struct {
+	Scope     []string `json:"scope"`
+	LineItems string   `json:"lineitems,omitempty"`
+	LineItem  string   `json:"lineitem,omitempty"`
+}
+
+// CanPublishScores reports whether the platform granted score passback to a line item
+func (e *Endpoint) CanPublishScores() bool {
+	return e != nil && e.LineItem != "" && slices.Contains(e.Scope, ScopeScore)
+}
+
+// LaunchClaims are the claims of a resource link launch id token
+type LaunchClaims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty string            `json:"azp,omitempty"`
+	Nonce           string            `json:"nonce"`
+	Name            string            `json:"name,omitempty"`
+	Email           string            `json:"email,omitempty"`
+	MessageType     string            `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version         string            `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentID    string            `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	TargetLinkURI   string            `json:"https://purl.imsglobal.org/spec/lti/claim/target_link_uri"`
+	ResourceLink    ResourceLink      `json:"https://purl.imsglobal.org/spec/lti/claim/resource_link"`
+	Context         *Context          `json:"https://purl.imsglobal.org/spec/lti/claim/context,omitempty"`
+	Roles           []string          `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+	Custom          map[string]string `json:"https://purl.imsglobal.org/spec/lti/claim/custom,omitempty"`
+	Endpoint        *Endpoint         `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint,omitempty"`
+}
+
+// KeySource resolves the platform key that signed an id token
+type KeySource struct {
+	// PublicKeyPEM is a fixed platform key; it takes precedence over JWKSURL
+	PublicKeyPEM string
+	JWKSURL      string
+	Client       *http.Client
+}
+
+// key returns the verification key for a key id
+func (s KeySource) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if s.PublicKeyPEM != "" {
+		return ParsePublicKeyPEM(s.PublicKeyPEM)
+	}
+	if s.JWKSURL == "" {
+		return nil, fmt.Errorf("platform has no verification key")
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	set, err := FetchJWKS(ctx, client, s.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+	return set.Key(kid)
+}
+
+// ParseLaunch verifies an id token signed by a platform and checks it is a
+// resource link launch for the given client and deployment
+func ParseLaunch(ctx context.Context, idToken string, keys KeySource, issuer, clientID, deploymentID string) (*LaunchClaims, error) {
+	claims := &LaunchClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return keys.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLaunch, err)
+	}
+
+	// With several audiences the tool must be the authorized party
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != clientID {
+		return nil, fmt.Errorf("%w: azp does not match client_id", ErrInvalidLaunch)
+	}
+
+	switch {
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidLaunch)
+	case claims.Nonce == "":
+		return nil, fmt.Errorf("%w: missing nonce", ErrInvalidLaunch)
+	case claims.MessageType != MessageTypeResourceLink:
+		return nil, fmt.Errorf("%w: unsupported message type %q", ErrInvalidLaunch, claims.MessageType)
+	case claims.Version != Version:
+		return nil, fmt.Errorf("%w: unsupported LTI version %q", ErrInvalidLaunch, claims.Version)
+	case claims.DeploymentID != deploymentID:
+		return nil, fmt.Errorf("%w: unknown deployment %q", ErrInvalidLaunch, claims.DeploymentID)
+	case claims.ResourceLink.ID == "":
+		return nil, fmt.Errorf("%w: missing resource link", ErrInvalidLaunch)
+	}
+
+	return claims, nil
+}
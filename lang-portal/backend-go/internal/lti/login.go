@@ -0,0 +1,53 @@
+package lti
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+)
+
+// LoginRequest is a third-party initiated OIDC login sent by a platform
+type LoginRequest struct {
+	Issuer         string `form:"iss" binding:"required"`
+	LoginHint      string `form:"login_hint" binding:"required"`
+	TargetLinkURI  string `form:"target_link_uri" binding:"required"`
+	LTIMessageHint string `form:"lti_message_hint"`
+	ClientID       string `form:"client_id"`
+	DeploymentID   string `form:"lti_deployment_id"`
+}
+
+// AuthRequestURL builds the platform authorization request answering a login.
+// The platform posts the id token back to redirectURI.
+func AuthRequestURL(authLoginURL, clientID, redirectURI string, login LoginRequest, state, nonce string) (string, error) {
+	u, err := url.Parse(authLoginURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid auth login URL: %w", err)
+	}
+
+	query := u.Query()
+	query.Set("scope", "openid")
+	query.Set("response_type", "id_token")
+	query.Set("response_mode", "form_post")
+	query.Set("prompt", "none")
+	query.Set("client_id", clientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("login_hint", login.LoginHint)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	if login.LTIMessageHint != "" {
+		query.Set("lti_message_hint", login.LTIMessageHint)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// RandomString returns a URL safe random value for states and nonces
+func RandomString() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,145 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"sync"
+)
+
+// maxImagePixels bounds the dimensions of uploaded images, so a small file cannot
+// decode into an enormous bitmap; thumbnails hold the decoded image and a copy of it,
+// up to 128MB at this size
+const maxImagePixels = 16_000_000
+
+// decoding lets one upload at a time decode an image for its thumbnail, so concurrent
+// uploads of large images do not multiply the memory it takes
+var decoding sync.Mutex
+
+// imageTypes maps the types http.DetectContentType sniffs to the image types accepted
+// for uploads, which are the ones the standard library decodes
+var imageTypes = map[string]string{
+	"image/png":  "image/png",
+	"image/jpeg": "image/jpeg",
+	"image/gif":  "image/gif",
+}
+
+// processedImage is a checked upload along with its thumbnail, if it needs one
+type processedImage struct {
+	contentType                     string
+	width, height                   int
+	thumbnail                       []byte
+	thumbnailType                   string
+	thumbnailWidth, thumbnailHeight int
+}
+
+// processImage checks that data is an accepted image and renders a thumbnail fitting a
+// square of size pixels; images already that small get none
+func processImage(data []byte, size int) (*processedImage, error) {
+	contentType, ok := imageTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, fmt.Errorf("image file must be PNG, JPEG or GIF")
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("image file is damaged: %v", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("image file must be at most %d pixels", maxImagePixels)
+	}
+
+	processed := &processedImage{contentType: contentType, width: config.Width, height: config.Height}
+	if config.Width <= size && config.Height <= size {
+		return processed, nil
+	}
+
+	width, height := size, size
+	if config.Width > config.Height {
+		height = max(1, config.Height*size/config.Width)
+	} else {
+		width = max(1, config.Width*size/config.Height)
+	}
+	thumbnail, isOpaque, err := decodeThumbnail(data, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if isOpaque {
+		err = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85})
+		processed.thumbnailType = "image/jpeg"
+	} else {
+		err = png.Encode(&buf, thumbnail)
+		processed.thumbnailType = "image/png"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	processed.thumbnail = buf.Bytes()
+	processed.thumbnailWidth, processed.thumbnailHeight = width, height
+	return processed, nil
+}
+
+// decodeThumbnail decodes an image and shrinks it to width by height pixels, reporting
+// whether it is opaque; only the thumbnail outlives the call
+func decodeThumbnail(data []byte, width, height int) (*image.NRGBA, bool, error) {
+	decoding.Lock()
+	defer decoding.Unlock()
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, false, fmt.Errorf("image file is damaged: %v", err)
+	}
+	return downscale(src, width, height), opaque(src), nil
+}
+
+// opaque reports whether an image has no transparent pixels, so it can become a JPEG
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// downscale shrinks src to width by height pixels, averaging the source pixels each
+// thumbnail pixel covers
+func downscale(src image.Image, width, height int) *image.NRGBA {
+	// Work on a copy with known layout rather than through the color model of every format
+	bounds := src.Bounds()
+	rgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, max((y+1)*srcH/height, y*srcH/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, max((x+1)*srcW/width, x*srcW/width+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					// Weigh colors by alpha so transparent pixels do not darken edges
+					alpha := uint64(p[3])
+					r += uint64(p[0]) * alpha
+					g += uint64(p[1]) * alpha
+					b += uint64(p[2]) * alpha
+					a += alpha
+					n++
+				}
+			}
+			c := color.NRGBA{A: uint8(a / n)}
+			if a > 0 {
+				c.R, c.G, c.B = uint8(r/a), uint8(g/a), uint8(b/a)
+			}
+			dst.SetNRGBA(x, y, c)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,322 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+	"lang-portal/internal/tts"
+)
+
+// MaxSpeechTextLength bounds the text spoken instead of a word, in characters
+const MaxSpeechTextLength = 200
+
+// audioTypes maps the types http.DetectContentType sniffs to the audio types accepted for
+// uploads; containers that also hold video are served as audio
+var audioTypes = map[string]string{
+	"audio/wave":      "audio/wav",
+	"audio/mpeg":      "audio/mpeg",
+	"application/ogg": "audio/ogg",
+	"video/webm":      "audio/webm",
+	"video/mp4":       "audio/mp4",
+}
+
+// Config limits uploads
+type Config struct {
+	// AudioMaxBytes and ImageMaxBytes are the largest uploads accepted
+	AudioMaxBytes int64
+	ImageMaxBytes int64
+	// ThumbnailSize is the side of the square thumbnails are scaled to fit, in pixels
+	ThumbnailSize int
+}
+
+// Service stores the pronunciation audio and mnemonic images of words and the thumbnails
+// of study activities; audio is uploaded or generated by text-to-speech
+type Service struct {
+	store       *Store
+	repo        repository.MediaRepository
+	words       repository.WordRepository
+	synthesizer tts.Synthesizer
+	cfg         Config
+
+	// mu keeps a file from being removed as unused while another request stores it again
+	mu sync.Mutex
+}
+
+// NewService creates a service storing files in store
+func NewService(store *Store, repo repository.MediaRepository, words repository.WordRepository, synthesizer tts.Synthesizer, cfg Config) *Service {
+	return &Service{store: store, repo: repo, words: words, synthesizer: synthesizer, cfg: cfg}
+}
+
+// MaxUploadSize is the largest upload accepted of any kind, in bytes
+func (s *Service) MaxUploadSize() int64 {
+	return max(s.cfg.AudioMaxBytes, s.cfg.ImageMaxBytes)
+}
+
+// GetWordAudio retrieves the pronunciation of a word
+func (s *Service) GetWordAudio(ctx context.Context, wordID int64) (*models.WordAudio, error) {
+	return s.repo.GetWordAudio(ctx, wordID)
+}
+
+// UploadWordAudio stores a recording as the pronunciation of a word, replacing its previous one
+func (s *Service) UploadWordAudio(ctx context.Context, wordID int64, r io.Reader) (*models.WordAudio, error) {
+	data, err := readUpload(r, "audio", s.cfg.AudioMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	contentType := detectAudioType(data)
+	if contentType == "" {
+		return nil, fmt.Errorf("audio file must be WAV, MP3, OGG, WebM or MP4 audio")
+	}
+	return s.saveAudio(ctx, data, contentType, &models.WordAudio{WordID: wordID, Source: models.AudioUpload})
+}
+
+// GenerateWordAudio speaks text, or the word itself when text is empty, and stores the
+// speech as the pronunciation of a word, replacing its previous one
+func (s *Service) GenerateWordAudio(ctx context.Context, wordID int64, text string) (*models.WordAudio, error) {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > MaxSpeechTextLength {
+		return nil, fmt.Errorf("text must be at most %d characters", MaxSpeechTextLength)
+	}
+	word, err := s.words.GetByID(ctx, wordID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("word not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		text = word.Kanji
+	}
+
+	data, contentType, err := s.synthesizer.Synthesize(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return s.saveAudio(ctx, data, contentType, &models.WordAudio{
+		WordID: wordID,
+		Source: models.AudioTTS,
+		Voice:  s.synthesizer.Voice(),
+	})
+}
+
+// DeleteWordAudio removes the pronunciation of a word, and its file when nothing else uses it
+func (s *Service) DeleteWordAudio(ctx context.Context, wordID int64) error {
+	return s.delete(func() ([]string, error) {
+		return s.repo.DeleteWordAudio(ctx, wordID)
+	})
+}
+
+// GetWordImage retrieves the mnemonic image of a word
+func (s *Service) GetWordImage(ctx context.Context, wordID int64) (*models.WordImage, error) {
+	return s.repo.GetWordImage(ctx, wordID)
+}
+
+// UploadWordImage stores an image as the mnemonic image of a word, replacing its previous one
+func (s *Service) UploadWordImage(ctx context.Context, wordID int64, r io.Reader) (*models.WordImage, error) {
+	var saved *models.WordImage
+	err := s.saveImage(ctx, r, func(image, thumbnail *models.MediaFile) ([]string, error) {
+		var unused []string
+		var err error
+		saved, unused, err = s.repo.SaveWordImage(ctx, wordID, image, thumbnail)
+		return unused, err
+	})
+	return saved, err
+}
+
+// DeleteWordImage removes the mnemonic image of a word
+func (s *Service) DeleteWordImage(ctx context.Context, wordID int64) error {
+	return s.delete(func() ([]string, error) {
+		return s.repo.DeleteWordImage(ctx, wordID)
+	})
+}
+
+// GetStudyActivityImage retrieves the uploaded thumbnail of a study activity
+func (s *Service) GetStudyActivityImage(ctx context.Context, activityID int64) (*models.StudyActivityImage, error) {
+	return s.repo.GetStudyActivityImage(ctx, activityID)
+}
+
+// UploadStudyActivityImage stores an image as the thumbnail of a study activity,
+// replacing its previous one
+func (s *Service) UploadStudyActivityImage(ctx context.Context, activityID int64, r io.Reader) (*models.StudyActivityImage, error) {
+	var saved *models.StudyActivityImage
+	err := s.saveImage(ctx, r, func(image, thumbnail *models.MediaFile) ([]string, error) {
+		var unused []string
+		var err error
+		saved, unused, err = s.repo.SaveStudyActivityImage(ctx, activityID, image, thumbnail)
+		return unused, err
+	})
+	return saved, err
+}
+
+// DeleteStudyActivityImage removes the uploaded thumbnail of a study activity
+func (s *Service) DeleteStudyActivityImage(ctx context.Context, activityID int64) error {
+	return s.delete(func() ([]string, error) {
+		return s.repo.DeleteStudyActivityImage(ctx, activityID)
+	})
+}
+
+// Prune removes the files nothing uses anymore, such as the media of deleted words,
+// and returns how many it removed
+func (s *Service) Prune(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hashes, err := s.repo.DeleteUnusedMedia(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, hash := range hashes {
+		s.remove(hash)
+	}
+	return len(hashes), nil
+}
+
+// Open opens a stored file along with its metadata; the caller closes the file
+func (s *Service) Open(ctx context.Context, hash string) (*models.MediaFile, *os.File, error) {
+	file, err := s.repo.GetMedia(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := s.store.Open(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, f, nil
+}
+
+// saveAudio stores audio and records it as the pronunciation of a word
+func (s *Service) saveAudio(ctx context.Context, data []byte, contentType string, audio *models.WordAudio) (*models.WordAudio, error) {
+	audio.CreatedAt = time.Now().UTC()
+	file := newMediaFile(data, contentType, audio.CreatedAt)
+	err := s.save(ctx, [][]byte{data}, func() ([]string, error) {
+		return s.repo.SaveWordAudio(ctx, file, audio)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return audio, nil
+}
+
+// saveImage checks an uploaded image, renders its thumbnail and hands both to record
+func (s *Service) saveImage(ctx context.Context, r io.Reader, record func(image, thumbnail *models.MediaFile) ([]string, error)) error {
+	data, err := readUpload(r, "image", s.cfg.ImageMaxBytes)
+	if err != nil {
+		return err
+	}
+	processed, err := processImage(data, s.cfg.ThumbnailSize)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	image := newMediaFile(data, processed.contentType, now)
+	image.Width, image.Height = processed.width, processed.height
+	blobs := [][]byte{data}
+	var thumbnail *models.MediaFile
+	if processed.thumbnail != nil {
+		thumbnail = newMediaFile(processed.thumbnail, processed.thumbnailType, now)
+		thumbnail.Width, thumbnail.Height = processed.thumbnailWidth, processed.thumbnailHeight
+		image.ThumbnailHash = thumbnail.Hash
+		blobs = append(blobs, processed.thumbnail)
+	}
+	return s.save(ctx, blobs, func() ([]string, error) {
+		return record(image, thumbnail)
+	})
+}
+
+// save stores blobs on disk, then records them with record, which returns the files it
+// left unused. When recording fails, the blobs are removed again unless already known.
+func (s *Service) save(ctx context.Context, blobs [][]byte, record func() ([]string, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, data := range blobs {
+		if _, err := s.store.Put(data); err != nil {
+			return err
+		}
+	}
+	unused, err := record()
+	if err != nil {
+		for _, data := range blobs {
+			hash := Hash(data)
+			if _, lookupErr := s.repo.GetMedia(ctx, hash); lookupErr != nil && lookupErr.Error() == "media not found" {
+				s.remove(hash)
+			}
+		}
+		return err
+	}
+	for _, hash := range unused {
+		s.remove(hash)
+	}
+	return nil
+}
+
+// delete runs a deletion returning the files it left unused, and removes them
+func (s *Service) delete(detach func() ([]string, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unused, err := detach()
+	if err != nil {
+		return err
+	}
+	for _, hash := range unused {
+		s.remove(hash)
+	}
+	return nil
+}
+
+// newMediaFile describes data about to be stored
+func newMediaFile(data []byte, contentType string, createdAt time.Time) *models.MediaFile {
+	return &models.MediaFile{
+		Hash:        Hash(data),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		CreatedAt:   createdAt,
+	}
+}
+
+// readUpload reads an upload of at most maxSize bytes
+func readUpload(r io.Reader, kind string, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", kind, err)
+	}
+	switch {
+	case len(data) == 0:
+		return nil, fmt.Errorf("%s file is empty", kind)
+	case int64(len(data)) > maxSize:
+		return nil, fmt.Errorf("%s file must be at most %d bytes", kind, maxSize)
+	}
+	return data, nil
+}
+
+// remove deletes a file no longer referenced; a failure only leaves an unused file behind
+func (s *Service) remove(hash string) {
+	if err := s.store.Remove(hash); err != nil {
+		log.Printf("Failed to remove unused media: %v", err)
+	}
+}
+
+// detectAudioType returns the content type of accepted audio, or an empty string
+func detectAudioType(data []byte) string {
+	if contentType, ok := audioTypes[http.DetectContentType(data)]; ok {
+		return contentType
+	}
+	// MP3 files without an ID3 tag start with a frame sync
+	if len(data) > 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 {
+		return "audio/mpeg"
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Store keeps files on disk under the SHA-256 of their content, in directories
+// named after the first two characters of the hash
+type Store struct {
+	root string
+}
+
+// NewStore creates a store in the root directory, creating the directory if needed
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	return &Store{root: root}, nil
+}
+
+// Hash returns the hash files are stored under
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// validHash reports whether hash is a hex SHA-256, so it is safe in a path
+func validHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+// path returns where a file is stored
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.root, hash[:2], hash)
+}
+
+// Put stores data and returns its hash. The file is written next to its final
+// path and renamed into place, so a file is never seen half written.
+func (s *Store) Put(data []byte) (string, error) {
+	hash := Hash(data)
+	dir := filepath.Dir(s.path(hash))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create media directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create media file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write media file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write media file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(hash)); err != nil {
+		return "", fmt.Errorf("failed to store media file: %w", err)
+	}
+	return hash, nil
+}
+
+// Open opens a stored file
+func (s *Store) Open(hash string) (*os.File, error) {
+	if !validHash(hash) {
+		return nil, fmt.Errorf("media not found")
+	}
+	f, err := os.Open(s.path(hash))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("media not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open media file: %w", err)
+	}
+	return f, nil
+}
+
+// Remove deletes a stored file, and its directory once empty; removing a missing
+// file is not an error
+func (s *Store) Remove(hash string) error {
+	if !validHash(hash) {
+		return nil
+	}
+	if err := os.Remove(s.path(hash)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove media file: %w", err)
+	}
+	// Fails while other files share the directory
+	os.Remove(filepath.Dir(s.path(hash)))
+	return nil
+}
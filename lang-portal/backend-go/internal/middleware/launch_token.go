@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/launch"
+)
+
+// LaunchClaimsKey is the context key holding the verified launch claims
+const LaunchClaimsKey = "launch_claims"
+
+// LaunchTokenAuth verifies the launch token sent as a Bearer token or ?token= query
+// parameter and stores its claims in the request context
+func LaunchTokenAuth(signer *launch.Signer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" {
+			token = c.Query("token")
+		}
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Launch token is required",
+			})
+			return
+		}
+
+		claims, err := signer.Verify(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Invalid launch token",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.Set(LaunchClaimsKey, claims)
+		c.Next()
+	}
+}
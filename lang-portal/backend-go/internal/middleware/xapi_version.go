@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/xapi"
+)
+
+// XAPIVersion requires the X-Experience-API-Version header on xAPI requests
+// and advertises the implemented version on every response
+func XAPIVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("X-Experience-API-Version", xapi.Version)
+
+		if !strings.HasPrefix(c.GetHeader("X-Experience-API-Version"), "1.0") {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Unsupported xAPI version",
+				"details": "X-Experience-API-Version header must be 1.0.x",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// AchievementAward records when an achievement was earned
+type AchievementAward struct {
+	AchievementID string    `json:"achievement_id"`
+	AwardedAt     time.Time `json:"awarded_at"`
+	// StudySessionID is the session the achievement was earned in, when there is one
+	StudySessionID *int64 `json:"study_session_id"`
+}
+
+// Achievement is a badge along with the learner's progress towards it
+type Achievement struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Progress is the current value of what the badge counts, out of Target
+	Progress int  `json:"progress"`
+	Target   int  `json:"target"`
+	Earned   bool `json:"earned"`
+	// AwardedAt and StudySessionID are set once the badge is earned
+	AwardedAt      *time.Time `json:"awarded_at"`
+	StudySessionID *int64     `json:"study_session_id"`
+}
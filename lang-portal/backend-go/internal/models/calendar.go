@@ -0,0 +1,35 @@
+package models
+
+// CalendarDay counts the study activity of one local day
+type CalendarDay struct {
+	Date     string `json:"date"`
+	Reviews  int    `json:"reviews"`
+	Sessions int    `json:"sessions"`
+}
+
+// Calendar is a year of study activity for a heatmap
+type Calendar struct {
+	Year          int           `json:"year"`
+	Timezone      string        `json:"timezone"`
+	TotalReviews  int           `json:"total_reviews"`
+	TotalSessions int           `json:"total_sessions"`
+	ActiveDays    int           `json:"active_days"`
+	MaxReviews    int           `json:"max_reviews"`
+	Days          []CalendarDay `json:"days"`
+}
+
+// NewCalendar sums up the days of a year
+func NewCalendar(year int, timezone string, days []CalendarDay) Calendar {
+	calendar := Calendar{Year: year, Timezone: timezone, Days: days}
+	for _, day := range days {
+		calendar.TotalReviews += day.Reviews
+		calendar.TotalSessions += day.Sessions
+		if day.Reviews > 0 || day.Sessions > 0 {
+			calendar.ActiveDays++
+		}
+		if day.Reviews > calendar.MaxReviews {
+			calendar.MaxReviews = day.Reviews
+		}
+	}
+	return calendar
+}
@@ -0,0 +1,53 @@
+package models
+
+// DictionaryEntry is an entry of the JMdict dictionary, reduced to its English senses
+type DictionaryEntry struct {
+	// ID is the JMdict entry sequence number, which stays the same across releases
+	ID       int64               `json:"id"`
+	Kanji    []DictionaryForm    `json:"kanji"`
+	Readings []DictionaryReading `json:"readings"`
+	Senses   []DictionarySense   `json:"senses"`
+	// Common is set when one of the forms or readings is among the common words
+	Common bool `json:"common"`
+}
+
+// DictionaryForm is a way of writing an entry with kanji
+type DictionaryForm struct {
+	Text   string `json:"text"`
+	Common bool   `json:"common"`
+}
+
+// DictionaryReading is a kana reading of an entry
+type DictionaryReading struct {
+	Text   string `json:"text"`
+	Common bool   `json:"common"`
+	// Kanji lists the only forms the reading belongs to; empty means all of them
+	Kanji []string `json:"kanji,omitempty"`
+	// NoKanji is set for readings that belong to none of the forms
+	NoKanji bool `json:"no_kanji,omitempty"`
+}
+
+// DictionarySense is a meaning of an entry
+type DictionarySense struct {
+	PartsOfSpeech []string `json:"parts_of_speech"`
+	Glosses       []string `json:"glosses"`
+	// Misc holds usage notes, such as the word being usually written in kana
+	Misc []string `json:"misc,omitempty"`
+	// Kanji and Readings list the only forms and readings the sense applies to
+	Kanji    []string `json:"kanji,omitempty"`
+	Readings []string `json:"readings,omitempty"`
+}
+
+// BelongsTo reports whether a reading is a reading of the form kanji
+func (r DictionaryReading) BelongsTo(kanji string) bool {
+	if r.NoKanji {
+		return false
+	}
+	return len(r.Kanji) == 0 || contains(r.Kanji, kanji)
+}
+
+// AppliesTo reports whether a sense applies to a form written as text, read as reading
+func (s DictionarySense) AppliesTo(text, reading string) bool {
+	return (len(s.Kanji) == 0 || contains(s.Kanji, text)) &&
+		(len(s.Readings) == 0 || contains(s.Readings, reading))
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Goal metrics
+const (
+	// GoalMetricReviews counts word reviews
+	GoalMetricReviews = "reviews"
+	// GoalMetricMinutes sums study session durations
+	GoalMetricMinutes = "minutes"
+	// GoalMetricNewWords counts words answered correctly for the first time
+	GoalMetricNewWords = "new_words"
+)
+
+// GoalMetrics lists the accepted goal metrics
+var GoalMetrics = []string{GoalMetricReviews, GoalMetricMinutes, GoalMetricNewWords}
+
+// Goal periods
+const (
+	GoalPeriodDaily  = "daily"
+	GoalPeriodWeekly = "weekly"
+)
+
+// GoalPeriods lists the accepted goal periods
+var GoalPeriods = []string{GoalPeriodDaily, GoalPeriodWeekly}
+
+// MaxGoalTarget bounds goal targets, so typos do not create goals nobody can reach
+const MaxGoalTarget = 100000
+
+// MaxGoalHistoryPeriods bounds the length of a goal attainment history
+const MaxGoalHistoryPeriods = 400
+
+// Goal is a daily or weekly target of a learner
+type Goal struct {
+	ID int64 `json:"id"`
+	// LearnerID is nil for goals of the portal's own user
+	LearnerID *int64    `json:"learner_id"`
+	Metric    string    `json:"metric"`
+	Period    string    `json:"period"`
+	Target    int       `json:"target"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the metric, period and target of a goal
+func (g *Goal) Validate() error {
+	if !contains(GoalMetrics, g.Metric) {
+		return fmt.Errorf("metric must be one of: %s", strings.Join(GoalMetrics, ", "))
+	}
+	if !contains(GoalPeriods, g.Period) {
+		return fmt.Errorf("period must be one of: %s", strings.Join(GoalPeriods, ", "))
+	}
+	if g.Target < 1 || g.Target > MaxGoalTarget {
+		return fmt.Errorf("target must be between 1 and %d", MaxGoalTarget)
+	}
+	return nil
+}
+
+// PeriodStart returns the local midnight starting the period of the goal that contains t;
+// weeks start on Monday
+func (g *Goal) PeriodStart(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	if g.Period == GoalPeriodWeekly {
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	}
+	return start
+}
+
+// NextPeriod returns the start of the period following the one starting at start
+func (g *Goal) NextPeriod(start time.Time) time.Time {
+	if g.Period == GoalPeriodWeekly {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// GoalTotals is the study activity of one local day that goals are measured against
+type GoalTotals struct {
+	Reviews  int
+	Seconds  int64
+	NewWords int
+}
+
+// Add sums the activity of another day
+func (t *GoalTotals) Add(other GoalTotals) {
+	t.Reviews += other.Reviews
+	t.Seconds += other.Seconds
+	t.NewWords += other.NewWords
+}
+
+// Value returns the amount of a goal metric; started minutes do not count
+func (t GoalTotals) Value(metric string) int {
+	switch metric {
+	case GoalMetricMinutes:
+		return int(t.Seconds / 60)
+	case GoalMetricNewWords:
+		return t.NewWords
+	default:
+		return t.Reviews
+	}
+}
+
+// GoalPeriodResult is the progress of a goal over one period
+type GoalPeriodResult struct {
+	// Start and End are the first and last local day of the period
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Value int    `json:"value"`
+	// Percent is the share of the target reached, capped at 100
+	Percent int  `json:"percent"`
+	Met     bool `json:"met"`
+	// Current is set for the period that has not ended yet
+	Current bool `json:"current"`
+	// BeforeGoal is set for periods that started before the goal was created, which
+	// attainment leaves out
+	BeforeGoal bool `json:"before_goal"`
+}
+
+// NewGoalPeriodResult compares the value reached in the period starting at start with the target of g
+func NewGoalPeriodResult(g *Goal, start time.Time, value int, current bool) GoalPeriodResult {
+	percent := 100
+	if value < g.Target {
+		percent = 100 * value / g.Target
+	}
+	return GoalPeriodResult{
+		Start:      start.Format(HistoryDateFormat),
+		End:        g.NextPeriod(start).AddDate(0, 0, -1).Format(HistoryDateFormat),
+		Value:      value,
+		Percent:    percent,
+		Met:        value >= g.Target,
+		Current:    current,
+		BeforeGoal: start.Before(g.CreatedAt),
+	}
+}
+
+// GoalProgress is a goal with its progress in the current period
+type GoalProgress struct {
+	Goal
+	Progress GoalPeriodResult `json:"progress"`
+}
+
+// GoalHistory is the attainment of a goal over past periods, oldest first
+type GoalHistory struct {
+	Goal    Goal               `json:"goal"`
+	Periods []GoalPeriodResult `json:"periods"`
+	// Met counts the ended periods whose target was reached, out of Ended; periods that
+	// started before the goal was created are not counted
+	Met   int `json:"met"`
+	Ended int `json:"ended"`
+	// Attainment is the percentage of counted ended periods whose target was reached
+	Attainment int `json:"attainment"`
+}
+
+// GoalDay counts the daily goals met on one local day
+type GoalDay struct {
+	Date  string `json:"date"`
+	Met   int    `json:"met"`
+	Total int    `json:"total"`
+}
+
+// DashboardGoals summarizes goal progress for the dashboard
+type DashboardGoals struct {
+	Goals []GoalProgress `json:"goals"`
+	// Met counts the active goals already reached in their current period
+	Met   int `json:"met"`
+	Total int `json:"total"`
+	// Days counts the daily goals met on each recent day, oldest first
+	Days []GoalDay `json:"days"`
+}
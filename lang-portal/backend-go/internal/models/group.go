@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 // Group represents a collection of words
@@ -36,6 +38,18 @@ type GroupsResponse struct {
 	CurrentPage int     `json:"current_page"`
 }
 
+// Sort orders accepted by list endpoints
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// GroupSortFields lists the fields groups can be sorted by
+var GroupSortFields = []string{"name", "word_count", "correct_count", "wrong_count"}
+
+// GroupWordSortFields lists the fields group words can be sorted by
+var GroupWordSortFields = []string{"id", "kanji", "romaji", "english", "correct_count", "wrong_count", "accuracy"}
+
 // GroupQueryParams represents the query parameters for group-related requests
 type GroupQueryParams struct {
 	Page    int
@@ -50,8 +64,70 @@ func DefaultGroupQueryParams() GroupQueryParams {
 		Page:    1,
 		PerPage: 10,
 		SortBy:  "name",
-		Order:   "asc",
+		Order:   OrderAsc,
+	}
+}
+
+// Validate checks that the sort field and order are supported
+func (p GroupQueryParams) Validate() error {
+	return validateSort(p.SortBy, p.Order, GroupSortFields)
+}
+
+// GroupWordQueryParams represents the query parameters for listing words in a group
+type GroupWordQueryParams struct {
+	Page    int
+	PerPage int
+	SortBy  string
+	Order   string
+	// MaxAccuracy keeps only reviewed words whose accuracy (0-100) is below this value
+	MaxAccuracy *float64
+	// NeverReviewed keeps only words without any review
+	NeverReviewed bool
+}
+
+// DefaultGroupWordQueryParams returns the default query parameters for group words
+func DefaultGroupWordQueryParams() GroupWordQueryParams {
+	return GroupWordQueryParams{
+		Page:    1,
+		PerPage: 100,
+		SortBy:  "id",
+		Order:   OrderAsc,
+	}
+}
+
+// Validate checks the sort options and filters
+func (p GroupWordQueryParams) Validate() error {
+	if err := validateSort(p.SortBy, p.Order, GroupWordSortFields); err != nil {
+		return err
+	}
+	if p.MaxAccuracy != nil && (*p.MaxAccuracy < 0 || *p.MaxAccuracy > 100) {
+		return fmt.Errorf("max_accuracy must be between 0 and 100")
+	}
+	if p.MaxAccuracy != nil && p.NeverReviewed {
+		return fmt.Errorf("max_accuracy and never_reviewed cannot be combined")
+	}
+	return nil
+}
+
+// validateSort checks a sort field against a whitelist and the order against asc/desc
+func validateSort(sortBy, order string, allowed []string) error {
+	if order != OrderAsc && order != OrderDesc {
+		return fmt.Errorf("order must be %q or %q", OrderAsc, OrderDesc)
+	}
+	if !contains(allowed, sortBy) {
+		return fmt.Errorf("sort_by must be one of: %s", strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// contains reports whether value is in values
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
+	return false
 }
 
 // RawGroupWordsResponse represents the raw words in a group
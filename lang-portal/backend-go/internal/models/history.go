@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// History bucket sizes
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// HistoryBuckets lists the accepted bucket sizes
+var HistoryBuckets = []string{BucketDay, BucketWeek, BucketMonth}
+
+// MaxHistoryBuckets bounds the length of a series
+const MaxHistoryBuckets = 400
+
+// HistoryDateFormat is the format of history dates, which are UTC days
+const HistoryDateFormat = "2006-01-02"
+
+// HistoryQueryParams selects the range, bucket size and scope of a history series
+type HistoryQueryParams struct {
+	// From and To are the first and last UTC day of the range, inclusive
+	From time.Time
+	To   time.Time
+	// Bucket is day, week (starting Monday) or month
+	Bucket string
+	// GroupID and StudyActivityID limit the series to sessions of a group or activity
+	GroupID         *int64
+	StudyActivityID *int64
+}
+
+// DefaultHistoryQueryParams returns daily buckets over the last 30 days
+func DefaultHistoryQueryParams(now time.Time) HistoryQueryParams {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return HistoryQueryParams{
+		From:   today.AddDate(0, 0, -29),
+		To:     today,
+		Bucket: BucketDay,
+	}
+}
+
+// Validate checks the bucket size and range
+func (p HistoryQueryParams) Validate() error {
+	if !contains(HistoryBuckets, p.Bucket) {
+		return fmt.Errorf("bucket must be one of: %s", strings.Join(HistoryBuckets, ", "))
+	}
+	if p.To.Before(p.From) {
+		return fmt.Errorf("from must not be after to")
+	}
+	if len(p.BucketStarts()) > MaxHistoryBuckets {
+		return fmt.Errorf("range spans more than %d buckets, use a larger bucket", MaxHistoryBuckets)
+	}
+	return nil
+}
+
+// BucketStart returns the first day of the bucket containing day
+func (p HistoryQueryParams) BucketStart(day time.Time) time.Time {
+	day = day.UTC().Truncate(24 * time.Hour)
+	switch p.Bucket {
+	case BucketWeek:
+		// Weeks start on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// BucketStarts returns the first day of every bucket in the range, so series have no gaps
+func (p HistoryQueryParams) BucketStarts() []time.Time {
+	var starts []time.Time
+	for start := p.BucketStart(p.From); !start.After(p.To); start = p.nextBucket(start) {
+		starts = append(starts, start)
+		if len(starts) > MaxHistoryBuckets {
+			break
+		}
+	}
+	return starts
+}
+
+// Range widens From and To to whole buckets, so the first and last bucket are complete
+func (p HistoryQueryParams) Range() (from, to time.Time) {
+	return p.BucketStart(p.From), p.nextBucket(p.BucketStart(p.To)).AddDate(0, 0, -1)
+}
+
+// nextBucket returns the start of the bucket following start
+func (p HistoryQueryParams) nextBucket(start time.Time) time.Time {
+	switch p.Bucket {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// ReviewHistoryPoint counts the reviews of one bucket
+type ReviewHistoryPoint struct {
+	Date     string `json:"date"`
+	Reviews  int    `json:"reviews"`
+	Correct  int    `json:"correct"`
+	Accuracy int    `json:"accuracy"`
+}
+
+// NewWordsHistoryPoint counts the words answered correctly for the first time in one bucket
+type NewWordsHistoryPoint struct {
+	Date     string `json:"date"`
+	NewWords int    `json:"new_words"`
+	// TotalWords is the running total of learned words at the end of the bucket
+	TotalWords int `json:"total_words"`
+}
+
+// TimeSpentHistoryPoint sums the study time of the sessions started in one bucket
+type TimeSpentHistoryPoint struct {
+	Date     string `json:"date"`
+	Sessions int    `json:"sessions"`
+	Seconds  int64  `json:"seconds"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// Radical types of KANJIDIC2: the classical Kangxi radical and the one of Nelson's dictionary
+const (
+	RadicalClassical = "classical"
+	RadicalNelson    = "nelson_c"
+)
+
+// Kanji is a character of the kanji dictionary
+type Kanji struct {
+	Literal     string    `json:"literal"`
+	Meanings    []string  `json:"meanings"`
+	OnReadings  []string  `json:"on_readings"`
+	KunReadings []string  `json:"kun_readings"`
+	StrokeCount int       `json:"stroke_count"`
+	Radicals    []Radical `json:"radicals"`
+	// Grade is the school grade the kanji is taught in: 1-6 for the kyouiku kanji,
+	// 8 for the rest of the jouyou kanji and 9-10 for kanji used in names
+	Grade *int `json:"grade"`
+	// JLPT is the level of the former four-level JLPT, 4 being the easiest
+	JLPT *int `json:"jlpt"`
+	// Frequency is the rank among the 2500 most used kanji in newspapers
+	Frequency *int `json:"frequency"`
+}
+
+// Radical is one of the 214 Kangxi radicals a kanji is classified under
+type Radical struct {
+	Type   string `json:"type"`
+	Number int    `json:"number"`
+	// Symbol is the radical as a character of the Kangxi Radicals block
+	Symbol string `json:"symbol"`
+}
+
+// RadicalSymbol returns the character of a radical number from the Kangxi Radicals
+// block, which lists the radicals in order
+func RadicalSymbol(number int) string {
+	if number < 1 || number > 214 {
+		return ""
+	}
+	return string(rune(0x2F00 + number - 1))
+}
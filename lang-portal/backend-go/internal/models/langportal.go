@@ -3,6 +3,8 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -13,6 +15,11 @@ type Word struct {
 	Romaji  string          `json:"romaji"`
 	English string          `json:"english"`
 	Parts   json.RawMessage `json:"parts"`
+	// AudioURL is where the word's pronunciation is served, when it has one
+	AudioURL string `json:"audio_url,omitempty"`
+	// ImageURL and ImageThumbnailURL are where the word's mnemonic image is served
+	ImageURL          string `json:"image_url,omitempty"`
+	ImageThumbnailURL string `json:"image_thumbnail_url,omitempty"`
 }
 
 // UnmarshalParts attempts to parse the parts column safely
@@ -36,17 +43,60 @@ func (w *Word) UnmarshalParts(data interface{}) error {
 
 // StudyActivity represents different types of study activities
 type StudyActivity struct {
-	ID     int64  `json:"id"`
-	Name   string `json:"name"`
-	URL    string `json:"thumbnail_url"`
+	ID                 int64      `json:"id"`
+	Name               string     `json:"name"`
+	URL                string     `json:"url"`
+	ThumbnailURL       string     `json:"thumbnail_url"`
+	Description        string     `json:"description"`
+	SupportedModes     []string   `json:"supported_modes"`
+	RequiredWordFields []string   `json:"required_word_fields"`
+	RetiredAt          *time.Time `json:"retired_at,omitempty"`
+}
+
+// WordFields lists the word fields a study activity can require
+var WordFields = []string{"kanji", "romaji", "english", "parts"}
+
+// Validate checks the required fields of a study activity
+func (a *StudyActivity) Validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(a.URL) == "" {
+		return fmt.Errorf("url is required")
+	}
+	// Activities are launched in a browser, so their launch URL template must be absolute
+	if u, err := url.Parse(a.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	for _, mode := range a.SupportedModes {
+		if strings.TrimSpace(mode) == "" {
+			return fmt.Errorf("supported_modes cannot contain empty values")
+		}
+	}
+	for _, field := range a.RequiredWordFields {
+		if !contains(WordFields, field) {
+			return fmt.Errorf("required_word_fields must only contain: %s", strings.Join(WordFields, ", "))
+		}
+	}
+	return nil
 }
 
 // StudySession represents an individual study session
 type StudySession struct {
-	ID              int64     `json:"id"`
-	GroupID         int64     `json:"group_id"`
-	StudyActivityID int64     `json:"study_activity_id"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              int64      `json:"id"`
+	GroupID         int64      `json:"group_id"`
+	StudyActivityID int64      `json:"study_activity_id"`
+	LearnerID       *int64     `json:"learner_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+// Learner represents a person studying through the portal
+type Learner struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // WordReviewItem represents a review of a word during a study session
@@ -57,3 +107,34 @@ type WordReviewItem struct {
 	Correct        bool      `json:"correct"`
 	CreatedAt      time.Time `json:"created_at"`
 }
+
+// StudySessionSortFields lists the fields study sessions can be sorted by
+var StudySessionSortFields = []string{"start_time", "activity_name", "group_name", "total_words_reviewed"}
+
+// StudySessionQueryParams represents the query parameters for listing study sessions
+type StudySessionQueryParams struct {
+	Page            int
+	PerPage         int
+	SortBy          string
+	Order           string
+	StudyActivityID int64
+	GroupID         int64
+	// StartedFrom and StartedBefore limit sessions to those started in [StartedFrom, StartedBefore) when set
+	StartedFrom   time.Time
+	StartedBefore time.Time
+}
+
+// DefaultStudySessionQueryParams returns the default query parameters for study sessions
+func DefaultStudySessionQueryParams() StudySessionQueryParams {
+	return StudySessionQueryParams{
+		Page:    1,
+		PerPage: 100,
+		SortBy:  "start_time",
+		Order:   OrderDesc,
+	}
+}
+
+// Validate checks that the sort field and order are supported
+func (p StudySessionQueryParams) Validate() error {
+	return validateSort(p.SortBy, p.Order, StudySessionSortFields)
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Leaderboard metrics
+const (
+	// LeaderboardReviews ranks by reviews recorded
+	LeaderboardReviews = "reviews"
+	// LeaderboardAccuracy ranks by the percentage of correct answers
+	LeaderboardAccuracy = "accuracy"
+	// LeaderboardStreak ranks by the current streak for weekly boards, the longest one for all-time boards
+	LeaderboardStreak = "streak"
+	// LeaderboardWordsMastered ranks by mastered words; weekly boards count the words mastered this week
+	LeaderboardWordsMastered = "words_mastered"
+)
+
+// LeaderboardMetrics lists the accepted leaderboard metrics
+var LeaderboardMetrics = []string{LeaderboardReviews, LeaderboardAccuracy, LeaderboardStreak, LeaderboardWordsMastered}
+
+// Leaderboard periods
+const (
+	LeaderboardWeekly  = "weekly"
+	LeaderboardAllTime = "all_time"
+)
+
+// LeaderboardPeriods lists the accepted leaderboard periods
+var LeaderboardPeriods = []string{LeaderboardWeekly, LeaderboardAllTime}
+
+// MaxLeaderboardLimit bounds the entries of a leaderboard
+const MaxLeaderboardLimit = 100
+
+// LeaderboardQueryParams selects the metric, period and scope of a leaderboard
+type LeaderboardQueryParams struct {
+	Metric string
+	Period string
+	// ClassID limits the board to the study sessions of a class; nil ranks all learners
+	ClassID *int64
+	Limit   int
+	// MinReviews is the number of reviews a learner needs to be ranked by accuracy
+	MinReviews int
+}
+
+// DefaultLeaderboardQueryParams returns the weekly top 10 by reviews of all learners
+func DefaultLeaderboardQueryParams() LeaderboardQueryParams {
+	return LeaderboardQueryParams{
+		Metric:     LeaderboardReviews,
+		Period:     LeaderboardWeekly,
+		Limit:      10,
+		MinReviews: 20,
+	}
+}
+
+// Validate checks the metric, period and limits
+func (p LeaderboardQueryParams) Validate() error {
+	if !contains(LeaderboardMetrics, p.Metric) {
+		return fmt.Errorf("metric must be one of: %s", strings.Join(LeaderboardMetrics, ", "))
+	}
+	if !contains(LeaderboardPeriods, p.Period) {
+		return fmt.Errorf("period must be one of: %s", strings.Join(LeaderboardPeriods, ", "))
+	}
+	if p.Limit < 1 || p.Limit > MaxLeaderboardLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxLeaderboardLimit)
+	}
+	if p.MinReviews < 1 {
+		return fmt.Errorf("min_reviews must be positive")
+	}
+	return nil
+}
+
+// LeaderboardEntry is the rank of one learner; learners with the same value share a rank
+type LeaderboardEntry struct {
+	Rank      int    `json:"rank"`
+	LearnerID int64  `json:"learner_id"`
+	Name      string `json:"name"`
+	Value     int    `json:"value"`
+	// Reviews is the number of reviews an accuracy is based on
+	Reviews int `json:"reviews,omitempty"`
+}
+
+// Leaderboard ranks learners by one metric
+type Leaderboard struct {
+	Metric  string `json:"metric"`
+	Period  string `json:"period"`
+	ClassID *int64 `json:"class_id"`
+	// WeekStart is the first day of the week weekly boards count
+	WeekStart string `json:"week_start,omitempty"`
+	Timezone  string `json:"timezone"`
+	// ComputedAt is when the statistics were last computed from history
+	ComputedAt time.Time          `json:"computed_at"`
+	Items      []LeaderboardEntry `json:"items"`
+	// TotalCount is the number of ranked learners, before the limit
+	TotalCount int `json:"total_count"`
+}
+
+// LearnerPrivacy holds how a learner appears on leaderboards
+type LearnerPrivacy struct {
+	LearnerID int64  `json:"learner_id"`
+	Name      string `json:"name"`
+	// DisplayName is shown instead of the name when set
+	DisplayName string `json:"display_name"`
+	// LeaderboardOptOut hides the learner from every leaderboard
+	LeaderboardOptOut bool `json:"leaderboard_opt_out"`
+}
+
+// MaxDisplayNameLength bounds display names, in characters
+const MaxDisplayNameLength = 50
+
+// Validate checks the display name
+func (p *LearnerPrivacy) Validate() error {
+	if utf8.RuneCountInString(p.DisplayName) > MaxDisplayNameLength {
+		return fmt.Errorf("display_name must be at most %d characters", MaxDisplayNameLength)
+	}
+	return nil
+}
+
+// PublicName returns the name shown on leaderboards
+func (p *LearnerPrivacy) PublicName() string {
+	if p.DisplayName != "" {
+		return p.DisplayName
+	}
+	return p.Name
+}
+
+// Class is an LTI course, studied through the group it is mapped to
+type Class struct {
+	// ID is the ID of the course's group
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	PlatformID int64  `json:"platform_id"`
+	ContextID  string `json:"context_id"`
+	// Learners counts the learners with study sessions in the class
+	Learners int `json:"learners"`
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// NeedsWorkGroupName is the group leeches are collected in for targeted practice
+const NeedsWorkGroupName = "Needs work"
+
+// DefaultLeechThreshold is the number of lapses that makes a word a leech
+const DefaultLeechThreshold = 8
+
+// DefaultRecentReviews is how many of a word's latest reviews the recent failure rate is computed over
+const DefaultRecentReviews = 10
+
+// ProblemWord is a word with its review record, ranked by how often it was failed lately.
+// A lapse is a wrong answer.
+type ProblemWord struct {
+	ID                int64     `json:"id"`
+	Kanji             string    `json:"kanji"`
+	Romaji            string    `json:"romaji"`
+	English           string    `json:"english"`
+	Reviews           int       `json:"reviews"`
+	CorrectCount      int       `json:"correct_count"`
+	Lapses            int       `json:"lapses"`
+	RecentReviews     int       `json:"recent_reviews"`
+	RecentFailureRate int       `json:"recent_failure_rate"`
+	LastReviewedAt    time.Time `json:"last_reviewed_at"`
+	IsLeech           bool      `json:"is_leech"`
+}
+
+// LeechQueryParams selects leeches across all words
+type LeechQueryParams struct {
+	Page    int
+	PerPage int
+	// Threshold is the number of lapses that makes a word a leech
+	Threshold int
+	// Recent is how many of the latest reviews the failure rate is computed over
+	Recent int
+}
+
+// DefaultLeechQueryParams returns the default query parameters for leeches
+func DefaultLeechQueryParams(threshold int) LeechQueryParams {
+	return LeechQueryParams{
+		Page:      1,
+		PerPage:   50,
+		Threshold: threshold,
+		Recent:    DefaultRecentReviews,
+	}
+}
+
+// Validate checks the threshold and window
+func (p LeechQueryParams) Validate() error {
+	if p.Threshold < 1 {
+		return fmt.Errorf("threshold must be at least 1")
+	}
+	return validateRecent(p.Recent)
+}
+
+// ProblemWordQueryParams selects the most failed words of a group
+type ProblemWordQueryParams struct {
+	// Limit is the number of words in the report
+	Limit int
+	// MinReviews leaves out words with too few reviews to judge
+	MinReviews int
+	// Recent is how many of the latest reviews the failure rate is computed over
+	Recent int
+	// Threshold is the number of lapses that makes a word a leech
+	Threshold int
+}
+
+// DefaultProblemWordQueryParams returns the default query parameters for problem word reports
+func DefaultProblemWordQueryParams(threshold int) ProblemWordQueryParams {
+	return ProblemWordQueryParams{
+		Limit:      20,
+		MinReviews: 3,
+		Recent:     DefaultRecentReviews,
+		Threshold:  threshold,
+	}
+}
+
+// Validate checks the report size and window
+func (p ProblemWordQueryParams) Validate() error {
+	if p.Limit < 1 || p.Limit > 100 {
+		return fmt.Errorf("limit must be between 1 and 100")
+	}
+	if p.MinReviews < 1 {
+		return fmt.Errorf("min_reviews must be at least 1")
+	}
+	return validateRecent(p.Recent)
+}
+
+// validateRecent checks the size of the recent review window
+func validateRecent(recent int) error {
+	if recent < 1 || recent > 100 {
+		return fmt.Errorf("recent must be between 1 and 100")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LTIPlatform represents an LMS registered as an LTI 1.3 platform
+type LTIPlatform struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Issuer       string    `json:"issuer"`
+	ClientID     string    `json:"client_id"`
+	DeploymentID string    `json:"deployment_id"`
+	AuthLoginURL string    `json:"auth_login_url"`
+	AuthTokenURL string    `json:"auth_token_url"`
+	JWKSURL      string    `json:"jwks_url"`
+	PublicKeyPEM string    `json:"public_key_pem,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Validate checks the required fields of a platform registration
+func (p *LTIPlatform) Validate() error {
+	required := map[string]string{
+		"name":           p.Name,
+		"issuer":         p.Issuer,
+		"client_id":      p.ClientID,
+		"deployment_id":  p.DeploymentID,
+		"auth_login_url": p.AuthLoginURL,
+		"auth_token_url": p.AuthTokenURL,
+	}
+	for _, field := range []string{"name", "issuer", "client_id", "deployment_id", "auth_login_url", "auth_token_url"} {
+		if strings.TrimSpace(required[field]) == "" {
+			return fmt.Errorf("%s is required", field)
+		}
+	}
+	if p.JWKSURL == "" && p.PublicKeyPEM == "" {
+		return fmt.Errorf("jwks_url or public_key_pem is required")
+	}
+	return nil
+}
+
+// LTILaunch links a study session to the LTI launch that started it
+type LTILaunch struct {
+	StudySessionID int64
+	PlatformID     int64
+	Subject        string
+	LineItemURL    string
+}
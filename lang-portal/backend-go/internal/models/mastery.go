@@ -0,0 +1,134 @@
+package models
+
+import "time"
+
+// Mastery levels of a word
+const (
+	// MasteryNew words were never reviewed
+	MasteryNew = "new"
+	// MasteryLearning words were reviewed but were answered wrong recently
+	MasteryLearning = "learning"
+	// MasteryFamiliar words were answered correctly a few times in a row
+	MasteryFamiliar = "familiar"
+	// MasteryMastered words were answered correctly many times in a row, on different days
+	MasteryMastered = "mastered"
+)
+
+// Mastery thresholds on the run of correct answers since the last wrong one
+const (
+	FamiliarRun = 2
+	MasteredRun = 5
+	// MasteredRunDays keeps a single cramming session from mastering a word
+	MasteredRunDays = 2
+)
+
+// ClassifyMastery returns the mastery level of a word from its number of reviews,
+// its run of correct answers since the last wrong one and the days that run spans
+func ClassifyMastery(reviews, run, runDays int) string {
+	switch {
+	case reviews == 0:
+		return MasteryNew
+	case run >= MasteredRun && runDays >= MasteredRunDays:
+		return MasteryMastered
+	case run >= FamiliarRun:
+		return MasteryFamiliar
+	default:
+		return MasteryLearning
+	}
+}
+
+// MasteryRun follows the mastery level of one word review by review, oldest first and
+// reviews of the same time by id, which is the order the mastery queries count runs in
+type MasteryRun struct {
+	reviews int
+	run     int
+	runDays map[string]bool
+}
+
+// Review records an answer given at t
+func (m *MasteryRun) Review(correct bool, at time.Time) {
+	m.reviews++
+	if !correct {
+		m.run = 0
+		m.runDays = nil
+		return
+	}
+	if m.runDays == nil {
+		m.runDays = map[string]bool{}
+	}
+	m.run++
+	// Run days are UTC days, like the mastery queries count them
+	m.runDays[at.UTC().Format(HistoryDateFormat)] = true
+}
+
+// Level returns the mastery level after the reviews recorded so far
+func (m *MasteryRun) Level() string {
+	return ClassifyMastery(m.reviews, m.run, len(m.runDays))
+}
+
+// MasteryCounts counts words per mastery level
+type MasteryCounts struct {
+	New      int `json:"new"`
+	Learning int `json:"learning"`
+	Familiar int `json:"familiar"`
+	Mastered int `json:"mastered"`
+	Total    int `json:"total"`
+	// Completion is the percentage of mastered words
+	Completion int `json:"completion"`
+}
+
+// Add counts one word of the given level
+func (m *MasteryCounts) Add(level string) {
+	switch level {
+	case MasteryNew:
+		m.New++
+	case MasteryLearning:
+		m.Learning++
+	case MasteryFamiliar:
+		m.Familiar++
+	case MasteryMastered:
+		m.Mastered++
+	}
+	m.Total++
+	m.Completion = 100 * m.Mastered / m.Total
+}
+
+// WordMastery is the mastery of one word
+type WordMastery struct {
+	ID         int64  `json:"id"`
+	Kanji      string `json:"kanji"`
+	Romaji     string `json:"romaji"`
+	English    string `json:"english"`
+	Level      string `json:"level"`
+	Reviews    int    `json:"reviews"`
+	CorrectRun int    `json:"correct_run"`
+}
+
+// GroupMastery is the mastery of the words of a group
+type GroupMastery struct {
+	GroupID   int64  `json:"group_id"`
+	GroupName string `json:"group_name"`
+	MasteryCounts
+	Words []WordMastery `json:"words"`
+}
+
+// GroupMasterySummary counts the mastery levels of a group
+type GroupMasterySummary struct {
+	GroupID   int64  `json:"group_id"`
+	GroupName string `json:"group_name"`
+	MasteryCounts
+}
+
+// ActivityMasterySummary counts the mastery levels of the words practiced with a study activity
+type ActivityMasterySummary struct {
+	StudyActivityID   int64  `json:"study_activity_id"`
+	StudyActivityName string `json:"study_activity_name"`
+	MasteryCounts
+}
+
+// MasteryBreakdown counts mastery levels overall, per group and per study activity
+type MasteryBreakdown struct {
+	Overall         MasteryCounts            `json:"overall"`
+	Groups          []GroupMasterySummary    `json:"groups"`
+	StudyActivities []ActivityMasterySummary `json:"study_activities"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// MediaPath is the path media files are served under, followed by their hash
+const MediaPath = "/api/v1/media/"
+
+// MediaURL returns the path a media file is served at
+func MediaURL(hash string) string {
+	return MediaPath + hash
+}
+
+// MediaFile describes a stored file, identified by the SHA-256 of its content
+type MediaFile struct {
+	Hash        string    `json:"hash"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Width and Height are set for images
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+	// ThumbnailHash is the smaller rendition of an image, if it needs one
+	ThumbnailHash string `json:"-"`
+}
+
+// Sources of word audio
+const (
+	AudioUpload = "upload"
+	AudioTTS    = "tts"
+)
+
+// WordAudio is the pronunciation of a word
+type WordAudio struct {
+	WordID int64  `json:"word_id"`
+	URL    string `json:"url"`
+	// Source tells uploaded recordings from generated speech
+	Source string `json:"source"`
+	// Voice names the text-to-speech voice generated audio came from
+	Voice       string    `json:"voice,omitempty"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	Hash        string    `json:"-"`
+}
+
+// Image is an uploaded image along with the thumbnail shown in lists; both URLs
+// are the same when the image is small enough to be its own thumbnail
+type Image struct {
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	CreatedAt    time.Time `json:"created_at"`
+	Hash         string    `json:"-"`
+}
+
+// WordImage is the mnemonic image of a word
+type WordImage struct {
+	WordID int64 `json:"word_id"`
+	Image
+}
+
+// StudyActivityImage is the uploaded thumbnail of a study activity
+type StudyActivityImage struct {
+	StudyActivityID int64 `json:"study_activity_id"`
+	Image
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// QuizScore records a scored quiz and the session its reviews were added to
+type QuizScore struct {
+	QuizID         string    `json:"quiz_id"`
+	StudySessionID int64     `json:"study_session_id"`
+	Correct        int       `json:"correct"`
+	Total          int       `json:"total"`
+	ScoredAt       time.Time `json:"scored_at"`
+}
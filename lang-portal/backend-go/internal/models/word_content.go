@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Kinds of word content
+const (
+	// ContentExampleSentences are sentences using the word, with readings and translations
+	ContentExampleSentences = "example_sentences"
+	// ContentMnemonic is a memory aid for the word's meaning and reading
+	ContentMnemonic = "mnemonic"
+	// ContentGrammar explains how the word is used and conjugated
+	ContentGrammar = "grammar"
+)
+
+// WordContentKinds lists the kinds of word content
+var WordContentKinds = []string{ContentExampleSentences, ContentMnemonic, ContentGrammar}
+
+// Sources of word content
+const (
+	ContentGenerated = "generated"
+	ContentTeacher   = "teacher"
+)
+
+// MaxWordContentLength bounds word content, in characters
+const MaxWordContentLength = 5000
+
+// WordContent is generated or teacher-written content about a word, one per kind
+type WordContent struct {
+	WordID  int64  `json:"word_id"`
+	Kind    string `json:"kind"`
+	Content string `json:"content"`
+	// Source tells generated content from content written or edited by a teacher
+	Source string `json:"source"`
+	// Model is the language model generated content came from
+	Model     string    `json:"model,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsWordContentKind reports whether kind is a kind of word content
+func IsWordContentKind(kind string) bool {
+	return contains(WordContentKinds, kind)
+}
+
+// Validate checks the kind and content
+func (c *WordContent) Validate() error {
+	if !IsWordContentKind(c.Kind) {
+		return fmt.Errorf("kind must be one of: %s", strings.Join(WordContentKinds, ", "))
+	}
+	if strings.TrimSpace(c.Content) == "" {
+		return fmt.Errorf("content is required")
+	}
+	if utf8.RuneCountInString(c.Content) > MaxWordContentLength {
+		return fmt.Errorf("content must be at most %d characters", MaxWordContentLength)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package quiz
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Common errors
+var (
+	ErrInvalidAnswerKey = errors.New("invalid answer key")
+	ErrExpiredAnswerKey = errors.New("answer key expired")
+)
+
+// answerKey holds what scoring a generated quiz needs, so quizzes are not stored
+type answerKey struct {
+	QuizID    string      `json:"id"`
+	GroupID   int64       `json:"gid"`
+	Type      string      `json:"type"`
+	ExpiresAt int64       `json:"exp"`
+	Questions []keyAnswer `json:"q"`
+}
+
+// keyAnswer is the answer of one question: the word asked and the index of the right
+// choice, or for matching questions the words on the left and the right index of each
+type keyAnswer struct {
+	WordIDs []int64 `json:"w"`
+	Answers []int   `json:"a"`
+}
+
+// AnswerKeys seals answer keys with AES-GCM: clients hand them back when submitting
+// answers but can neither read the answers from them nor forge them.
+// A sealed key is base64url(nonce + ciphertext).
+type AnswerKeys struct {
+	aead cipher.AEAD
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewAnswerKeys creates a sealer; an empty secret is replaced by a random one,
+// which means answer keys do not survive a server restart
+func NewAnswerKeys(secret string, ttl time.Duration) (*AnswerKeys, error) {
+	key := sha256.Sum256([]byte(secret))
+	if secret == "" {
+		if _, err := rand.Read(key[:]); err != nil {
+			return nil, fmt.Errorf("failed to generate quiz secret: %w", err)
+		}
+	}
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quiz cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quiz cipher: %w", err)
+	}
+	return &AnswerKeys{aead: aead, ttl: ttl, now: time.Now}, nil
+}
+
+// seal sets the expiry of a key and returns it sealed along with that expiry
+func (k *AnswerKeys) seal(key answerKey) (string, time.Time, error) {
+	expiresAt := k.now().Add(k.ttl).UTC().Truncate(time.Second)
+	key.ExpiresAt = expiresAt.Unix()
+	payload, err := json.Marshal(key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode answer key: %w", err)
+	}
+
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate answer key nonce: %w", err)
+	}
+	sealed := k.aead.Seal(nonce, nonce, payload, nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), expiresAt, nil
+}
+
+// open decrypts a sealed key and checks its expiry
+func (k *AnswerKeys) open(sealed string) (*answerKey, error) {
+	data, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil || len(data) < k.aead.NonceSize() {
+		return nil, ErrInvalidAnswerKey
+	}
+	nonce, ciphertext := data[:k.aead.NonceSize()], data[k.aead.NonceSize():]
+	payload, err := k.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidAnswerKey
+	}
+
+	var key answerKey
+	if err := json.Unmarshal(payload, &key); err != nil {
+		return nil, ErrInvalidAnswerKey
+	}
+	if k.now().Unix() >= key.ExpiresAt {
+		return nil, ErrExpiredAnswerKey
+	}
+	return &key, nil
+}
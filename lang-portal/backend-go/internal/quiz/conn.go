@@ -0,0 +1,120 @@
+package quiz
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket tuning
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 4 << 10
+	sendBuffer     = 32
+)
+
+// Conn is a WebSocket connection of a host or player.
+// Writes go through a buffered channel drained by writePump; a client that
+// lets the buffer fill up is disconnected rather than slowing the room down.
+type Conn struct {
+	ws     *websocket.Conn
+	send   chan []byte
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewConn wraps an upgraded connection
+func NewConn(ws *websocket.Conn) *Conn {
+	return &Conn{ws: ws, send: make(chan []byte, sendBuffer), closed: make(chan struct{})}
+}
+
+// Send queues a message, closing the connection when its buffer is full
+func (c *Conn) Send(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Failed to encode quiz message: %v", err)
+		return
+	}
+
+	select {
+	case <-c.closed:
+	case c.send <- data:
+	default:
+		c.Close()
+	}
+}
+
+// Close disconnects the client after the queued messages are written
+func (c *Conn) Close() {
+	c.once.Do(func() { close(c.closed) })
+}
+
+// writePump writes queued messages and pings until the connection is closed
+func (c *Conn) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+	}()
+
+	for {
+		select {
+		case data := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteMessage(websocket.TextMessage, data); err != nil {
+				c.Close()
+				return
+			}
+		case <-ticker.C:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.Close()
+				return
+			}
+		case <-c.closed:
+			// Flush what is already queued, then say goodbye
+			for {
+				select {
+				case data := <-c.send:
+					c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+					if c.ws.WriteMessage(websocket.TextMessage, data) != nil {
+						return
+					}
+				default:
+					c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+					c.ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+					return
+				}
+			}
+		}
+	}
+}
+
+// readPump hands client messages to onMessage until the connection fails
+func (c *Conn) readPump(onMessage func(ClientMessage)) {
+	defer c.Close()
+
+	c.ws.SetReadLimit(maxMessageSize)
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var message ClientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			c.Send(ErrorMessage{Type: MsgError, Message: "invalid message"})
+			continue
+		}
+		onMessage(message)
+	}
+}
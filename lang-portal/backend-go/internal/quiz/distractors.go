@@ -0,0 +1,125 @@
+package quiz
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode"
+
+	"lang-portal/internal/repository"
+)
+
+// Parts of speech, as far as partOfSpeech can tell them apart
+const (
+	posVerb      = "verb"
+	posAdjective = "adjective"
+	posNoun      = "noun"
+)
+
+// partOfSpeech guesses the part of speech of a word, which is not stored:
+// English meanings starting with "to " are verbs, other words ending in い are adjectives
+func partOfSpeech(word repository.RawGroupWordItem) string {
+	switch {
+	case strings.HasPrefix(strings.ToLower(strings.TrimSpace(word.English)), "to "):
+		return posVerb
+	case strings.HasSuffix(word.Kanji, "い"):
+		return posAdjective
+	default:
+		return posNoun
+	}
+}
+
+// sharedKanji counts the distinct kanji (not kana) two words have in common
+func sharedKanji(a, b string) int {
+	inA := map[rune]bool{}
+	for _, r := range a {
+		if unicode.Is(unicode.Han, r) {
+			inA[r] = true
+		}
+	}
+	shared := 0
+	for r := range inA {
+		if strings.ContainsRune(b, r) {
+			shared++
+		}
+	}
+	return shared
+}
+
+// romajiSimilarity is 1 for identical romaji down to 0 for entirely different ones,
+// based on their edit distance
+func romajiSimilarity(a, b string) float64 {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance between two ASCII strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// plausibility scores how easily a candidate is mistaken for the target:
+// the same part of speech counts most, then shared kanji and similar romaji
+func plausibility(target, candidate repository.RawGroupWordItem) float64 {
+	score := 2 * float64(sharedKanji(target.Kanji, candidate.Kanji))
+	score += 2 * romajiSimilarity(target.Romaji, candidate.Romaji)
+	if partOfSpeech(target) == partOfSpeech(candidate) {
+		score += 3
+	}
+	return score
+}
+
+// pickDistractors returns up to count of the most plausible words to offer next to target.
+// A distractor never shows the target's prompt or answer, nor the answer of another
+// distractor, so exactly one choice is right; equally plausible words are picked at random.
+func pickDistractors(target repository.RawGroupWordItem, words []repository.RawGroupWordItem, f fields, count int, rng *rand.Rand) []repository.RawGroupWordItem {
+	candidates := make([]repository.RawGroupWordItem, len(words))
+	for i, j := range rng.Perm(len(words)) {
+		candidates[i] = words[j]
+	}
+	scores := make(map[int64]float64, len(candidates))
+	for _, candidate := range candidates {
+		scores[candidate.ID] = plausibility(target, candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i].ID] > scores[candidates[j].ID]
+	})
+
+	seen := map[string]bool{f.answer(target): true}
+	var distractors []repository.RawGroupWordItem
+	for _, candidate := range candidates {
+		if len(distractors) == count {
+			break
+		}
+		answer := f.answer(candidate)
+		if seen[answer] || f.prompt(candidate) == f.prompt(target) {
+			continue
+		}
+		seen[answer] = true
+		distractors = append(distractors, candidate)
+	}
+	return distractors
+}
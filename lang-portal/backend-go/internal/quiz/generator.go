@@ -0,0 +1,343 @@
+package quiz
+
+import (
+	"context"
+	"fmt"
+	mathrand "math/rand"
+	"strings"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// Question types of generated quizzes
+const (
+	// KanjiToEnglish shows a word and asks for its meaning
+	KanjiToEnglish = "kanji_to_english"
+	// EnglishToKanji shows a meaning and asks for the word
+	EnglishToKanji = "english_to_kanji"
+	// Reading shows a word and asks for its reading in romaji
+	Reading = "reading"
+	// Matching asks to pair words with their meanings
+	Matching = "matching"
+)
+
+// QuestionTypes lists the question types of generated quizzes
+var QuestionTypes = []string{KanjiToEnglish, EnglishToKanji, Reading, Matching}
+
+// Choice count bounds; for matching questions the choice count is the number of pairs
+const (
+	DefaultChoiceCount = 4
+	MinChoiceCount     = 2
+	MaxChoiceCount     = 6
+)
+
+// fields selects what a multiple-choice question type shows and asks for
+type fields struct {
+	prompt func(repository.RawGroupWordItem) string
+	answer func(repository.RawGroupWordItem) string
+}
+
+func kanji(w repository.RawGroupWordItem) string   { return w.Kanji }
+func english(w repository.RawGroupWordItem) string { return w.English }
+func romaji(w repository.RawGroupWordItem) string  { return w.Romaji }
+
+// choiceFields maps the multiple-choice question types to their fields
+var choiceFields = map[string]fields{
+	KanjiToEnglish: {prompt: kanji, answer: english},
+	EnglishToKanji: {prompt: english, answer: kanji},
+	Reading:        {prompt: kanji, answer: romaji},
+	// Matching pairs are told apart by both sides
+	Matching: {prompt: kanji, answer: english},
+}
+
+// QuizSettings selects the group, question type and size of a generated quiz
+type QuizSettings struct {
+	GroupID       int64
+	Type          string
+	QuestionCount int
+	ChoiceCount   int
+}
+
+// Item is a question of a generated quiz. Multiple-choice questions have a prompt and
+// choices; matching questions list words on the left and shuffled meanings on the right.
+type Item struct {
+	Question int      `json:"question"`
+	Prompt   string   `json:"prompt,omitempty"`
+	Choices  []string `json:"choices,omitempty"`
+	Left     []string `json:"left,omitempty"`
+	Right    []string `json:"right,omitempty"`
+}
+
+// Quiz is a generated quiz; its answers are only in the sealed answer key
+type Quiz struct {
+	ID        string    `json:"quiz_id"`
+	GroupID   int64     `json:"group_id"`
+	Type      string    `json:"type"`
+	Questions []Item    `json:"questions"`
+	AnswerKey string    `json:"answer_key"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Answer is a submitted answer: the index of the chosen choice, or for
+// matching questions the index on the right paired with each word on the left
+type Answer struct {
+	Question int   `json:"question"`
+	Choice   *int  `json:"choice"`
+	Pairs    []int `json:"pairs"`
+}
+
+// QuestionResult is the outcome of one question along with the reviews it recorded
+type QuestionResult struct {
+	Question int  `json:"question"`
+	Correct  bool `json:"correct"`
+	// Answer is the right choice of a multiple-choice question
+	Answer *int `json:"answer,omitempty"`
+	// Pairs are the right pairs of a matching question
+	Pairs   []int                   `json:"pairs,omitempty"`
+	Reviews []models.WordReviewItem `json:"reviews"`
+}
+
+// QuizResult is a scored quiz; every word asked counts as one review
+type QuizResult struct {
+	models.QuizScore
+	// Score is the percentage of correct reviews
+	Score     int              `json:"score"`
+	Questions []QuestionResult `json:"questions"`
+}
+
+// Generator builds quizzes from the words of a group and scores them into study sessions
+type Generator struct {
+	groupRepo repository.GroupRepository
+	quizRepo  repository.QuizRepository
+	keys      *AnswerKeys
+}
+
+// NewGenerator creates a generator sealing answer keys with keys
+func NewGenerator(groupRepo repository.GroupRepository, quizRepo repository.QuizRepository, keys *AnswerKeys) *Generator {
+	return &Generator{groupRepo: groupRepo, quizRepo: quizRepo, keys: keys}
+}
+
+// Generate picks random words of the group and builds a question for each, or a matching
+// question for each set of ChoiceCount words; a group too small for QuestionCount gets fewer
+func (g *Generator) Generate(ctx context.Context, settings QuizSettings) (*Quiz, error) {
+	if settings.QuestionCount == 0 {
+		settings.QuestionCount = DefaultQuestionCount
+	}
+	if settings.ChoiceCount == 0 {
+		settings.ChoiceCount = DefaultChoiceCount
+	}
+	f, ok := choiceFields[settings.Type]
+	if !ok {
+		return nil, fmt.Errorf("type must be one of: %s", strings.Join(QuestionTypes, ", "))
+	}
+	if settings.QuestionCount < 1 || settings.QuestionCount > MaxQuestionCount {
+		return nil, fmt.Errorf("question_count must be between 1 and %d", MaxQuestionCount)
+	}
+	if settings.ChoiceCount < MinChoiceCount || settings.ChoiceCount > MaxChoiceCount {
+		return nil, fmt.Errorf("choice_count must be between %d and %d", MinChoiceCount, MaxChoiceCount)
+	}
+
+	words, err := g.groupRepo.GetGroupWordsRaw(ctx, settings.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	rng := mathrand.New(mathrand.NewSource(time.Now().UnixNano()))
+	var items []Item
+	var answers []keyAnswer
+	if settings.Type == Matching {
+		items, answers = buildMatching(words, f, settings.QuestionCount, settings.ChoiceCount, rng)
+	} else {
+		items, answers = buildChoices(words, f, settings.QuestionCount, settings.ChoiceCount, rng)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("group needs at least 2 words with different %s for %s questions",
+			describeFields(settings.Type), settings.Type)
+	}
+
+	id, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	sealed, expiresAt, err := g.keys.seal(answerKey{
+		QuizID:    id,
+		GroupID:   settings.GroupID,
+		Type:      settings.Type,
+		Questions: answers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Quiz{
+		ID:        id,
+		GroupID:   settings.GroupID,
+		Type:      settings.Type,
+		Questions: items,
+		AnswerKey: sealed,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// describeFields names what the words of a group must differ in for a question type
+func describeFields(questionType string) string {
+	switch questionType {
+	case Reading:
+		return "readings"
+	case EnglishToKanji, Matching:
+		return "kanji and meanings"
+	default:
+		return "meanings"
+	}
+}
+
+// buildChoices builds a multiple-choice question for up to count words, skipping
+// words no distractor can be found for
+func buildChoices(words []repository.RawGroupWordItem, f fields, count, choiceCount int, rng *mathrand.Rand) ([]Item, []keyAnswer) {
+	var items []Item
+	var answers []keyAnswer
+	for _, i := range rng.Perm(len(words)) {
+		if len(items) == count {
+			break
+		}
+		target := words[i]
+		distractors := pickDistractors(target, words, f, choiceCount-1, rng)
+		if len(distractors) == 0 {
+			continue
+		}
+
+		options := append([]repository.RawGroupWordItem{target}, distractors...)
+		rng.Shuffle(len(options), func(a, b int) { options[a], options[b] = options[b], options[a] })
+		item := Item{Question: len(items) + 1, Prompt: f.prompt(target)}
+		answer := keyAnswer{WordIDs: []int64{target.ID}}
+		for k, option := range options {
+			item.Choices = append(item.Choices, f.answer(option))
+			if option.ID == target.ID {
+				answer.Answers = []int{k}
+			}
+		}
+		items = append(items, item)
+		answers = append(answers, answer)
+	}
+	return items, answers
+}
+
+// buildMatching splits the words into up to count sets of pairCount words with
+// different kanji and meanings; a set needs at least 2 words
+func buildMatching(words []repository.RawGroupWordItem, f fields, count, pairCount int, rng *mathrand.Rand) ([]Item, []keyAnswer) {
+	remaining := make([]repository.RawGroupWordItem, len(words))
+	for i, j := range rng.Perm(len(words)) {
+		remaining[i] = words[j]
+	}
+
+	var items []Item
+	var answers []keyAnswer
+	for len(items) < count {
+		var set, rest []repository.RawGroupWordItem
+		prompts, meanings := map[string]bool{}, map[string]bool{}
+		for _, word := range remaining {
+			if len(set) == pairCount || prompts[f.prompt(word)] || meanings[f.answer(word)] {
+				rest = append(rest, word)
+				continue
+			}
+			prompts[f.prompt(word)] = true
+			meanings[f.answer(word)] = true
+			set = append(set, word)
+		}
+		if len(set) < 2 {
+			break
+		}
+		remaining = rest
+
+		right := rng.Perm(len(set))
+		item := Item{Question: len(items) + 1, Right: make([]string, len(set))}
+		answer := keyAnswer{Answers: make([]int, len(set))}
+		for k, word := range set {
+			item.Left = append(item.Left, f.prompt(word))
+			item.Right[right[k]] = f.answer(word)
+			answer.WordIDs = append(answer.WordIDs, word.ID)
+			answer.Answers[k] = right[k]
+		}
+		items = append(items, item)
+		answers = append(answers, answer)
+	}
+	return items, answers
+}
+
+// Score checks the answers against a sealed answer key and records a review of every word
+// asked in the study session; unanswered questions count as wrong
+func (g *Generator) Score(ctx context.Context, sealedKey string, sessionID int64, submitted []Answer) (*QuizResult, error) {
+	key, err := g.keys.open(sealedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	given := map[int]Answer{}
+	for _, answer := range submitted {
+		if answer.Question < 1 || answer.Question > len(key.Questions) {
+			return nil, fmt.Errorf("answers: question %d does not exist", answer.Question)
+		}
+		if _, ok := given[answer.Question]; ok {
+			return nil, fmt.Errorf("answers: question %d is answered more than once", answer.Question)
+		}
+		expected := key.Questions[answer.Question-1]
+		switch {
+		case key.Type == Matching && len(answer.Pairs) != len(expected.WordIDs):
+			return nil, fmt.Errorf("answers: question %d needs %d pairs", answer.Question, len(expected.WordIDs))
+		case key.Type != Matching && answer.Choice == nil:
+			return nil, fmt.Errorf("answers: question %d needs a choice", answer.Question)
+		}
+		given[answer.Question] = answer
+	}
+
+	now := time.Now().UTC()
+	result := &QuizResult{QuizScore: models.QuizScore{QuizID: key.QuizID, StudySessionID: sessionID, ScoredAt: now}}
+	var reviews []models.WordReviewItem
+	for i, expected := range key.Questions {
+		answer, answered := given[i+1]
+		question := QuestionResult{Question: i + 1, Correct: answered}
+		for k, wordID := range expected.WordIDs {
+			var correct bool
+			if key.Type == Matching {
+				correct = answered && answer.Pairs[k] == expected.Answers[k]
+			} else {
+				correct = answered && *answer.Choice == expected.Answers[k]
+			}
+			question.Correct = question.Correct && correct
+			reviews = append(reviews, models.WordReviewItem{
+				WordID:         wordID,
+				StudySessionID: sessionID,
+				Correct:        correct,
+				CreatedAt:      now,
+			})
+			result.Total++
+			if correct {
+				result.Correct++
+			}
+		}
+		if key.Type == Matching {
+			question.Pairs = expected.Answers
+		} else {
+			question.Answer = &expected.Answers[0]
+		}
+		result.Questions = append(result.Questions, question)
+	}
+
+	if err := g.quizRepo.SaveScore(ctx, &result.QuizScore, key.GroupID, reviews); err != nil {
+		return nil, err
+	}
+
+	// Hand out the saved reviews, now that they have IDs
+	next := 0
+	for i := range result.Questions {
+		count := len(key.Questions[i].WordIDs)
+		result.Questions[i].Reviews = reviews[next : next+count]
+		next += count
+	}
+	if result.Total > 0 {
+		result.Score = 100 * result.Correct / result.Total
+	}
+	return result, nil
+}
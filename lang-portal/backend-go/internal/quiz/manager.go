@@ -0,0 +1,184 @@
+package quiz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+	"sync"
+	"time"
+
+	"lang-portal/internal/repository"
+)
+
+// Room setting bounds
+const (
+	DefaultQuestionCount = 10
+	MaxQuestionCount     = 50
+	DefaultQuestionTime  = 20 * time.Second
+	MinQuestionTime      = 5 * time.Second
+	MaxQuestionTime      = 120 * time.Second
+)
+
+// codeAlphabet leaves out characters that are easy to confuse when read aloud or from a projector
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// codeLength is the number of characters of a room code
+const codeLength = 6
+
+// Manager creates rooms and finds them by code
+type Manager struct {
+	groupRepo         repository.GroupRepository
+	studyActivityRepo repository.StudyActivityRepository
+	studySessionRepo  repository.StudySessionRepository
+	maxRooms          int
+
+	mu       sync.Mutex
+	rooms    map[string]*Room
+	shutdown bool
+}
+
+// NewManager creates a manager hosting up to maxRooms rooms at a time
+func NewManager(
+	groupRepo repository.GroupRepository,
+	studyActivityRepo repository.StudyActivityRepository,
+	studySessionRepo repository.StudySessionRepository,
+	maxRooms int,
+) *Manager {
+	return &Manager{
+		groupRepo:         groupRepo,
+		studyActivityRepo: studyActivityRepo,
+		studySessionRepo:  studySessionRepo,
+		maxRooms:          maxRooms,
+		rooms:             map[string]*Room{},
+	}
+}
+
+// CreateRoom validates the settings, picks the questions and opens a room in the lobby state
+func (m *Manager) CreateRoom(ctx context.Context, settings Settings) (*Room, error) {
+	if settings.QuestionCount == 0 {
+		settings.QuestionCount = DefaultQuestionCount
+	}
+	if settings.QuestionTime == 0 {
+		settings.QuestionTime = DefaultQuestionTime
+	}
+	if settings.QuestionCount < 1 || settings.QuestionCount > MaxQuestionCount {
+		return nil, fmt.Errorf("question_count must be between 1 and %d", MaxQuestionCount)
+	}
+	if settings.QuestionTime < MinQuestionTime || settings.QuestionTime > MaxQuestionTime {
+		return nil, fmt.Errorf("question_seconds must be between %d and %d",
+			int(MinQuestionTime/time.Second), int(MaxQuestionTime/time.Second))
+	}
+
+	activity, err := m.studyActivityRepo.GetByID(ctx, settings.StudyActivityID)
+	if err != nil {
+		return nil, err
+	}
+	if activity.RetiredAt != nil {
+		return nil, fmt.Errorf("study activity has been retired")
+	}
+
+	group, err := m.groupRepo.GetByID(ctx, settings.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	words, err := m.groupRepo.GetGroupWordsRaw(ctx, settings.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if distinctMeanings(words) < 2 {
+		return nil, fmt.Errorf("group needs at least 2 words with different meanings for a quiz")
+	}
+
+	hostToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	questions := buildQuestions(words, settings.QuestionCount, mathrand.New(mathrand.NewSource(time.Now().UnixNano())))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.shutdown {
+		return nil, fmt.Errorf("quiz rooms are shutting down")
+	}
+	if len(m.rooms) >= m.maxRooms {
+		return nil, fmt.Errorf("too many quiz rooms")
+	}
+	code, err := m.newCode()
+	if err != nil {
+		return nil, err
+	}
+
+	room := newRoom(code, hostToken, group.Name, settings, questions, m.studySessionRepo)
+	m.rooms[code] = room
+	go room.run()
+	go func() {
+		<-room.Done()
+		m.mu.Lock()
+		delete(m.rooms, code)
+		m.mu.Unlock()
+	}()
+
+	return room, nil
+}
+
+// Room finds an open room by code
+func (m *Manager) Room(code string) (*Room, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	room, ok := m.rooms[code]
+	return room, ok
+}
+
+// Shutdown closes every room and waits for them to finish
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.shutdown = true
+	rooms := make([]*Room, 0, len(m.rooms))
+	for _, room := range m.rooms {
+		rooms = append(rooms, room)
+	}
+	m.mu.Unlock()
+
+	for _, room := range rooms {
+		room.Close()
+	}
+	for _, room := range rooms {
+		select {
+		case <-room.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// newCode picks an unused room code; the caller holds m.mu
+func (m *Manager) newCode() (string, error) {
+	for attempt := 0; attempt < 10; attempt++ {
+		code := make([]byte, codeLength)
+		for i := range code {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(codeAlphabet))))
+			if err != nil {
+				return "", fmt.Errorf("failed to generate room code: %w", err)
+			}
+			code[i] = codeAlphabet[n.Int64()]
+		}
+		if _, taken := m.rooms[string(code)]; !taken {
+			return string(code), nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate room code")
+}
+
+// newToken returns a random token for hosts and players
+func newToken() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
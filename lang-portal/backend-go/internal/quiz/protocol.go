@@ -0,0 +1,128 @@
+package quiz
+
+import "time"
+
+// Messages sent by clients
+const (
+	// MsgStart is sent by the host to ask the first question
+	MsgStart = "start"
+	// MsgNext is sent by the host to ask the next question, or finish after the last one
+	MsgNext = "next"
+	// MsgEnd is sent by the host to finish the quiz early
+	MsgEnd = "end"
+	// MsgAnswer is sent by players with their choice for the current question
+	MsgAnswer = "answer"
+)
+
+// Messages sent by the server
+const (
+	MsgWelcome  = "welcome"
+	MsgLobby    = "lobby"
+	MsgQuestion = "question"
+	MsgProgress = "progress"
+	MsgResult   = "answer_result"
+	MsgReveal   = "reveal"
+	MsgFinished = "finished"
+	MsgError    = "error"
+)
+
+// Room states
+const (
+	StateLobby    = "lobby"
+	StateQuestion = "question"
+	StateReveal   = "reveal"
+	StateFinished = "finished"
+)
+
+// ClientMessage is a message received from a host or player
+type ClientMessage struct {
+	Type       string `json:"type"`
+	QuestionID int    `json:"question_id,omitempty"`
+	Choice     *int   `json:"choice,omitempty"`
+}
+
+// Welcome is sent once a connection has joined a room
+type Welcome struct {
+	Type        string `json:"type"`
+	Role        string `json:"role"`
+	Code        string `json:"code"`
+	GroupName   string `json:"group_name"`
+	State       string `json:"state"`
+	PlayerID    int64  `json:"player_id,omitempty"`
+	PlayerToken string `json:"player_token,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Score       int    `json:"score"`
+}
+
+// PlayerInfo describes a player in the lobby
+type PlayerInfo struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Connected bool   `json:"connected"`
+}
+
+// Lobby lists the players of a room
+type Lobby struct {
+	Type    string       `json:"type"`
+	Players []PlayerInfo `json:"players"`
+}
+
+// QuestionMessage asks a question until the deadline
+type QuestionMessage struct {
+	Type     string    `json:"type"`
+	Index    int       `json:"index"`
+	Total    int       `json:"total"`
+	Seconds  int       `json:"seconds"`
+	Deadline time.Time `json:"deadline"`
+	Question
+}
+
+// Progress tells the host how many players answered
+type Progress struct {
+	Type       string `json:"type"`
+	QuestionID int    `json:"question_id"`
+	Answered   int    `json:"answered"`
+	Players    int    `json:"players"`
+}
+
+// Result tells a player how their answer was scored
+type Result struct {
+	Type       string `json:"type"`
+	QuestionID int    `json:"question_id"`
+	Correct    bool   `json:"correct"`
+	Points     int    `json:"points"`
+	Score      int    `json:"score"`
+}
+
+// Standing is a leaderboard entry
+type Standing struct {
+	Rank     int    `json:"rank"`
+	PlayerID int64  `json:"player_id"`
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	Correct  int    `json:"correct"`
+	Answered int    `json:"answered"`
+}
+
+// Reveal closes a question with its answer and the leaderboard
+type Reveal struct {
+	Type          string     `json:"type"`
+	QuestionID    int        `json:"question_id"`
+	Answer        int        `json:"answer"`
+	CorrectChoice string     `json:"correct_choice"`
+	Answered      int        `json:"answered"`
+	Last          bool       `json:"last"`
+	Leaderboard   []Standing `json:"leaderboard"`
+}
+
+// Finished ends the quiz with the final leaderboard
+type Finished struct {
+	Type        string     `json:"type"`
+	Leaderboard []Standing `json:"leaderboard"`
+}
+
+// ErrorMessage reports a rejected message
+type ErrorMessage struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
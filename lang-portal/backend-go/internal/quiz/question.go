@@ -0,0 +1,72 @@
+package quiz
+
+import (
+	"math/rand"
+
+	"lang-portal/internal/repository"
+)
+
+// maxChoices is the number of choices of a question when the group has enough words
+const maxChoices = 4
+
+// Question asks for the English meaning of a group word
+type Question struct {
+	ID      int      `json:"question_id"`
+	WordID  int64    `json:"-"`
+	Kanji   string   `json:"kanji"`
+	Romaji  string   `json:"romaji"`
+	Choices []string `json:"choices"`
+	answer  int
+}
+
+// buildQuestions picks up to count distinct words and gives each question
+// distractors taken from the meanings of other words in the group
+func buildQuestions(words []repository.RawGroupWordItem, count int, rng *rand.Rand) []Question {
+	order := rng.Perm(len(words))
+	if count > len(words) {
+		count = len(words)
+	}
+
+	questions := make([]Question, 0, count)
+	for i := 0; i < count; i++ {
+		word := words[order[i]]
+
+		// Distinct meanings, so exactly one choice is right
+		choices := []string{word.English}
+		seen := map[string]bool{word.English: true}
+		for _, j := range rng.Perm(len(words)) {
+			if len(choices) == maxChoices {
+				break
+			}
+			if english := words[j].English; !seen[english] {
+				seen[english] = true
+				choices = append(choices, english)
+			}
+		}
+		rng.Shuffle(len(choices), func(a, b int) { choices[a], choices[b] = choices[b], choices[a] })
+
+		question := Question{
+			ID:      i + 1,
+			WordID:  word.ID,
+			Kanji:   word.Kanji,
+			Romaji:  word.Romaji,
+			Choices: choices,
+		}
+		for k, choice := range choices {
+			if choice == word.English {
+				question.answer = k
+			}
+		}
+		questions = append(questions, question)
+	}
+	return questions
+}
+
+// distinctMeanings counts the different English meanings of the words
+func distinctMeanings(words []repository.RawGroupWordItem) int {
+	seen := map[string]bool{}
+	for _, word := range words {
+		seen[word.English] = true
+	}
+	return len(seen)
+}
@@ -0,0 +1,552 @@
+package quiz
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	"sort"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// Room lifetime limits
+const (
+	// idleTimeout closes rooms nobody interacted with for a while
+	idleTimeout = 2 * time.Hour
+	// finishedGrace keeps finished rooms around for late leaderboard views
+	finishedGrace = 5 * time.Minute
+	// maxPlayers bounds the size of a room
+	maxPlayers = 200
+)
+
+// Settings configure a room
+type Settings struct {
+	GroupID         int64
+	StudyActivityID int64
+	QuestionCount   int
+	QuestionTime    time.Duration
+}
+
+// player is a participant; players keep their score across reconnects
+type player struct {
+	id        int64
+	token     string
+	name      string
+	sessionID int64
+	score     int
+	correct   int
+	answered  int
+	conn      *Conn
+}
+
+// answer is a player's answer to the current question
+type answer struct {
+	choice int
+	points int
+}
+
+// Room is a live quiz. All state is owned by the run goroutine; other
+// goroutines change it by submitting functions through do.
+type Room struct {
+	Code      string
+	HostToken string
+	GroupName string
+	CreatedAt time.Time
+
+	settings  Settings
+	questions []Question
+	sessions  repository.StudySessionRepository
+	commands  chan func()
+	done      chan struct{}
+
+	state        string
+	host         *Conn
+	players      map[string]*player
+	nextPlayerID int64
+	current      int
+	deadline     time.Time
+	answers      map[int64]answer
+	lastActivity time.Time
+	finishedAt   time.Time
+	closing      bool
+}
+
+// newRoom creates a room in the lobby state
+func newRoom(code, hostToken, groupName string, settings Settings, questions []Question, sessions repository.StudySessionRepository) *Room {
+	now := time.Now()
+	return &Room{
+		Code:         code,
+		HostToken:    hostToken,
+		GroupName:    groupName,
+		CreatedAt:    now.UTC(),
+		settings:     settings,
+		questions:    questions,
+		sessions:     sessions,
+		commands:     make(chan func()),
+		done:         make(chan struct{}),
+		state:        StateLobby,
+		players:      map[string]*player{},
+		current:      -1,
+		lastActivity: now,
+	}
+}
+
+// Info is a snapshot of a room for the REST API
+type Info struct {
+	Code            string    `json:"code"`
+	GroupID         int64     `json:"group_id"`
+	GroupName       string    `json:"group_name"`
+	StudyActivityID int64     `json:"study_activity_id"`
+	State           string    `json:"state"`
+	Players         int       `json:"players"`
+	QuestionCount   int       `json:"question_count"`
+	QuestionSeconds int       `json:"question_seconds"`
+	CurrentQuestion int       `json:"current_question"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Info returns a snapshot of the room; ok is false once the room is closed
+func (r *Room) Info() (info Info, ok bool) {
+	ok = r.do(func() {
+		info = Info{
+			Code:            r.Code,
+			GroupID:         r.settings.GroupID,
+			GroupName:       r.GroupName,
+			StudyActivityID: r.settings.StudyActivityID,
+			State:           r.state,
+			Players:         len(r.players),
+			QuestionCount:   len(r.questions),
+			QuestionSeconds: int(r.settings.QuestionTime / time.Second),
+			CurrentQuestion: r.current + 1,
+			CreatedAt:       r.CreatedAt,
+		}
+	})
+	return info, ok
+}
+
+// IsHost reports whether token is the host token of the room
+func (r *Room) IsHost(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(r.HostToken)) == 1
+}
+
+// Done is closed when the room has shut down
+func (r *Room) Done() <-chan struct{} {
+	return r.done
+}
+
+// Close shuts the room down, disconnecting everyone
+func (r *Room) Close() {
+	r.do(func() { r.closing = true })
+}
+
+// do runs fn on the room goroutine and waits for it, returning false when the room is closed
+func (r *Room) do(fn func()) bool {
+	ran := make(chan struct{})
+	select {
+	case r.commands <- func() { fn(); close(ran) }:
+		<-ran
+		return true
+	case <-r.done:
+		return false
+	}
+}
+
+// run processes commands until the room closes
+func (r *Room) run() {
+	defer close(r.done)
+
+	janitor := time.NewTicker(30 * time.Second)
+	defer janitor.Stop()
+
+	for !r.closing {
+		select {
+		case fn := <-r.commands:
+			fn()
+		case now := <-janitor.C:
+			idle := now.Sub(r.lastActivity) > idleTimeout
+			expired := r.state == StateFinished && now.Sub(r.finishedAt) > finishedGrace
+			if idle || expired {
+				r.closing = true
+			}
+		}
+	}
+
+	if r.state != StateFinished {
+		r.finish()
+	}
+	r.eachConn(func(c *Conn) { c.Close() })
+}
+
+// ServeHost runs the host side of a connection until it closes
+func (r *Room) ServeHost(conn *Conn) {
+	go conn.writePump()
+	joined := r.do(func() {
+		if r.host != nil {
+			r.host.Close()
+		}
+		r.host = conn
+		r.lastActivity = time.Now()
+		conn.Send(Welcome{Type: MsgWelcome, Role: "host", Code: r.Code, GroupName: r.GroupName, State: r.state})
+		conn.Send(r.lobby())
+		r.resendCurrent(conn, nil)
+	})
+	if !joined {
+		conn.Close()
+		return
+	}
+
+	conn.readPump(func(message ClientMessage) {
+		r.do(func() { r.handleHost(conn, message) })
+	})
+	r.do(func() {
+		if r.host == conn {
+			r.host = nil
+		}
+	})
+}
+
+// ServePlayer runs a player connection until it closes; a known token rejoins as the same player
+func (r *Room) ServePlayer(conn *Conn, name, token string) {
+	go conn.writePump()
+
+	var p *player
+	r.do(func() {
+		p = r.join(conn, name, token)
+	})
+	if p == nil {
+		conn.Close()
+		return
+	}
+
+	conn.readPump(func(message ClientMessage) {
+		r.do(func() { r.handlePlayer(p, message) })
+	})
+	r.do(func() {
+		if p.conn == conn {
+			p.conn = nil
+			r.broadcastLobby()
+			// Do not keep everyone waiting for a player who left
+			if r.state == StateQuestion && len(r.answers) > 0 && len(r.answers) >= r.connectedPlayers() {
+				r.reveal(r.current)
+			}
+		}
+	})
+}
+
+// join adds or reconnects a player, returning nil when the join is refused
+func (r *Room) join(conn *Conn, name, token string) *player {
+	r.lastActivity = time.Now()
+
+	p, known := r.players[token]
+	switch {
+	case known:
+		if p.conn != nil {
+			p.conn.Close()
+		}
+	case token != "":
+		conn.Send(ErrorMessage{Type: MsgError, Message: "unknown player token"})
+		return nil
+	case r.state == StateFinished:
+		conn.Send(ErrorMessage{Type: MsgError, Message: "quiz has finished"})
+		return nil
+	case len(r.players) >= maxPlayers:
+		conn.Send(ErrorMessage{Type: MsgError, Message: "room is full"})
+		return nil
+	default:
+		// Each player studies in their own session, so their reviews count for them
+		session := &models.StudySession{
+			GroupID:         r.settings.GroupID,
+			StudyActivityID: r.settings.StudyActivityID,
+			CreatedAt:       time.Now().UTC(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := r.sessions.Create(ctx, session); err != nil {
+			log.Printf("Failed to create quiz session in room %s: %v", r.Code, err)
+			conn.Send(ErrorMessage{Type: MsgError, Message: "failed to join the quiz"})
+			return nil
+		}
+
+		playerToken, err := newToken()
+		if err != nil {
+			conn.Send(ErrorMessage{Type: MsgError, Message: "failed to join the quiz"})
+			return nil
+		}
+		r.nextPlayerID++
+		p = &player{id: r.nextPlayerID, token: playerToken, name: name, sessionID: session.ID}
+		r.players[p.token] = p
+	}
+
+	p.conn = conn
+	conn.Send(Welcome{
+		Type:        MsgWelcome,
+		Role:        "player",
+		Code:        r.Code,
+		GroupName:   r.GroupName,
+		State:       r.state,
+		PlayerID:    p.id,
+		PlayerToken: p.token,
+		Name:        p.name,
+		Score:       p.score,
+	})
+	r.resendCurrent(conn, p)
+	r.broadcastLobby()
+	return p
+}
+
+// resendCurrent brings a (re)connected client up to date with the current question or results
+func (r *Room) resendCurrent(conn *Conn, p *player) {
+	switch r.state {
+	case StateQuestion:
+		if p == nil || !r.hasAnswered(p) {
+			conn.Send(r.questionMessage())
+		}
+	case StateReveal:
+		conn.Send(r.revealMessage())
+	case StateFinished:
+		conn.Send(Finished{Type: MsgFinished, Leaderboard: r.leaderboard()})
+	}
+}
+
+// handleHost applies a host command
+func (r *Room) handleHost(conn *Conn, message ClientMessage) {
+	r.lastActivity = time.Now()
+
+	switch message.Type {
+	case MsgStart:
+		if r.state != StateLobby {
+			conn.Send(ErrorMessage{Type: MsgError, Message: "quiz has already started"})
+			return
+		}
+		r.ask(0)
+	case MsgNext:
+		switch {
+		case r.state == StateQuestion:
+			r.reveal(r.current)
+		case r.state == StateReveal && r.current+1 < len(r.questions):
+			r.ask(r.current + 1)
+		case r.state == StateReveal:
+			r.finish()
+		default:
+			conn.Send(ErrorMessage{Type: MsgError, Message: "no question to move on from"})
+		}
+	case MsgEnd:
+		if r.state != StateFinished {
+			r.finish()
+		}
+	default:
+		conn.Send(ErrorMessage{Type: MsgError, Message: "unknown message type " + message.Type})
+	}
+}
+
+// handlePlayer applies a player answer
+func (r *Room) handlePlayer(p *player, message ClientMessage) {
+	r.lastActivity = time.Now()
+
+	if message.Type != MsgAnswer {
+		p.conn.Send(ErrorMessage{Type: MsgError, Message: "unknown message type " + message.Type})
+		return
+	}
+	if r.state != StateQuestion || message.QuestionID != r.questions[r.current].ID {
+		p.conn.Send(ErrorMessage{Type: MsgError, Message: "question is closed"})
+		return
+	}
+	if r.hasAnswered(p) {
+		p.conn.Send(ErrorMessage{Type: MsgError, Message: "already answered"})
+		return
+	}
+	question := r.questions[r.current]
+	if message.Choice == nil || *message.Choice < 0 || *message.Choice >= len(question.Choices) {
+		p.conn.Send(ErrorMessage{Type: MsgError, Message: "invalid choice"})
+		return
+	}
+
+	correct := *message.Choice == question.answer
+	points := 0
+	if correct {
+		points = r.points(time.Now())
+		p.correct++
+	}
+	p.score += points
+	p.answered++
+	r.answers[p.id] = answer{choice: *message.Choice, points: points}
+
+	r.recordReview(p, question.WordID, correct)
+	p.conn.Send(Result{Type: MsgResult, QuestionID: question.ID, Correct: correct, Points: points, Score: p.score})
+
+	answered, players := len(r.answers), r.connectedPlayers()
+	if r.host != nil {
+		r.host.Send(Progress{Type: MsgProgress, QuestionID: question.ID, Answered: answered, Players: players})
+	}
+	if answered >= players {
+		r.reveal(r.current)
+	}
+}
+
+// points scores a correct answer: 500, plus up to 500 more for answering fast
+func (r *Room) points(at time.Time) int {
+	remaining := r.deadline.Sub(at)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return 500 + int(500*remaining/r.settings.QuestionTime)
+}
+
+// recordReview stores an answer as a word review in the player's session
+func (r *Room) recordReview(p *player, wordID int64, correct bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	review := &models.WordReviewItem{
+		WordID:         wordID,
+		StudySessionID: p.sessionID,
+		Correct:        correct,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := r.sessions.CreateWordReview(ctx, review); err != nil {
+		log.Printf("Failed to record quiz answer in room %s: %v", r.Code, err)
+	}
+}
+
+// ask opens a question and schedules its deadline
+func (r *Room) ask(index int) {
+	r.state = StateQuestion
+	r.current = index
+	r.answers = map[int64]answer{}
+	r.deadline = time.Now().Add(r.settings.QuestionTime)
+
+	time.AfterFunc(r.settings.QuestionTime, func() {
+		r.do(func() { r.reveal(index) })
+	})
+
+	r.broadcast(r.questionMessage())
+}
+
+// reveal closes a question, unless it was already closed
+func (r *Room) reveal(index int) {
+	if r.state != StateQuestion || r.current != index {
+		return
+	}
+	r.state = StateReveal
+	r.broadcast(r.revealMessage())
+}
+
+// finish ends the quiz and completes every player's session
+func (r *Room) finish() {
+	r.state = StateFinished
+	r.finishedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, p := range r.players {
+		if _, err := r.sessions.Complete(ctx, p.sessionID); err != nil && err.Error() != "study session already completed" {
+			log.Printf("Failed to complete quiz session %d: %v", p.sessionID, err)
+		}
+	}
+
+	r.broadcast(Finished{Type: MsgFinished, Leaderboard: r.leaderboard()})
+}
+
+// questionMessage is the current question as sent to clients
+func (r *Room) questionMessage() QuestionMessage {
+	return QuestionMessage{
+		Type:     MsgQuestion,
+		Index:    r.current + 1,
+		Total:    len(r.questions),
+		Seconds:  int(r.settings.QuestionTime / time.Second),
+		Deadline: r.deadline.UTC(),
+		Question: r.questions[r.current],
+	}
+}
+
+// revealMessage is the answer of the current question with the leaderboard
+func (r *Room) revealMessage() Reveal {
+	question := r.questions[r.current]
+	return Reveal{
+		Type:          MsgReveal,
+		QuestionID:    question.ID,
+		Answer:        question.answer,
+		CorrectChoice: question.Choices[question.answer],
+		Answered:      len(r.answers),
+		Last:          r.current+1 == len(r.questions),
+		Leaderboard:   r.leaderboard(),
+	}
+}
+
+// leaderboard ranks players by score; equal scores share a rank
+func (r *Room) leaderboard() []Standing {
+	standings := make([]Standing, 0, len(r.players))
+	for _, p := range r.players {
+		standings = append(standings, Standing{
+			PlayerID: p.id,
+			Name:     p.name,
+			Score:    p.score,
+			Correct:  p.correct,
+			Answered: p.answered,
+		})
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Score != standings[j].Score {
+			return standings[i].Score > standings[j].Score
+		}
+		return standings[i].PlayerID < standings[j].PlayerID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Score == standings[i-1].Score {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+	return standings
+}
+
+// lobby lists the players by join order
+func (r *Room) lobby() Lobby {
+	players := make([]PlayerInfo, 0, len(r.players))
+	for _, p := range r.players {
+		players = append(players, PlayerInfo{ID: p.id, Name: p.name, Connected: p.conn != nil})
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].ID < players[j].ID })
+	return Lobby{Type: MsgLobby, Players: players}
+}
+
+// broadcastLobby sends the player list to everyone
+func (r *Room) broadcastLobby() {
+	r.broadcast(r.lobby())
+}
+
+// broadcast sends a message to the host and every connected player
+func (r *Room) broadcast(message interface{}) {
+	r.eachConn(func(c *Conn) { c.Send(message) })
+}
+
+// eachConn calls fn for the host and every connected player
+func (r *Room) eachConn(fn func(*Conn)) {
+	if r.host != nil {
+		fn(r.host)
+	}
+	for _, p := range r.players {
+		if p.conn != nil {
+			fn(p.conn)
+		}
+	}
+}
+
+// hasAnswered reports whether a player answered the current question
+func (r *Room) hasAnswered(p *player) bool {
+	_, ok := r.answers[p.id]
+	return ok
+}
+
+// connectedPlayers counts the players currently connected
+func (r *Room) connectedPlayers() int {
+	n := 0
+	for _, p := range r.players {
+		if p.conn != nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+// ReviewRecord is a review replayed by the achievement rules
+type ReviewRecord struct {
+	WordID         int64
+	StudySessionID int64
+	Correct        bool
+	CreatedAt      time.Time
+}
+
+// SessionRecord is a study session replayed by the achievement rules
+type SessionRecord struct {
+	ID          int64
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// AchievementHistory is the study history achievements are evaluated over, oldest first
+type AchievementHistory struct {
+	Reviews  []ReviewRecord
+	Sessions []SessionRecord
+	// GroupWords holds the words of the group with each requested slug
+	GroupWords map[string]map[int64]bool
+}
+
+// AchievementRepository defines the interface for the history achievements are earned from and their awards
+type AchievementRepository interface {
+	// GetHistory retrieves every review and study session, along with the words of the groups
+	// with the given slugs
+	GetHistory(ctx context.Context, groupSlugs []string) (*AchievementHistory, error)
+
+	// ListAwards retrieves the achievements earned so far
+	ListAwards(ctx context.Context) ([]models.AchievementAward, error)
+
+	// SaveAwards stores awards not stored yet and returns those
+	SaveAwards(ctx context.Context, awards []models.AchievementAward) ([]models.AchievementAward, error)
+}
+
+// SQLAchievementRepository implements AchievementRepository using SQLite
+type SQLAchievementRepository struct {
+	db *sql.DB
+}
+
+// NewAchievementRepository creates a new instance of SQLAchievementRepository
+func NewAchievementRepository(db *sql.DB) *SQLAchievementRepository {
+	return &SQLAchievementRepository{db: db}
+}
+
+// GetHistory retrieves every review and study session, along with the words of the groups
+// with the given slugs
+func (r *SQLAchievementRepository) GetHistory(ctx context.Context, groupSlugs []string) (*AchievementHistory, error) {
+	history := &AchievementHistory{GroupWords: map[string]map[int64]bool{}}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT word_id, study_session_id, correct, created_at
+		FROM word_review_items
+		ORDER BY julianday(created_at), id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var review ReviewRecord
+		if err := rows.Scan(&review.WordID, &review.StudySessionID, &review.Correct, &review.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		history.Reviews = append(history.Reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	sessionRows, err := r.db.QueryContext(ctx, `
+		SELECT id, created_at, completed_at
+		FROM study_sessions
+		ORDER BY julianday(created_at), id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch study sessions: %w", err)
+	}
+	defer sessionRows.Close()
+	for sessionRows.Next() {
+		var session SessionRecord
+		var completedAt sql.NullTime
+		if err := sessionRows.Scan(&session.ID, &session.CreatedAt, &completedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan study session: %w", err)
+		}
+		if completedAt.Valid {
+			session.CompletedAt = &completedAt.Time
+		}
+		history.Sessions = append(history.Sessions, session)
+	}
+	if err := sessionRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch study sessions: %w", err)
+	}
+
+	for _, slug := range groupSlugs {
+		words, err := r.groupWords(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		history.GroupWords[slug] = words
+	}
+	return history, nil
+}
+
+// groupWords returns the words of the group with the given slug, none when no group has it
+func (r *SQLAchievementRepository) groupWords(ctx context.Context, slug string) (map[int64]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT wg.word_id
+		FROM word_groups wg
+		JOIN groups g ON g.id = wg.group_id
+		WHERE g.slug = ?
+	`, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch words of group %q: %w", slug, err)
+	}
+	defer rows.Close()
+
+	words := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan word of group %q: %w", slug, err)
+		}
+		words[id] = true
+	}
+	return words, rows.Err()
+}
+
+// ListAwards retrieves the achievements earned so far
+func (r *SQLAchievementRepository) ListAwards(ctx context.Context) ([]models.AchievementAward, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT achievement_id, awarded_at, study_session_id
+		FROM achievement_awards
+		ORDER BY julianday(awarded_at), achievement_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch achievement awards: %w", err)
+	}
+	defer rows.Close()
+
+	awards := []models.AchievementAward{}
+	for rows.Next() {
+		var award models.AchievementAward
+		var sessionID sql.NullInt64
+		if err := rows.Scan(&award.AchievementID, &award.AwardedAt, &sessionID); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement award: %w", err)
+		}
+		if sessionID.Valid {
+			award.StudySessionID = &sessionID.Int64
+		}
+		awards = append(awards, award)
+	}
+	return awards, rows.Err()
+}
+
+// SaveAwards stores awards not stored yet and returns those; an achievement is only awarded once
+func (r *SQLAchievementRepository) SaveAwards(ctx context.Context, awards []models.AchievementAward) ([]models.AchievementAward, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var saved []models.AchievementAward
+	for _, award := range awards {
+		var sessionID interface{}
+		if award.StudySessionID != nil {
+			sessionID = *award.StudySessionID
+		}
+		result, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO achievement_awards (achievement_id, awarded_at, study_session_id)
+			VALUES (?, ?, ?)
+		`, award.AchievementID, award.AwardedAt.UTC(), sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save achievement award: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check saved achievement award: %w", err)
+		}
+		if affected > 0 {
+			saved = append(saved, award)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit achievement awards: %w", err)
+	}
+	return saved, nil
+}
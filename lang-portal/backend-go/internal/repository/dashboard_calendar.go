@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+// GetCalendarDays counts reviews and started sessions per day in loc, for every day from the
+// day of from up to the day before to; days are given as midnight in loc
+func (r *SQLDashboardRepository) GetCalendarDays(ctx context.Context, from, to time.Time, loc *time.Location) ([]models.CalendarDay, error) {
+	// SQLite only knows UTC, so count per quarter hour and assign quarters to local days here
+	query := `
+		SELECT quarter, SUM(reviews), SUM(sessions)
+		FROM (
+			SELECT CAST(julianday(created_at) * ? AS INTEGER) AS quarter, 1 AS reviews, 0 AS sessions
+			FROM word_review_items
+			WHERE julianday(created_at) >= julianday(?) AND julianday(created_at) < julianday(?)
+			UNION ALL
+			SELECT CAST(julianday(created_at) * ? AS INTEGER), 0, 1
+			FROM study_sessions
+			WHERE julianday(created_at) >= julianday(?) AND julianday(created_at) < julianday(?)
+		)
+		GROUP BY quarter
+	`
+	start, end := sqliteTime(from), sqliteTime(to)
+	rows, err := r.db.QueryContext(ctx, query, quartersPerDay, start, end, quartersPerDay, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve calendar activity: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]*models.CalendarDay{}
+	for rows.Next() {
+		var quarter int64
+		var reviews, sessions int
+		if err := rows.Scan(&quarter, &reviews, &sessions); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar activity: %w", err)
+		}
+		date := julianToTime(float64(quarter) / quartersPerDay).In(loc).Format(models.HistoryDateFormat)
+		day, ok := counts[date]
+		if !ok {
+			day = &models.CalendarDay{Date: date}
+			counts[date] = day
+		}
+		day.Reviews += reviews
+		day.Sessions += sessions
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve calendar activity: %w", err)
+	}
+
+	var days []models.CalendarDay
+	for day := from.In(loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(models.HistoryDateFormat)
+		if count, ok := counts[date]; ok {
+			days = append(days, *count)
+		} else {
+			days = append(days, models.CalendarDay{Date: date})
+		}
+	}
+	return days, nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+// maxSessionDuration caps the time counted for one study session, so a
+// session left open overnight does not dominate the time spent chart
+const maxSessionDuration = 3 * time.Hour
+
+// sessionSecondsExpr is the duration in seconds of study session ss, which lasts until it
+// was completed or until its last review, whichever came later
+const sessionSecondsExpr = `(MAX(
+	COALESCE(julianday(ss.completed_at), julianday(ss.created_at)),
+	COALESCE(
+		(SELECT MAX(julianday(wri.created_at)) FROM word_review_items wri WHERE wri.study_session_id = ss.id),
+		julianday(ss.created_at)
+	)
+) - julianday(ss.created_at)) * 86400`
+
+// bucketExpr returns the SQL expression truncating a timestamp column to the first day of its bucket
+func bucketExpr(bucket, column string) string {
+	switch bucket {
+	case models.BucketWeek:
+		return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", column)
+	case models.BucketMonth:
+		return fmt.Sprintf("date(%s, 'start of month')", column)
+	default:
+		return fmt.Sprintf("date(%s)", column)
+	}
+}
+
+// historyScope returns the conditions and arguments limiting study sessions ss to the group and activity of params
+func historyScope(params models.HistoryQueryParams) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if params.GroupID != nil {
+		conditions = append(conditions, "ss.group_id = ?")
+		args = append(args, *params.GroupID)
+	}
+	if params.StudyActivityID != nil {
+		conditions = append(conditions, "ss.study_activity_id = ?")
+		args = append(args, *params.StudyActivityID)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " AND " + strings.Join(conditions, " AND "), args
+}
+
+// countByBucket runs a query returning (bucket, values...) rows and indexes the values by bucket
+func (r *SQLDashboardRepository) countByBucket(ctx context.Context, query string, args []interface{}, columns int) (map[string][]int64, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string][]int64{}
+	for rows.Next() {
+		var bucket string
+		values := make([]int64, columns)
+		dest := []interface{}{&bucket}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		counts[bucket] = values
+	}
+	return counts, rows.Err()
+}
+
+// GetReviewHistory counts reviews and correct answers per bucket
+func (r *SQLDashboardRepository) GetReviewHistory(ctx context.Context, params models.HistoryQueryParams) ([]models.ReviewHistoryPoint, error) {
+	from, to := params.Range()
+	scope, scopeArgs := historyScope(params)
+	query := `
+		SELECT
+			` + bucketExpr(params.Bucket, "wri.created_at") + ` AS bucket,
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN wri.correct = 1 THEN 1 ELSE 0 END), 0)
+		FROM word_review_items wri
+		JOIN study_sessions ss ON ss.id = wri.study_session_id
+		WHERE date(wri.created_at) BETWEEN ? AND ?` + scope + `
+		GROUP BY bucket
+	`
+	args := append([]interface{}{from.Format(models.HistoryDateFormat), to.Format(models.HistoryDateFormat)}, scopeArgs...)
+
+	counts, err := r.countByBucket(ctx, query, args, 2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve review history: %w", err)
+	}
+
+	starts := params.BucketStarts()
+	points := make([]models.ReviewHistoryPoint, 0, len(starts))
+	for _, start := range starts {
+		point := models.ReviewHistoryPoint{Date: start.Format(models.HistoryDateFormat)}
+		if values, ok := counts[point.Date]; ok {
+			point.Reviews = int(values[0])
+			point.Correct = int(values[1])
+			point.Accuracy = int(math.Round(100 * float64(point.Correct) / float64(point.Reviews)))
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// GetNewWordsHistory counts words answered correctly for the first time per bucket,
+// along with the running total of such words
+func (r *SQLDashboardRepository) GetNewWordsHistory(ctx context.Context, params models.HistoryQueryParams) ([]models.NewWordsHistoryPoint, error) {
+	_, to := params.Range()
+	scope, scopeArgs := historyScope(params)
+	// Words learned before the range are counted too, as the base of the running total
+	query := `
+		WITH learned AS (
+			SELECT wri.word_id, MIN(julianday(wri.created_at)) AS learned_at
+			FROM word_review_items wri
+			JOIN study_sessions ss ON ss.id = wri.study_session_id
+			WHERE wri.correct = 1` + scope + `
+			GROUP BY wri.word_id
+		)
+		SELECT ` + bucketExpr(params.Bucket, "learned_at") + ` AS bucket, COUNT(*)
+		FROM learned
+		WHERE date(learned_at) <= ?
+		GROUP BY bucket
+	`
+	args := append(scopeArgs, to.Format(models.HistoryDateFormat))
+
+	counts, err := r.countByBucket(ctx, query, args, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve new words history: %w", err)
+	}
+
+	starts := params.BucketStarts()
+	first := starts[0].Format(models.HistoryDateFormat)
+	total := 0
+	for bucket, values := range counts {
+		if bucket < first {
+			total += int(values[0])
+		}
+	}
+
+	points := make([]models.NewWordsHistoryPoint, 0, len(starts))
+	for _, start := range starts {
+		point := models.NewWordsHistoryPoint{Date: start.Format(models.HistoryDateFormat)}
+		if values, ok := counts[point.Date]; ok {
+			point.NewWords = int(values[0])
+		}
+		total += point.NewWords
+		point.TotalWords = total
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// GetTimeSpentHistory sums the duration of the sessions started in each bucket
+func (r *SQLDashboardRepository) GetTimeSpentHistory(ctx context.Context, params models.HistoryQueryParams) ([]models.TimeSpentHistoryPoint, error) {
+	from, to := params.Range()
+	scope, scopeArgs := historyScope(params)
+	query := `
+		WITH durations AS (
+			SELECT ss.created_at, ` + sessionSecondsExpr + ` AS seconds
+			FROM study_sessions ss
+			WHERE date(ss.created_at) BETWEEN ? AND ?` + scope + `
+		)
+		SELECT
+			` + bucketExpr(params.Bucket, "created_at") + ` AS bucket,
+			COUNT(*),
+			COALESCE(SUM(CAST(ROUND(MIN(MAX(seconds, 0), ?)) AS INTEGER)), 0)
+		FROM durations
+		GROUP BY bucket
+	`
+	args := append([]interface{}{from.Format(models.HistoryDateFormat), to.Format(models.HistoryDateFormat)}, scopeArgs...)
+	args = append(args, maxSessionDuration.Seconds())
+
+	counts, err := r.countByBucket(ctx, query, args, 2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve time spent history: %w", err)
+	}
+
+	starts := params.BucketStarts()
+	points := make([]models.TimeSpentHistoryPoint, 0, len(starts))
+	for _, start := range starts {
+		point := models.TimeSpentHistoryPoint{Date: start.Format(models.HistoryDateFormat)}
+		if values, ok := counts[point.Date]; ok {
+			point.Sessions = int(values[0])
+			point.Seconds = values[1]
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
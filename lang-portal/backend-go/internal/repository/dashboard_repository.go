@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"time"
+
+	"lang-portal/internal/models"
 )
 
 // LastStudySession represents the most recent study session
@@ -28,6 +30,9 @@ type QuickStats struct {
 	TotalStudySessions  int `json:"total_study_sessions"`
 	TotalActiveGroups   int `json:"total_active_groups"`
 	CurrentStreak       int `json:"current_streak"`
+	// GoalsMet counts the active goals reached in their current period, out of GoalsTotal
+	GoalsMet   int `json:"goals_met"`
+	GoalsTotal int `json:"goals_total"`
 }
 
 // DashboardRepository defines methods for retrieving dashboard-related data
@@ -40,16 +45,30 @@ type DashboardRepository interface {
 
 	// GetQuickStats retrieves quick dashboard statistics
 	GetQuickStats(ctx context.Context) (*QuickStats, error)
+
+	// GetReviewHistory counts reviews and their accuracy per bucket
+	GetReviewHistory(ctx context.Context, params models.HistoryQueryParams) ([]models.ReviewHistoryPoint, error)
+
+	// GetNewWordsHistory counts newly learned words per bucket
+	GetNewWordsHistory(ctx context.Context, params models.HistoryQueryParams) ([]models.NewWordsHistoryPoint, error)
+
+	// GetTimeSpentHistory sums study time per bucket
+	GetTimeSpentHistory(ctx context.Context, params models.HistoryQueryParams) ([]models.TimeSpentHistoryPoint, error)
+
+	// GetCalendarDays counts reviews and sessions per local day in [from, to)
+	GetCalendarDays(ctx context.Context, from, to time.Time, loc *time.Location) ([]models.CalendarDay, error)
 }
 
 // SQLDashboardRepository implements DashboardRepository using SQLite
 type SQLDashboardRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	streaks StreakSource
+	goals   GoalSource
 }
 
 // NewDashboardRepository creates a new instance of SQLDashboardRepository
-func NewDashboardRepository(db *sql.DB) *SQLDashboardRepository {
-	return &SQLDashboardRepository{db: db}
+func NewDashboardRepository(db *sql.DB, streaks StreakSource, goals GoalSource) *SQLDashboardRepository {
+	return &SQLDashboardRepository{db: db, streaks: streaks, goals: goals}
 }
 
 // GetLastStudySession retrieves the most recent study session
@@ -156,34 +175,24 @@ func (r *SQLDashboardRepository) GetQuickStats(ctx context.Context) (*QuickStats
 		return nil, fmt.Errorf("failed to count active groups: %w", err)
 	}
 
-	// Calculate current streak (consecutive days with study sessions)
-	// Note: This is a simplified implementation and might need more complex logic
-	currentStreakQuery := `
-		WITH study_days AS (
-			SELECT DISTINCT date(created_at) as study_date
-			FROM study_sessions
-			ORDER BY study_date DESC
-		), consecutive_days AS (
-			SELECT 
-				study_date, 
-				julianday('now') - julianday(study_date) as days_ago,
-				ROW_NUMBER() OVER (ORDER BY study_date DESC) as rn
-			FROM study_days
-		)
-		SELECT COUNT(*) 
-		FROM consecutive_days 
-		WHERE days_ago = rn - 1
-	`
-	var currentStreak int
-	err = r.db.QueryRowContext(ctx, currentStreakQuery).Scan(&currentStreak)
+	// Calculate current streak in the configured timezone
+	currentStreak, err := r.streaks.CurrentStreak(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate current streak: %w", err)
 	}
 
+	// Count the goals reached so far in the configured timezone
+	goalsMet, goalsTotal, err := r.goals.GoalsMet(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate goal progress: %w", err)
+	}
+
 	return &QuickStats{
 		SuccessRate:        successRate,
 		TotalStudySessions: totalStudySessions,
 		TotalActiveGroups:  totalActiveGroups,
 		CurrentStreak:      currentStreak,
+		GoalsMet:           goalsMet,
+		GoalsTotal:         goalsTotal,
 	}, nil
 }
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"lang-portal/internal/dictionary"
+	"lang-portal/internal/models"
+)
+
+// Lookup size bounds
+const (
+	DefaultLookupLimit = 20
+	MaxLookupLimit     = 100
+)
+
+// DictionaryRepository stores the JMdict dictionary teachers look words up in
+type DictionaryRepository interface {
+	// Import replaces the dictionary with the entries read hands to add, in one transaction
+	// so that a failed import keeps the previous dictionary; it returns how many were stored
+	Import(ctx context.Context, read func(add func(*models.DictionaryEntry) error) error) (int, error)
+
+	// Lookup finds up to limit entries written or read as query or a word starting with it,
+	// or with an English gloss starting with it, or read as romaji query or a word starting with it
+	Lookup(ctx context.Context, query string, limit int) ([]models.DictionaryEntry, error)
+
+	// GetEntry retrieves an entry by its sequence number
+	GetEntry(ctx context.Context, id int64) (*models.DictionaryEntry, error)
+}
+
+// SQLDictionaryRepository implements DictionaryRepository using SQLite
+type SQLDictionaryRepository struct {
+	db *sql.DB
+}
+
+// NewDictionaryRepository creates a new instance of SQLDictionaryRepository
+func NewDictionaryRepository(db *sql.DB) *SQLDictionaryRepository {
+	return &SQLDictionaryRepository{db: db}
+}
+
+// entryColumns lists the columns of the dictionary_entries table e scanned by scanEntry
+const entryColumns = `e.id, e.kanji, e.readings, e.senses, e.common`
+
+// scanEntry scans entryColumns
+func scanEntry(row rowScanner) (*models.DictionaryEntry, error) {
+	var entry models.DictionaryEntry
+	var kanji, readings, senses string
+	if err := row.Scan(&entry.ID, &kanji, &readings, &senses, &entry.Common); err != nil {
+		return nil, err
+	}
+	for _, field := range []struct {
+		data string
+		dest interface{}
+	}{
+		{kanji, &entry.Kanji},
+		{readings, &entry.Readings},
+		{senses, &entry.Senses},
+	} {
+		if err := json.Unmarshal([]byte(field.data), field.dest); err != nil {
+			return nil, fmt.Errorf("failed to parse dictionary entry %d: %w", entry.ID, err)
+		}
+	}
+	return &entry, nil
+}
+
+// Import replaces the dictionary in one transaction; JMdict is too large to hold in
+// memory, so entries are stored as they are read
+func (r *SQLDictionaryRepository) Import(ctx context.Context, read func(add func(*models.DictionaryEntry) error) error) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"dictionary_glosses", "dictionary_readings", "dictionary_forms", "dictionary_entries"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table); err != nil {
+			return 0, fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+	}
+
+	var statements [4]*sql.Stmt
+	for i, query := range []string{
+		`INSERT OR REPLACE INTO dictionary_entries (id, kanji, readings, senses, common) VALUES (?, ?, ?, ?, ?)`,
+		`INSERT OR IGNORE INTO dictionary_forms (text, entry_id) VALUES (?, ?)`,
+		`INSERT OR IGNORE INTO dictionary_readings (text, entry_id) VALUES (?, ?)`,
+		`INSERT INTO dictionary_glosses (gloss, entry_id, sense) VALUES (?, ?, ?)`,
+	} {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prepare dictionary insert: %w", err)
+		}
+		defer stmt.Close()
+		statements[i] = stmt
+	}
+	insertEntry, insertForm, insertReading, insertGloss := statements[0], statements[1], statements[2], statements[3]
+
+	imported := 0
+	err = read(func(entry *models.DictionaryEntry) error {
+		columns := make([]interface{}, 0, 3)
+		for _, value := range []interface{}{entry.Kanji, entry.Readings, entry.Senses} {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("failed to encode dictionary entry %d: %w", entry.ID, err)
+			}
+			columns = append(columns, string(data))
+		}
+		if _, err := insertEntry.ExecContext(ctx, entry.ID, columns[0], columns[1], columns[2], entry.Common); err != nil {
+			return fmt.Errorf("failed to import dictionary entry %d: %w", entry.ID, err)
+		}
+
+		var forms []string
+		for _, k := range entry.Kanji {
+			forms = append(forms, k.Text)
+		}
+		for _, reading := range entry.Readings {
+			forms = append(forms, reading.Text)
+		}
+		for _, form := range forms {
+			if _, err := insertForm.ExecContext(ctx, dictionary.Hiragana(form), entry.ID); err != nil {
+				return fmt.Errorf("failed to import dictionary entry %d: %w", entry.ID, err)
+			}
+		}
+		for _, reading := range entry.Readings {
+			if _, err := insertReading.ExecContext(ctx, dictionary.FoldLong(dictionary.Hiragana(reading.Text)), entry.ID); err != nil {
+				return fmt.Errorf("failed to import dictionary entry %d: %w", entry.ID, err)
+			}
+		}
+		for i, sense := range entry.Senses {
+			for _, gloss := range sense.Glosses {
+				if _, err := insertGloss.ExecContext(ctx, strings.ToLower(gloss), entry.ID, i); err != nil {
+					return fmt.Errorf("failed to import dictionary entry %d: %w", entry.ID, err)
+				}
+			}
+		}
+		imported++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit dictionary import: %w", err)
+	}
+	return imported, nil
+}
+
+// Lookup searches the written forms and readings when query contains kanji or kana,
+// and the English glosses otherwise, along with the readings when query is romaji.
+// Romaji is converted to kana and compared with long vowels folded, so kohi finds
+// コーヒー. Exact matches come first, then glosses before readings, then common words;
+// gloss matches are ranked by the first sense they are found in.
+func (r *SQLDictionaryRepository) Lookup(ctx context.Context, query string, limit int) ([]models.DictionaryEntry, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []models.DictionaryEntry{}, nil
+	}
+
+	// Prefix matches sort between the prefix and the prefix followed by the last character
+	var matches string
+	var args []interface{}
+	if dictionary.HasJapanese(query) {
+		query = dictionary.Hiragana(query)
+		matches = `
+			SELECT entry_id, text = ? AS exact, 0 AS romaji, 0 AS sense
+			FROM dictionary_forms
+			WHERE text >= ? AND text < ?
+		`
+		args = []interface{}{query, query, query + "\U0010FFFF"}
+	} else {
+		// English verbs are glossed "to ..."
+		query = strings.ToLower(query)
+		verb := "to " + query
+		matches = `
+			SELECT entry_id, gloss IN (?, ?) AS exact, 0 AS romaji, sense
+			FROM dictionary_glosses
+			WHERE (gloss >= ? AND gloss < ?) OR (gloss >= ? AND gloss < ?)
+		`
+		args = []interface{}{query, verb, query, query + "\U0010FFFF", verb, verb + "\U0010FFFF"}
+		if kana, ok := dictionary.Kana(query); ok {
+			kana = dictionary.FoldLong(kana)
+			matches += `
+				UNION ALL
+				SELECT entry_id, text = ? AS exact, 1 AS romaji, 0 AS sense
+				FROM dictionary_readings
+				WHERE text >= ? AND text < ?
+			`
+			args = append(args, kana, kana, kana+"\U0010FFFF")
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+entryColumns+`
+		FROM (
+			SELECT entry_id, MAX(exact) AS exact, MIN(romaji) AS romaji, MIN(sense) AS sense
+			FROM (`+matches+`)
+			GROUP BY entry_id
+		) m
+		JOIN dictionary_entries e ON e.id = m.entry_id
+		ORDER BY m.exact DESC, m.romaji, e.common DESC, m.sense, e.id
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up dictionary: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.DictionaryEntry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dictionary entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetEntry retrieves an entry by its sequence number
+func (r *SQLDictionaryRepository) GetEntry(ctx context.Context, id int64) (*models.DictionaryEntry, error) {
+	entry, err := scanEntry(r.db.QueryRowContext(ctx, `
+		SELECT `+entryColumns+` FROM dictionary_entries e WHERE e.id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("dictionary entry not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dictionary entry: %w", err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+// GoalRepository defines the interface for learner goals and the activity they are measured against
+type GoalRepository interface {
+	// ListGoals retrieves the goals of a learner, or of the portal's own user when learnerID is nil
+	ListGoals(ctx context.Context, learnerID *int64, activeOnly bool) ([]models.Goal, error)
+
+	// GetGoal retrieves a goal by ID
+	GetGoal(ctx context.Context, id int64) (*models.Goal, error)
+
+	// CreateGoal adds a goal; a learner has at most one goal per metric and period
+	CreateGoal(ctx context.Context, goal *models.Goal) error
+
+	// UpdateGoal saves the target and active flag of a goal
+	UpdateGoal(ctx context.Context, goal *models.Goal) error
+
+	// DeleteGoal removes a goal
+	DeleteGoal(ctx context.Context, id int64) error
+
+	// GetDailyTotals sums the activity of a learner per day in loc, for every day from the day
+	// of from up to the day before to; days without activity are missing
+	GetDailyTotals(ctx context.Context, learnerID *int64, from, to time.Time, loc *time.Location) (map[string]models.GoalTotals, error)
+}
+
+// GoalSource provides today's goal attainment for dashboard statistics
+type GoalSource interface {
+	GoalsMet(ctx context.Context) (met, total int, err error)
+}
+
+// SQLGoalRepository implements GoalRepository using SQLite
+type SQLGoalRepository struct {
+	db *sql.DB
+}
+
+// NewGoalRepository creates a new instance of SQLGoalRepository
+func NewGoalRepository(db *sql.DB) *SQLGoalRepository {
+	return &SQLGoalRepository{db: db}
+}
+
+// goalColumns lists the columns scanned by scanGoal
+const goalColumns = `id, learner_id, metric, period, target, active, created_at, updated_at`
+
+// scanGoal scans a row selected with goalColumns
+func scanGoal(row rowScanner) (*models.Goal, error) {
+	var g models.Goal
+	var learnerID sql.NullInt64
+	if err := row.Scan(&g.ID, &learnerID, &g.Metric, &g.Period, &g.Target, &g.Active, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if learnerID.Valid {
+		g.LearnerID = &learnerID.Int64
+	}
+	return &g, nil
+}
+
+// learnerArg returns the query argument matching learner_id with IS, which also matches NULL
+func learnerArg(learnerID *int64) interface{} {
+	if learnerID == nil {
+		return nil
+	}
+	return *learnerID
+}
+
+// ListGoals retrieves the goals of a learner, or of the portal's own user when learnerID is nil
+func (r *SQLGoalRepository) ListGoals(ctx context.Context, learnerID *int64, activeOnly bool) ([]models.Goal, error) {
+	query := `SELECT ` + goalColumns + ` FROM goals WHERE learner_id IS ?`
+	if activeOnly {
+		query += ` AND active = 1`
+	}
+	query += ` ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, learnerArg(learnerID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch goals: %w", err)
+	}
+	defer rows.Close()
+
+	goals := []models.Goal{}
+	for rows.Next() {
+		g, err := scanGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan goal: %w", err)
+		}
+		goals = append(goals, *g)
+	}
+	return goals, rows.Err()
+}
+
+// GetGoal retrieves a goal by ID
+func (r *SQLGoalRepository) GetGoal(ctx context.Context, id int64) (*models.Goal, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+goalColumns+` FROM goals WHERE id = ?`, id)
+	g, err := scanGoal(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("goal not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch goal: %w", err)
+	}
+	return g, nil
+}
+
+// CreateGoal adds a goal; a learner has at most one goal per metric and period
+func (r *SQLGoalRepository) CreateGoal(ctx context.Context, goal *models.Goal) error {
+	if goal.LearnerID != nil {
+		var exists int
+		err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM learners WHERE id = ?`, *goal.LearnerID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check learner: %w", err)
+		}
+		if exists == 0 {
+			return fmt.Errorf("learner not found")
+		}
+	}
+
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO goals (learner_id, metric, period, target, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, learnerArg(goal.LearnerID), goal.Metric, goal.Period, goal.Target, goal.Active, now, now)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("goal already exists")
+		}
+		return fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	goal.ID = id
+	goal.CreatedAt = now
+	goal.UpdatedAt = now
+	return nil
+}
+
+// UpdateGoal saves the target and active flag of a goal
+func (r *SQLGoalRepository) UpdateGoal(ctx context.Context, goal *models.Goal) error {
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE goals SET target = ?, active = ?, updated_at = ? WHERE id = ?
+	`, goal.Target, goal.Active, now, goal.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update goal: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated goal: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("goal not found")
+	}
+	goal.UpdatedAt = now
+	return nil
+}
+
+// DeleteGoal removes a goal
+func (r *SQLGoalRepository) DeleteGoal(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM goals WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted goal: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("goal not found")
+	}
+	return nil
+}
+
+// GetDailyTotals sums the reviews, session durations and newly learned words of a learner per
+// day in loc. Sessions count on the day they started; words count on the day of their first
+// correct answer by the learner.
+func (r *SQLGoalRepository) GetDailyTotals(ctx context.Context, learnerID *int64, from, to time.Time, loc *time.Location) (map[string]models.GoalTotals, error) {
+	// SQLite only knows UTC, so sum per quarter hour and assign quarters to local days here
+	query := `
+		WITH learned AS (
+			SELECT MIN(julianday(wri.created_at)) AS learned_at
+			FROM word_review_items wri
+			JOIN study_sessions ss ON ss.id = wri.study_session_id
+			WHERE wri.correct = 1 AND ss.learner_id IS ?
+			GROUP BY wri.word_id
+		)
+		SELECT quarter, SUM(reviews), SUM(seconds), SUM(new_words)
+		FROM (
+			SELECT CAST(julianday(wri.created_at) * ? AS INTEGER) AS quarter, 1 AS reviews, 0 AS seconds, 0 AS new_words
+			FROM word_review_items wri
+			JOIN study_sessions ss ON ss.id = wri.study_session_id
+			WHERE ss.learner_id IS ?
+				AND julianday(wri.created_at) >= julianday(?) AND julianday(wri.created_at) < julianday(?)
+			UNION ALL
+			SELECT CAST(julianday(ss.created_at) * ? AS INTEGER), 0, CAST(ROUND(MIN(MAX(` + sessionSecondsExpr + `, 0), ?)) AS INTEGER), 0
+			FROM study_sessions ss
+			WHERE ss.learner_id IS ?
+				AND julianday(ss.created_at) >= julianday(?) AND julianday(ss.created_at) < julianday(?)
+			UNION ALL
+			SELECT CAST(learned_at * ? AS INTEGER), 0, 0, 1
+			FROM learned
+			WHERE learned_at >= julianday(?) AND learned_at < julianday(?)
+		)
+		GROUP BY quarter
+	`
+	learner, start, end := learnerArg(learnerID), sqliteTime(from), sqliteTime(to)
+	rows, err := r.db.QueryContext(ctx, query,
+		learner,
+		quartersPerDay, learner, start, end,
+		quartersPerDay, maxSessionDuration.Seconds(), learner, start, end,
+		quartersPerDay, start, end,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve goal activity: %w", err)
+	}
+	defer rows.Close()
+
+	totals := map[string]models.GoalTotals{}
+	for rows.Next() {
+		var quarter int64
+		var quarterTotals models.GoalTotals
+		if err := rows.Scan(&quarter, &quarterTotals.Reviews, &quarterTotals.Seconds, &quarterTotals.NewWords); err != nil {
+			return nil, fmt.Errorf("failed to scan goal activity: %w", err)
+		}
+		date := julianToTime(float64(quarter) / quartersPerDay).In(loc).Format(models.HistoryDateFormat)
+		day := totals[date]
+		day.Add(quarterTotals)
+		totals[date] = day
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve goal activity: %w", err)
+	}
+	return totals, nil
+}
@@ -5,14 +5,38 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
+
+	"lang-portal/internal/models"
 )
 
 // GroupListItem represents a group in the list view
 type GroupListItem struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	WordCount int    `json:"word_count"`
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	WordCount    int    `json:"word_count"`
+	CorrectCount int    `json:"correct_count"`
+	WrongCount   int    `json:"wrong_count"`
+}
+
+// groupSortColumns maps group sort fields to SQL expressions
+var groupSortColumns = map[string]string{
+	"name":          "g.name",
+	"word_count":    "g.words_count",
+	"correct_count": "correct_count",
+	"wrong_count":   "wrong_count",
+}
+
+// groupWordSortColumns maps group word sort fields to SQL expressions
+var groupWordSortColumns = map[string]string{
+	"id":            "w.id",
+	"kanji":         "w.kanji",
+	"romaji":        "w.romaji",
+	"english":       "w.english",
+	"correct_count": "correct_count",
+	"wrong_count":   "wrong_count",
+	"accuracy":      "accuracy",
 }
 
 // GroupDetails represents detailed information about a group
@@ -30,6 +54,8 @@ type GroupWordItem struct {
 	English      string `json:"english"`
 	CorrectCount int    `json:"correct_count"`
 	WrongCount   int    `json:"wrong_count"`
+	// Accuracy is the percentage (0-100) of correct reviews, null for words never reviewed
+	Accuracy *float64 `json:"accuracy"`
 }
 
 // GroupStudySessionItem represents a study session for a group
@@ -54,24 +80,54 @@ type RawGroupWordItem struct {
 	Romaji  string     `json:"romaji"`
 	English string     `json:"english"`
 	Parts   []WordPart `json:"parts"`
+	// AudioURL is where the word's pronunciation is served, when it has one
+	AudioURL string `json:"audio_url,omitempty"`
+	// ImageURL and ImageThumbnailURL are where the word's mnemonic image is served
+	ImageURL          string `json:"image_url,omitempty"`
+	ImageThumbnailURL string `json:"image_thumbnail_url,omitempty"`
 }
 
 // GroupRepository defines the interface for group-related database operations
 type GroupRepository interface {
-	// List retrieves groups with optional pagination
-	List(ctx context.Context, page, groupsPerPage int) ([]GroupListItem, int, error)
+	// List retrieves groups with pagination and sorting
+	List(ctx context.Context, params models.GroupQueryParams) ([]GroupListItem, int, error)
 
 	// GetByID retrieves detailed information about a specific group
 	GetByID(ctx context.Context, groupID int64) (*GroupDetails, error)
 
-	// GetGroupWords retrieves words in a group with pagination
-	GetGroupWords(ctx context.Context, groupID int64, page, wordsPerPage int) ([]GroupWordItem, int, error)
+	// GetGroupWords retrieves words in a group with pagination, sorting and review filters
+	GetGroupWords(ctx context.Context, groupID int64, params models.GroupWordQueryParams) ([]GroupWordItem, int, error)
 
 	// GetGroupStudySessions retrieves study sessions for a group with pagination
 	GetGroupStudySessions(ctx context.Context, groupID int64, page, sessionsPerPage int) ([]GroupStudySessionItem, int, error)
 
 	// GetGroupWordsRaw retrieves all words in a group without pagination
 	GetGroupWordsRaw(ctx context.Context, groupID int64) ([]RawGroupWordItem, error)
+
+	// HasWord reports whether a word belongs to a group
+	HasWord(ctx context.Context, groupID, wordID int64) (bool, error)
+
+	// Create adds a new group, optionally seeded with words
+	Create(ctx context.Context, name string, wordIDs []int64) (*GroupDetails, error)
+
+	// CreateWithWords adds a new group with words, creating the words that do not exist yet
+	// with the same kanji, romaji and meaning; it returns how many words were created
+	CreateWithWords(ctx context.Context, name string, words []models.Word) (*GroupDetails, int, error)
+
+	// Rename changes the name of an existing group
+	Rename(ctx context.Context, groupID int64, name string) (*GroupDetails, error)
+
+	// Delete removes a group and its word associations, refusing groups with study sessions
+	Delete(ctx context.Context, groupID int64) error
+
+	// AddWords links words to a group, returning how many links were created
+	AddWords(ctx context.Context, groupID int64, wordIDs []int64) (int, error)
+
+	// RemoveWords unlinks words from a group, returning how many links were removed
+	RemoveWords(ctx context.Context, groupID int64, wordIDs []int64) (int, error)
+
+	// RecountWords recomputes words_count for every group, returning how many groups were corrected
+	RecountWords(ctx context.Context) (int, error)
 }
 
 // SQLGroupRepository implements GroupRepository using SQLite
@@ -84,30 +140,40 @@ func NewGroupRepository(db *sql.DB) *SQLGroupRepository {
 	return &SQLGroupRepository{db: db}
 }
 
-// List retrieves groups with pagination
-func (r *SQLGroupRepository) List(ctx context.Context, page, groupsPerPage int) ([]GroupListItem, int, error) {
+// List retrieves groups with pagination and sorting
+func (r *SQLGroupRepository) List(ctx context.Context, params models.GroupQueryParams) ([]GroupListItem, int, error) {
+	orderBy, err := orderByClause(params.SortBy, params.Order, groupSortColumns, "g.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Calculate pagination
-	offset := (page - 1) * groupsPerPage
+	offset := (params.Page - 1) * params.PerPage
 
 	// Count total groups
 	countQuery := `SELECT COUNT(*) FROM groups`
 	var totalGroups int
-	err := r.db.QueryRowContext(ctx, countQuery).Scan(&totalGroups)
+	err = r.db.QueryRowContext(ctx, countQuery).Scan(&totalGroups)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count groups: %w", err)
 	}
 
-	// Query to fetch groups with word count
-	query := `
+	// Query to fetch groups with word count and review totals
+	query := fmt.Sprintf(`
 		SELECT 
 			g.id, 
 			g.name, 
-			g.words_count
+			g.words_count,
+			COALESCE(SUM(CASE WHEN wri.correct = 1 THEN 1 ELSE 0 END), 0) as correct_count,
+			COALESCE(SUM(CASE WHEN wri.correct = 0 THEN 1 ELSE 0 END), 0) as wrong_count
 		FROM groups g
-		ORDER BY g.name
+		LEFT JOIN word_groups wg ON wg.group_id = g.id
+		LEFT JOIN word_review_items wri ON wri.word_id = wg.word_id
+		GROUP BY g.id, g.name, g.words_count
+		%s
 		LIMIT ? OFFSET ?
-	`
-	rows, err := r.db.QueryContext(ctx, query, groupsPerPage, offset)
+	`, orderBy)
+	rows, err := r.db.QueryContext(ctx, query, params.PerPage, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list groups: %w", err)
 	}
@@ -120,6 +186,8 @@ func (r *SQLGroupRepository) List(ctx context.Context, page, groupsPerPage int)
 			&group.ID,
 			&group.Name,
 			&group.WordCount,
+			&group.CorrectCount,
+			&group.WrongCount,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan group: %w", err)
 		}
@@ -155,48 +223,70 @@ func (r *SQLGroupRepository) GetByID(ctx context.Context, groupID int64) (*Group
 	return &group, nil
 }
 
-// GetGroupWords retrieves words in a group with pagination
-func (r *SQLGroupRepository) GetGroupWords(ctx context.Context, groupID int64, page, wordsPerPage int) ([]GroupWordItem, int, error) {
+// GetGroupWords retrieves words in a group with pagination, sorting and review filters
+func (r *SQLGroupRepository) GetGroupWords(ctx context.Context, groupID int64, params models.GroupWordQueryParams) ([]GroupWordItem, int, error) {
 	// First, verify the group exists
 	_, err := r.GetByID(ctx, groupID)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Count total words in the group
-	countQuery := `
-		SELECT COUNT(*) 
-		FROM words w
-		JOIN word_groups wg ON w.id = wg.word_id
-		WHERE wg.group_id = ?
-	`
-	var totalWords int
-	err = r.db.QueryRowContext(ctx, countQuery, groupID).Scan(&totalWords)
+	orderBy, err := orderByClause(params.SortBy, params.Order, groupWordSortColumns, "w.id")
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count group words: %w", err)
+		return nil, 0, err
 	}
 
-	// Calculate pagination
-	offset := (page - 1) * wordsPerPage
+	// Build review filters, applied after aggregation
+	var having []string
+	args := []interface{}{groupID}
+	if params.NeverReviewed {
+		having = append(having, "COUNT(wri.id) = 0")
+	}
+	if params.MaxAccuracy != nil {
+		having = append(having, "COUNT(wri.id) > 0", "100.0 * SUM(CASE WHEN wri.correct = 1 THEN 1 ELSE 0 END) / COUNT(wri.id) < ?")
+		args = append(args, *params.MaxAccuracy)
+	}
+	havingClause := ""
+	if len(having) > 0 {
+		havingClause = "HAVING " + strings.Join(having, " AND ")
+	}
 
-	// Query to fetch words with review statistics
-	query := `
+	// Words with their review statistics, shared by the count and page queries
+	baseQuery := fmt.Sprintf(`
 		SELECT 
 			w.id, 
 			w.kanji, 
 			w.romaji, 
 			w.english,
 			COALESCE(SUM(CASE WHEN wri.correct = 1 THEN 1 ELSE 0 END), 0) as correct_count,
-			COALESCE(SUM(CASE WHEN wri.correct = 0 THEN 1 ELSE 0 END), 0) as wrong_count
+			COALESCE(SUM(CASE WHEN wri.correct = 0 THEN 1 ELSE 0 END), 0) as wrong_count,
+			CASE 
+				WHEN COUNT(wri.id) > 0 
+				THEN 100.0 * SUM(CASE WHEN wri.correct = 1 THEN 1 ELSE 0 END) / COUNT(wri.id)
+				ELSE NULL 
+			END as accuracy
 		FROM words w
 		JOIN word_groups wg ON w.id = wg.word_id
 		LEFT JOIN word_review_items wri ON w.id = wri.word_id
 		WHERE wg.group_id = ?
 		GROUP BY w.id, w.kanji, w.romaji, w.english
-		ORDER BY w.id
-		LIMIT ? OFFSET ?
-	`
-	rows, err := r.db.QueryContext(ctx, query, groupID, wordsPerPage, offset)
+		%s
+	`, havingClause)
+
+	// Count total matching words in the group
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM (%s)`, baseQuery)
+	var totalWords int
+	err = r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalWords)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count group words: %w", err)
+	}
+
+	// Calculate pagination
+	offset := (params.Page - 1) * params.PerPage
+
+	// Query to fetch the requested page of words
+	query := fmt.Sprintf(`%s %s LIMIT ? OFFSET ?`, baseQuery, orderBy)
+	rows, err := r.db.QueryContext(ctx, query, append(args, params.PerPage, offset)...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to fetch group words: %w", err)
 	}
@@ -205,6 +295,7 @@ func (r *SQLGroupRepository) GetGroupWords(ctx context.Context, groupID int64, p
 	var words []GroupWordItem
 	for rows.Next() {
 		var word GroupWordItem
+		var accuracy sql.NullFloat64
 		if err := rows.Scan(
 			&word.ID,
 			&word.Kanji,
@@ -212,9 +303,13 @@ func (r *SQLGroupRepository) GetGroupWords(ctx context.Context, groupID int64, p
 			&word.English,
 			&word.CorrectCount,
 			&word.WrongCount,
+			&accuracy,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan group word: %w", err)
 		}
+		if accuracy.Valid {
+			word.Accuracy = &accuracy.Float64
+		}
 		words = append(words, word)
 	}
 
@@ -297,7 +392,9 @@ func (r *SQLGroupRepository) GetGroupWordsRaw(ctx context.Context, groupID int64
 			w.kanji, 
 			w.romaji, 
 			w.english,
-			w.parts
+			w.parts,
+			` + wordAudioHash("w") + `,
+			` + wordImageHashes("w") + `
 		FROM words w
 		JOIN word_groups wg ON w.id = wg.word_id
 		WHERE wg.group_id = ?
@@ -313,15 +410,21 @@ func (r *SQLGroupRepository) GetGroupWordsRaw(ctx context.Context, groupID int64
 	for rows.Next() {
 		var word RawGroupWordItem
 		var partsJSON string
+		var audioHash, imageHash, thumbnailHash sql.NullString
 		if err := rows.Scan(
 			&word.ID,
 			&word.Kanji,
 			&word.Romaji,
 			&word.English,
 			&partsJSON,
+			&audioHash,
+			&imageHash,
+			&thumbnailHash,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan group word: %w", err)
 		}
+		word.AudioURL = mediaURL(audioHash)
+		word.ImageURL, word.ImageThumbnailURL = mediaURL(imageHash), mediaURL(thumbnailHash)
 
 		// Unmarshal parts JSON
 		if err := json.Unmarshal([]byte(partsJSON), &word.Parts); err != nil {
@@ -333,3 +436,301 @@ func (r *SQLGroupRepository) GetGroupWordsRaw(ctx context.Context, groupID int64
 
 	return words, nil
 }
+
+// HasWord reports whether a word belongs to a group
+func (r *SQLGroupRepository) HasWord(ctx context.Context, groupID, wordID int64) (bool, error) {
+	query := `SELECT COUNT(*) FROM word_groups WHERE group_id = ? AND word_id = ?`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, groupID, wordID).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check group word: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Create adds a new group, optionally seeded with words
+func (r *SQLGroupRepository) Create(ctx context.Context, name string, wordIDs []int64) (*GroupDetails, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO groups (name, words_count) VALUES (?, 0)`, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group: %w", err)
+	}
+
+	groupID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	if _, err := addWordsTx(ctx, tx, groupID, wordIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit group creation: %w", err)
+	}
+
+	return r.GetByID(ctx, groupID)
+}
+
+// CreateWithWords adds a new group with words, creating the words that do not exist yet
+// with the same kanji, romaji and meaning; it returns how many words were created
+func (r *SQLGroupRepository) CreateWithWords(ctx context.Context, name string, words []models.Word) (*GroupDetails, int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO groups (name, words_count) VALUES (?, 0)`, name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create group: %w", err)
+	}
+	groupID, err := result.LastInsertId()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	created := 0
+	wordIDs := make([]int64, 0, len(words))
+	for _, word := range words {
+		wordID, err := findWordID(ctx, tx, word.Kanji, word.Romaji, word.English)
+		if err == sql.ErrNoRows {
+			result, err := tx.ExecContext(ctx, `
+				INSERT INTO words (kanji, romaji, english, parts) VALUES (?, ?, ?, ?)
+			`, word.Kanji, word.Romaji, word.English, string(word.Parts))
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to create word: %w", err)
+			}
+			if wordID, err = result.LastInsertId(); err != nil {
+				return nil, 0, fmt.Errorf("failed to get last insert ID: %w", err)
+			}
+			if err := linkWordKanji(ctx, tx, wordID, word.Kanji, word.Parts); err != nil {
+				return nil, 0, err
+			}
+			created++
+		} else if err != nil {
+			return nil, 0, err
+		}
+		wordIDs = append(wordIDs, wordID)
+	}
+
+	if _, err := addWordsTx(ctx, tx, groupID, wordIDs); err != nil {
+		return nil, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("failed to commit group creation: %w", err)
+	}
+
+	group, err := r.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return group, created, nil
+}
+
+// Rename changes the name of an existing group
+func (r *SQLGroupRepository) Rename(ctx context.Context, groupID int64, name string) (*GroupDetails, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE groups SET name = ? WHERE id = ?`, name, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename group: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check renamed group: %w", err)
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("group not found")
+	}
+
+	return r.GetByID(ctx, groupID)
+}
+
+// Delete removes a group and its word associations. Groups that were studied are kept,
+// as deleting them would cascade to their study sessions and reviews.
+func (r *SQLGroupRepository) Delete(ctx context.Context, groupID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := groupExistsTx(ctx, tx, groupID); err != nil {
+		return err
+	}
+	var sessions int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM study_sessions WHERE group_id = ?`, groupID).Scan(&sessions); err != nil {
+		return fmt.Errorf("failed to count study sessions of group: %w", err)
+	}
+	if sessions > 0 {
+		return fmt.Errorf("group has study sessions")
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM groups WHERE id = ?`, groupID)
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted group: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("group not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group deletion: %w", err)
+	}
+	return nil
+}
+
+// AddWords links words to a group, returning how many links were created
+func (r *SQLGroupRepository) AddWords(ctx context.Context, groupID int64, wordIDs []int64) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := groupExistsTx(ctx, tx, groupID); err != nil {
+		return 0, err
+	}
+
+	added, err := addWordsTx(ctx, tx, groupID, wordIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit group words: %w", err)
+	}
+
+	return added, nil
+}
+
+// RemoveWords unlinks words from a group, returning how many links were removed
+func (r *SQLGroupRepository) RemoveWords(ctx context.Context, groupID int64, wordIDs []int64) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := groupExistsTx(ctx, tx, groupID); err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `DELETE FROM word_groups WHERE word_id = ? AND group_id = ?`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare word_groups delete statement: %w", err)
+	}
+	defer stmt.Close()
+
+	removed := 0
+	for _, wordID := range wordIDs {
+		result, err := stmt.ExecContext(ctx, wordID, groupID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to remove word from group: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to check removed word: %w", err)
+		}
+		removed += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit group words: %w", err)
+	}
+
+	return removed, nil
+}
+
+// RecountWords recomputes words_count for every group, returning how many groups were corrected
+func (r *SQLGroupRepository) RecountWords(ctx context.Context) (int, error) {
+	query := `
+		UPDATE groups
+		SET words_count = (SELECT COUNT(*) FROM word_groups wg WHERE wg.group_id = groups.id)
+		WHERE words_count IS NULL
+			OR words_count <> (SELECT COUNT(*) FROM word_groups wg WHERE wg.group_id = groups.id)
+	`
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recount group words: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check recounted groups: %w", err)
+	}
+
+	return int(affected), nil
+}
+
+// groupExistsTx verifies a group exists within a transaction
+func groupExistsTx(ctx context.Context, tx *sql.Tx, groupID int64) error {
+	var exists int
+	err := tx.QueryRowContext(ctx, `SELECT 1 FROM groups WHERE id = ?`, groupID).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("group not found")
+		}
+		return fmt.Errorf("failed to validate group: %w", err)
+	}
+	return nil
+}
+
+// addWordsTx links words to a group within a transaction; words_count is maintained by triggers
+func addWordsTx(ctx context.Context, tx *sql.Tx, groupID int64, wordIDs []int64) (int, error) {
+	if len(wordIDs) == 0 {
+		return 0, nil
+	}
+
+	// Make sure every word exists before linking anything
+	wordStmt, err := tx.PrepareContext(ctx, `SELECT 1 FROM words WHERE id = ?`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare word lookup statement: %w", err)
+	}
+	defer wordStmt.Close()
+
+	var missing []string
+	for _, wordID := range wordIDs {
+		var exists int
+		if err := wordStmt.QueryRowContext(ctx, wordID).Scan(&exists); err != nil {
+			if err == sql.ErrNoRows {
+				missing = append(missing, fmt.Sprint(wordID))
+				continue
+			}
+			return 0, fmt.Errorf("failed to validate word: %w", err)
+		}
+	}
+	if len(missing) > 0 {
+		return 0, fmt.Errorf("words not found: %s", strings.Join(missing, ", "))
+	}
+
+	linkStmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO word_groups (word_id, group_id) VALUES (?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare word_groups insert statement: %w", err)
+	}
+	defer linkStmt.Close()
+
+	added := 0
+	for _, wordID := range wordIDs {
+		result, err := linkStmt.ExecContext(ctx, wordID, groupID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add word to group: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to check added word: %w", err)
+		}
+		added += int(affected)
+	}
+
+	return added, nil
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"lang-portal/internal/models"
+)
+
+// KanjiRepository stores the kanji dictionary and which words use each kanji
+type KanjiRepository interface {
+	// Import replaces the dictionary with entries and returns how many were stored
+	Import(ctx context.Context, entries []models.Kanji) (int, error)
+
+	// Get retrieves a kanji with the words using it
+	Get(ctx context.Context, literal string) (*KanjiDetails, error)
+
+	// GetMany retrieves the dictionary entries of literals, leaving out kanji missing from it
+	GetMany(ctx context.Context, literals []string) (map[string]*models.Kanji, error)
+
+	// ListWordKanji retrieves the kanji of a word in the order of its parts
+	ListWordKanji(ctx context.Context, wordID int64) ([]WordKanji, error)
+
+	// LinkWords links every word to the kanji of its parts again and returns how many
+	// links there are
+	LinkWords(ctx context.Context) (int, error)
+}
+
+// KanjiWord is a word using a kanji; Position is the index of the part the kanji is in
+type KanjiWord struct {
+	ID       int64  `json:"id"`
+	Kanji    string `json:"kanji"`
+	Romaji   string `json:"romaji"`
+	English  string `json:"english"`
+	Position *int   `json:"position"`
+}
+
+// KanjiDetails is a kanji with the words of the inventory using it; the dictionary
+// fields are missing when the kanji is not in the imported dictionary
+type KanjiDetails struct {
+	Literal      string `json:"literal"`
+	InDictionary bool   `json:"in_dictionary"`
+	*models.Kanji
+	Words []KanjiWord `json:"words"`
+}
+
+// WordKanji is a kanji of a word; Position is the index of the part it is in
+type WordKanji struct {
+	Literal      string `json:"literal"`
+	Position     *int   `json:"position"`
+	InDictionary bool   `json:"in_dictionary"`
+	*models.Kanji
+}
+
+// SQLKanjiRepository implements KanjiRepository using SQLite
+type SQLKanjiRepository struct {
+	db *sql.DB
+}
+
+// NewKanjiRepository creates a new instance of SQLKanjiRepository
+func NewKanjiRepository(db *sql.DB) *SQLKanjiRepository {
+	return &SQLKanjiRepository{db: db}
+}
+
+// kanjiColumns lists the columns of the kanji table k scanned by scanKanji
+const kanjiColumns = `k.literal, k.meanings, k.on_readings, k.kun_readings, k.stroke_count, k.radicals, k.grade, k.jlpt, k.frequency`
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// scanKanji scans kanjiColumns followed by any extra destinations; it returns nil when
+// the columns are NULL, as for kanji missing from the dictionary in a LEFT JOIN
+func scanKanji(row rowScanner, extra ...interface{}) (*models.Kanji, error) {
+	var literal, meanings, onReadings, kunReadings, radicals sql.NullString
+	var strokeCount, grade, jlpt, frequency sql.NullInt64
+	dest := append([]interface{}{
+		&literal, &meanings, &onReadings, &kunReadings, &strokeCount, &radicals, &grade, &jlpt, &frequency,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if !literal.Valid {
+		return nil, nil
+	}
+
+	kanji := &models.Kanji{
+		Literal:     literal.String,
+		StrokeCount: int(strokeCount.Int64),
+		Grade:       nullIntPtr(grade),
+		JLPT:        nullIntPtr(jlpt),
+		Frequency:   nullIntPtr(frequency),
+	}
+	for _, field := range []struct {
+		data string
+		dest interface{}
+	}{
+		{meanings.String, &kanji.Meanings},
+		{onReadings.String, &kanji.OnReadings},
+		{kunReadings.String, &kanji.KunReadings},
+		{radicals.String, &kanji.Radicals},
+	} {
+		if err := json.Unmarshal([]byte(field.data), field.dest); err != nil {
+			return nil, fmt.Errorf("failed to parse kanji %s: %w", kanji.Literal, err)
+		}
+	}
+	return kanji, nil
+}
+
+// nullIntPtr returns a pointer to a nullable integer, or nil
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+// Import replaces the dictionary with entries in one transaction
+func (r *SQLKanjiRepository) Import(ctx context.Context, entries []models.Kanji) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM kanji`); err != nil {
+		return 0, fmt.Errorf("failed to clear kanji: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT OR REPLACE INTO kanji
+			(literal, meanings, on_readings, kun_readings, stroke_count, radicals, grade, jlpt, frequency)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare kanji insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, k := range entries {
+		columns := make([]interface{}, 0, 4)
+		for _, value := range []interface{}{k.Meanings, k.OnReadings, k.KunReadings, k.Radicals} {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return 0, fmt.Errorf("failed to encode kanji %s: %w", k.Literal, err)
+			}
+			columns = append(columns, string(data))
+		}
+		if _, err := stmt.ExecContext(ctx, k.Literal, columns[0], columns[1], columns[2], k.StrokeCount, columns[3],
+			k.Grade, k.JLPT, k.Frequency); err != nil {
+			return 0, fmt.Errorf("failed to import kanji %s: %w", k.Literal, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit kanji import: %w", err)
+	}
+	return len(entries), nil
+}
+
+// Get retrieves a kanji with the words using it
+func (r *SQLKanjiRepository) Get(ctx context.Context, literal string) (*KanjiDetails, error) {
+	kanji, err := scanKanji(r.db.QueryRowContext(ctx, `
+		SELECT `+kanjiColumns+` FROM kanji k WHERE k.literal = ?
+	`, literal))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch kanji: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT w.id, w.kanji, w.romaji, w.english, wk.position
+		FROM word_kanji wk
+		JOIN words w ON w.id = wk.word_id
+		WHERE wk.literal = ?
+		ORDER BY w.id
+	`, literal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch kanji words: %w", err)
+	}
+	defer rows.Close()
+
+	details := &KanjiDetails{Literal: literal, InDictionary: kanji != nil, Kanji: kanji, Words: []KanjiWord{}}
+	for rows.Next() {
+		var word KanjiWord
+		var position sql.NullInt64
+		if err := rows.Scan(&word.ID, &word.Kanji, &word.Romaji, &word.English, &position); err != nil {
+			return nil, fmt.Errorf("failed to scan kanji word: %w", err)
+		}
+		word.Position = nullIntPtr(position)
+		details.Words = append(details.Words, word)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch kanji words: %w", err)
+	}
+
+	if kanji == nil && len(details.Words) == 0 {
+		return nil, fmt.Errorf("kanji not found")
+	}
+	return details, nil
+}
+
+// GetMany retrieves the dictionary entries of literals
+func (r *SQLKanjiRepository) GetMany(ctx context.Context, literals []string) (map[string]*models.Kanji, error) {
+	kanji := map[string]*models.Kanji{}
+	if len(literals) == 0 {
+		return kanji, nil
+	}
+	args := make([]interface{}, len(literals))
+	for i, literal := range literals {
+		args[i] = literal
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+kanjiColumns+` FROM kanji k
+		WHERE k.literal IN (?`+strings.Repeat(", ?", len(literals)-1)+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch kanji: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanKanji(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan kanji: %w", err)
+		}
+		kanji[entry.Literal] = entry
+	}
+	return kanji, rows.Err()
+}
+
+// ListWordKanji retrieves the kanji of a word in the order of its parts
+func (r *SQLKanjiRepository) ListWordKanji(ctx context.Context, wordID int64) ([]WordKanji, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM words WHERE id = ?)`, wordID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to fetch word: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("word not found")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+kanjiColumns+`, wk.literal, wk.position
+		FROM word_kanji wk
+		LEFT JOIN kanji k ON k.literal = wk.literal
+		WHERE wk.word_id = ?
+		ORDER BY wk.position, wk.rowid
+	`, wordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch word kanji: %w", err)
+	}
+	defer rows.Close()
+
+	kanji := []WordKanji{}
+	for rows.Next() {
+		var item WordKanji
+		var position sql.NullInt64
+		entry, err := scanKanji(rows, &item.Literal, &position)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan word kanji: %w", err)
+		}
+		item.Position = nullIntPtr(position)
+		item.Kanji, item.InDictionary = entry, entry != nil
+		kanji = append(kanji, item)
+	}
+	return kanji, rows.Err()
+}
+
+// LinkWords links every word to the kanji of its parts again
+func (r *SQLKanjiRepository) LinkWords(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, kanji, parts FROM words`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch words: %w", err)
+	}
+	type wordRow struct {
+		id    int64
+		kanji string
+		parts []byte
+	}
+	var words []wordRow
+	for rows.Next() {
+		var w wordRow
+		var parts sql.NullString
+		if err := rows.Scan(&w.id, &w.kanji, &parts); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan word: %w", err)
+		}
+		w.parts = []byte(parts.String)
+		words = append(words, w)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to fetch words: %w", err)
+	}
+
+	for _, w := range words {
+		if err := linkWordKanji(ctx, tx, w.id, w.kanji, w.parts); err != nil {
+			return 0, err
+		}
+	}
+	var links int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM word_kanji`).Scan(&links); err != nil {
+		return 0, fmt.Errorf("failed to count word kanji: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit word kanji: %w", err)
+	}
+	return links, nil
+}
+
+// linkWordKanji replaces the kanji links of a word with the kanji found in its parts,
+// or in the word itself when its parts cannot be read
+func linkWordKanji(ctx context.Context, q execer, wordID int64, kanji string, parts []byte) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM word_kanji WHERE word_id = ?`, wordID); err != nil {
+		return fmt.Errorf("failed to unlink word kanji: %w", err)
+	}
+
+	link := func(text string, position interface{}) error {
+		for _, r := range text {
+			if !unicode.Is(unicode.Han, r) {
+				continue
+			}
+			if _, err := q.ExecContext(ctx, `
+				INSERT OR IGNORE INTO word_kanji (word_id, literal, position) VALUES (?, ?, ?)
+			`, wordID, string(r), position); err != nil {
+				return fmt.Errorf("failed to link word kanji: %w", err)
+			}
+		}
+		return nil
+	}
+
+	var wordParts []WordPart
+	if err := json.Unmarshal(parts, &wordParts); err != nil || len(wordParts) == 0 {
+		return link(kanji, nil)
+	}
+	for i, part := range wordParts {
+		if err := link(part.Kanji, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+// LearnerActivity is a review or a session start of a learner, replayed for leaderboards
+type LearnerActivity struct {
+	LearnerID int64
+	// WordID is zero for session starts
+	WordID    int64
+	Correct   bool
+	CreatedAt time.Time
+}
+
+// LeaderboardRepository provides the learner activity leaderboards rank and the learners' privacy settings
+type LeaderboardRepository interface {
+	// ListClasses retrieves the LTI courses and how many learners studied in each
+	ListClasses(ctx context.Context) ([]models.Class, error)
+
+	// GetClass retrieves the LTI course mapped to a group
+	GetClass(ctx context.Context, groupID int64) (*models.Class, error)
+
+	// GetLearnerActivity retrieves the reviews and session starts of every learner, oldest first,
+	// limited to the sessions of a class's group when classID is set
+	GetLearnerActivity(ctx context.Context, classID *int64) ([]LearnerActivity, error)
+
+	// ListPrivacy retrieves the privacy settings of every learner
+	ListPrivacy(ctx context.Context) ([]models.LearnerPrivacy, error)
+
+	// GetPrivacy retrieves the privacy settings of a learner
+	GetPrivacy(ctx context.Context, learnerID int64) (*models.LearnerPrivacy, error)
+
+	// UpdatePrivacy saves the display name and opt-out of a learner
+	UpdatePrivacy(ctx context.Context, privacy *models.LearnerPrivacy) error
+}
+
+// SQLLeaderboardRepository implements LeaderboardRepository using SQLite
+type SQLLeaderboardRepository struct {
+	db *sql.DB
+}
+
+// NewLeaderboardRepository creates a new instance of SQLLeaderboardRepository
+func NewLeaderboardRepository(db *sql.DB) *SQLLeaderboardRepository {
+	return &SQLLeaderboardRepository{db: db}
+}
+
+// classQuery selects LTI courses with their learner counts; %s filters the contexts lc
+const classQuery = `
+	SELECT
+		g.id, g.name, lc.platform_id, lc.context_id,
+		(SELECT COUNT(DISTINCT ss.learner_id) FROM study_sessions ss WHERE ss.group_id = g.id) AS learners
+	FROM lti_contexts lc
+	JOIN groups g ON g.id = lc.group_id
+	%s
+	ORDER BY g.id
+`
+
+// ListClasses retrieves the LTI courses and how many learners studied in each
+func (r *SQLLeaderboardRepository) ListClasses(ctx context.Context) ([]models.Class, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(classQuery, ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch classes: %w", err)
+	}
+	defer rows.Close()
+
+	classes := []models.Class{}
+	for rows.Next() {
+		var class models.Class
+		if err := rows.Scan(&class.ID, &class.Name, &class.PlatformID, &class.ContextID, &class.Learners); err != nil {
+			return nil, fmt.Errorf("failed to scan class: %w", err)
+		}
+		classes = append(classes, class)
+	}
+	return classes, rows.Err()
+}
+
+// GetClass retrieves the LTI course mapped to a group
+func (r *SQLLeaderboardRepository) GetClass(ctx context.Context, groupID int64) (*models.Class, error) {
+	var class models.Class
+	err := r.db.QueryRowContext(ctx, fmt.Sprintf(classQuery, "WHERE lc.group_id = ?"), groupID).Scan(
+		&class.ID, &class.Name, &class.PlatformID, &class.ContextID, &class.Learners,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("class not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch class: %w", err)
+	}
+	return &class, nil
+}
+
+// GetLearnerActivity retrieves the reviews and session starts of every learner, oldest first,
+// limited to the sessions of a class's group when classID is set
+func (r *SQLLeaderboardRepository) GetLearnerActivity(ctx context.Context, classID *int64) ([]LearnerActivity, error) {
+	scope := ""
+	var args []interface{}
+	if classID != nil {
+		scope = " AND ss.group_id = ?"
+		args = []interface{}{*classID, *classID}
+	}
+	query := `
+		SELECT learner_id, word_id, correct, created_at
+		FROM (
+			SELECT ss.learner_id, wri.word_id, wri.correct, wri.created_at, wri.id AS seq
+			FROM word_review_items wri
+			JOIN study_sessions ss ON ss.id = wri.study_session_id
+			WHERE ss.learner_id IS NOT NULL` + scope + `
+			UNION ALL
+			SELECT ss.learner_id, 0, 0, ss.created_at, 0
+			FROM study_sessions ss
+			WHERE ss.learner_id IS NOT NULL` + scope + `
+		)
+		ORDER BY julianday(created_at), seq
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch learner activity: %w", err)
+	}
+	defer rows.Close()
+
+	var activity []LearnerActivity
+	for rows.Next() {
+		var a LearnerActivity
+		var createdAt string
+		if err := rows.Scan(&a.LearnerID, &a.WordID, &a.Correct, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan learner activity: %w", err)
+		}
+		// The column type is lost in the union, so the timestamp comes back as text
+		if a.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan learner activity: %w", err)
+		}
+		activity = append(activity, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch learner activity: %w", err)
+	}
+	return activity, nil
+}
+
+// privacyColumns lists the columns scanned by scanPrivacy
+const privacyColumns = `id, name, display_name, leaderboard_opt_out`
+
+// scanPrivacy scans a row selected with privacyColumns
+func scanPrivacy(row rowScanner) (*models.LearnerPrivacy, error) {
+	var p models.LearnerPrivacy
+	if err := row.Scan(&p.LearnerID, &p.Name, &p.DisplayName, &p.LeaderboardOptOut); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListPrivacy retrieves the privacy settings of every learner
+func (r *SQLLeaderboardRepository) ListPrivacy(ctx context.Context) ([]models.LearnerPrivacy, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+privacyColumns+` FROM learners ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch learners: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []models.LearnerPrivacy
+	for rows.Next() {
+		p, err := scanPrivacy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan learner: %w", err)
+		}
+		settings = append(settings, *p)
+	}
+	return settings, rows.Err()
+}
+
+// GetPrivacy retrieves the privacy settings of a learner
+func (r *SQLLeaderboardRepository) GetPrivacy(ctx context.Context, learnerID int64) (*models.LearnerPrivacy, error) {
+	p, err := scanPrivacy(r.db.QueryRowContext(ctx, `SELECT `+privacyColumns+` FROM learners WHERE id = ?`, learnerID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("learner not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch learner: %w", err)
+	}
+	return p, nil
+}
+
+// UpdatePrivacy saves the display name and opt-out of a learner
+func (r *SQLLeaderboardRepository) UpdatePrivacy(ctx context.Context, privacy *models.LearnerPrivacy) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE learners SET display_name = ?, leaderboard_opt_out = ? WHERE id = ?
+	`, privacy.DisplayName, privacy.LeaderboardOptOut, privacy.LearnerID)
+	if err != nil {
+		return fmt.Errorf("failed to update learner privacy: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated learner: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("learner not found")
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"lang-portal/internal/models"
+)
+
+// LeechRepository finds words that keep getting failed
+type LeechRepository interface {
+	// ListLeeches retrieves words with at least the threshold of lapses, most failed lately first
+	ListLeeches(ctx context.Context, params models.LeechQueryParams) ([]models.ProblemWord, int, error)
+
+	// GetGroupProblemWords retrieves the words of a group with recent failures, most failed lately first
+	GetGroupProblemWords(ctx context.Context, groupID int64, params models.ProblemWordQueryParams) ([]models.ProblemWord, error)
+
+	// CountLapsesUpTo returns the number of wrong answers given for a word up to and
+	// including the review reviewID
+	CountLapsesUpTo(ctx context.Context, wordID, reviewID int64) (int, error)
+
+	// AddToNeedsWork links words to the "Needs work" group, creating it when missing,
+	// and returns the group with the number of words that were added
+	AddToNeedsWork(ctx context.Context, wordIDs []int64) (*GroupDetails, int, error)
+
+	// LeechIDs returns the ids of all words with at least threshold lapses
+	LeechIDs(ctx context.Context, threshold int) ([]int64, error)
+}
+
+// SQLLeechRepository implements LeechRepository using SQLite
+type SQLLeechRepository struct {
+	db *sql.DB
+}
+
+// NewLeechRepository creates a new instance of SQLLeechRepository
+func NewLeechRepository(db *sql.DB) *SQLLeechRepository {
+	return &SQLLeechRepository{db: db}
+}
+
+// problemWordsQuery selects reviewed words with their totals and the outcome of their
+// latest reviews; %s is an extra condition on the words, stats s and recent stats rs
+const problemWordsQuery = `
+	WITH stats AS (
+		SELECT
+			word_id,
+			COUNT(*) AS reviews,
+			SUM(CASE WHEN correct = 1 THEN 1 ELSE 0 END) AS correct_count,
+			SUM(CASE WHEN correct = 0 THEN 1 ELSE 0 END) AS lapses,
+			MAX(julianday(created_at)) AS last_reviewed
+		FROM word_review_items
+		GROUP BY word_id
+	), ranked AS (
+		SELECT
+			word_id,
+			correct,
+			ROW_NUMBER() OVER (PARTITION BY word_id ORDER BY julianday(created_at) DESC, id DESC) AS rn
+		FROM word_review_items
+	), recent AS (
+		SELECT
+			word_id,
+			COUNT(*) AS recent_reviews,
+			SUM(CASE WHEN correct = 0 THEN 1 ELSE 0 END) AS recent_lapses
+		FROM ranked
+		WHERE rn <= ?
+		GROUP BY word_id
+	)
+	SELECT
+		w.id, w.kanji, w.romaji, w.english,
+		s.reviews, s.correct_count, s.lapses,
+		rs.recent_reviews, rs.recent_lapses,
+		s.last_reviewed
+	FROM stats s
+	JOIN words w ON w.id = s.word_id
+	JOIN recent rs ON rs.word_id = s.word_id
+	WHERE %s
+`
+
+// problemWordsOrder ranks by recent failure rate, then total lapses
+const problemWordsOrder = `
+	ORDER BY 1.0 * rs.recent_lapses / rs.recent_reviews DESC, s.lapses DESC, w.id
+`
+
+// scanProblemWords reads rows of problemWordsQuery
+func scanProblemWords(rows *sql.Rows, threshold int) ([]models.ProblemWord, error) {
+	words := []models.ProblemWord{}
+	for rows.Next() {
+		var word models.ProblemWord
+		var recentLapses int
+		var lastReviewed float64
+		if err := rows.Scan(
+			&word.ID,
+			&word.Kanji,
+			&word.Romaji,
+			&word.English,
+			&word.Reviews,
+			&word.CorrectCount,
+			&word.Lapses,
+			&word.RecentReviews,
+			&recentLapses,
+			&lastReviewed,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan problem word: %w", err)
+		}
+		word.RecentFailureRate = int(math.Round(100 * float64(recentLapses) / float64(word.RecentReviews)))
+		word.LastReviewedAt = julianToTime(lastReviewed)
+		word.IsLeech = word.Lapses >= threshold
+		words = append(words, word)
+	}
+	return words, rows.Err()
+}
+
+// ListLeeches retrieves words with at least the threshold of lapses, most failed lately first
+func (r *SQLLeechRepository) ListLeeches(ctx context.Context, params models.LeechQueryParams) ([]models.ProblemWord, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT word_id FROM word_review_items
+			GROUP BY word_id
+			HAVING SUM(CASE WHEN correct = 0 THEN 1 ELSE 0 END) >= ?
+		)
+	`, params.Threshold).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count leeches: %w", err)
+	}
+
+	query := fmt.Sprintf(problemWordsQuery, "s.lapses >= ?") + problemWordsOrder + " LIMIT ? OFFSET ?"
+	offset := (params.Page - 1) * params.PerPage
+	rows, err := r.db.QueryContext(ctx, query, params.Recent, params.Threshold, params.PerPage, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch leeches: %w", err)
+	}
+	defer rows.Close()
+
+	words, err := scanProblemWords(rows, params.Threshold)
+	if err != nil {
+		return nil, 0, err
+	}
+	return words, total, nil
+}
+
+// GetGroupProblemWords retrieves the words of a group with recent failures, most failed lately first
+func (r *SQLLeechRepository) GetGroupProblemWords(ctx context.Context, groupID int64, params models.ProblemWordQueryParams) ([]models.ProblemWord, error) {
+	var exists int
+	if err := r.db.QueryRowContext(ctx, `SELECT 1 FROM groups WHERE id = ?`, groupID).Scan(&exists); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("group not found")
+		}
+		return nil, fmt.Errorf("failed to validate group: %w", err)
+	}
+
+	condition := `
+		w.id IN (SELECT word_id FROM word_groups WHERE group_id = ?)
+		AND s.reviews >= ?
+		AND rs.recent_lapses > 0
+	`
+	query := fmt.Sprintf(problemWordsQuery, condition) + problemWordsOrder + " LIMIT ?"
+	rows, err := r.db.QueryContext(ctx, query, params.Recent, groupID, params.MinReviews, params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch problem words: %w", err)
+	}
+	defer rows.Close()
+
+	return scanProblemWords(rows, params.Threshold)
+}
+
+// CountLapsesUpTo returns the number of wrong answers given for a word up to a review,
+// so that reviews committed together each see their own count
+func (r *SQLLeechRepository) CountLapsesUpTo(ctx context.Context, wordID, reviewID int64) (int, error) {
+	var lapses int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM word_review_items WHERE word_id = ? AND correct = 0 AND id <= ?
+	`, wordID, reviewID).Scan(&lapses)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count lapses: %w", err)
+	}
+	return lapses, nil
+}
+
+// LeechIDs returns the ids of all words with at least threshold lapses
+func (r *SQLLeechRepository) LeechIDs(ctx context.Context, threshold int) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT wri.word_id
+		FROM word_review_items wri
+		JOIN words w ON w.id = wri.word_id
+		GROUP BY wri.word_id
+		HAVING SUM(CASE WHEN wri.correct = 0 THEN 1 ELSE 0 END) >= ?
+		ORDER BY wri.word_id
+	`, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch leeches: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan leech: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// AddToNeedsWork links words to the "Needs work" group, creating it when missing,
+// and returns the group with the number of words that were added
+func (r *SQLLeechRepository) AddToNeedsWork(ctx context.Context, wordIDs []int64) (*GroupDetails, int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var groupID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM groups WHERE name = ? ORDER BY id LIMIT 1`, models.NeedsWorkGroupName).Scan(&groupID)
+	if err == sql.ErrNoRows {
+		result, err := tx.ExecContext(ctx, `INSERT INTO groups (name, words_count) VALUES (?, 0)`, models.NeedsWorkGroupName)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to create needs work group: %w", err)
+		}
+		if groupID, err = result.LastInsertId(); err != nil {
+			return nil, 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+	} else if err != nil {
+		return nil, 0, fmt.Errorf("failed to find needs work group: %w", err)
+	}
+
+	added, err := addWordsTx(ctx, tx, groupID, wordIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var group GroupDetails
+	err = tx.QueryRowContext(ctx, `SELECT id, name, words_count FROM groups WHERE id = ?`, groupID).Scan(&group.ID, &group.Name, &group.TotalWordCount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve needs work group: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("failed to commit needs work group: %w", err)
+	}
+
+	return &group, added, nil
+}
@@ -0,0 +1,460 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"lang-portal/internal/events"
+	"lang-portal/internal/models"
+)
+
+// LTIRepository defines the interface for LTI platform registrations and launch state
+type LTIRepository interface {
+	// ListPlatforms retrieves all registered platforms
+	ListPlatforms(ctx context.Context) ([]models.LTIPlatform, error)
+
+	// GetPlatform retrieves a platform by ID
+	GetPlatform(ctx context.Context, id int64) (*models.LTIPlatform, error)
+
+	// FindPlatform retrieves a platform by issuer; clientID may be empty when the issuer has a single registration
+	FindPlatform(ctx context.Context, issuer, clientID string) (*models.LTIPlatform, error)
+
+	// CreatePlatform registers a platform
+	CreatePlatform(ctx context.Context, platform *models.LTIPlatform) error
+
+	// DeletePlatform removes a platform and its launch state
+	DeletePlatform(ctx context.Context, id int64) error
+
+	// SaveState stores the state and nonce of an OIDC login
+	SaveState(ctx context.Context, state, nonce string, platformID int64, expiresAt time.Time) error
+
+	// ConsumeState removes an unexpired login state, returning its nonce and platform
+	ConsumeState(ctx context.Context, state string) (nonce string, platformID int64, err error)
+
+	// UseNonce records a launch nonce, failing when it has been used before
+	UseNonce(ctx context.Context, platformID int64, nonce string, expiresAt time.Time) error
+
+	// LinkLearner returns the learner of a platform user, creating it on first launch
+	LinkLearner(ctx context.Context, platformID int64, subject, name, email string) (int64, error)
+
+	// LinkContext returns the group of a platform course, creating an empty group on first launch
+	LinkContext(ctx context.Context, platformID int64, contextID, title string) (int64, error)
+
+	// CreateLaunchSession creates a study session along with the launch that started it, in
+	// one transaction, setting the IDs of both
+	CreateLaunchSession(ctx context.Context, session *models.StudySession, launch *models.LTILaunch) error
+
+	// GetLaunch retrieves the launch of a study session
+	GetLaunch(ctx context.Context, studySessionID int64) (*models.LTILaunch, error)
+
+	// SessionScore counts the correct and total reviews of a study session
+	SessionScore(ctx context.Context, studySessionID int64) (correct, total int, completed bool, err error)
+
+	// MarkScorePublished records when the score of a session was last sent to the platform
+	MarkScorePublished(ctx context.Context, studySessionID int64, at time.Time) error
+}
+
+// SQLLTIRepository implements LTIRepository using SQLite
+type SQLLTIRepository struct {
+	db        *sql.DB
+	publisher events.Publisher
+}
+
+// NewLTIRepository creates a new instance of SQLLTIRepository, publishing the sessions
+// launches create to publisher
+func NewLTIRepository(db *sql.DB, publisher events.Publisher) *SQLLTIRepository {
+	return &SQLLTIRepository{db: db, publisher: publisher}
+}
+
+// ltiPlatformColumns lists the columns scanned by scanLTIPlatform
+const ltiPlatformColumns = `id, name, issuer, client_id, deployment_id, auth_login_url,
+	auth_token_url, jwks_url, public_key_pem, created_at`
+
+// scanLTIPlatform scans a row selected with ltiPlatformColumns
+func scanLTIPlatform(row rowScanner) (*models.LTIPlatform, error) {
+	var p models.LTIPlatform
+	err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.Issuer,
+		&p.ClientID,
+		&p.DeploymentID,
+		&p.AuthLoginURL,
+		&p.AuthTokenURL,
+		&p.JWKSURL,
+		&p.PublicKeyPEM,
+		&p.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListPlatforms retrieves all registered platforms
+func (r *SQLLTIRepository) ListPlatforms(ctx context.Context) ([]models.LTIPlatform, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+ltiPlatformColumns+` FROM lti_platforms ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LTI platforms: %w", err)
+	}
+	defer rows.Close()
+
+	platforms := []models.LTIPlatform{}
+	for rows.Next() {
+		p, err := scanLTIPlatform(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan LTI platform: %w", err)
+		}
+		platforms = append(platforms, *p)
+	}
+	return platforms, rows.Err()
+}
+
+// GetPlatform retrieves a platform by ID
+func (r *SQLLTIRepository) GetPlatform(ctx context.Context, id int64) (*models.LTIPlatform, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+ltiPlatformColumns+` FROM lti_platforms WHERE id = ?`, id)
+	p, err := scanLTIPlatform(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("LTI platform not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LTI platform: %w", err)
+	}
+	return p, nil
+}
+
+// FindPlatform retrieves a platform by issuer; clientID may be empty when the issuer has a single registration
+func (r *SQLLTIRepository) FindPlatform(ctx context.Context, issuer, clientID string) (*models.LTIPlatform, error) {
+	query := `SELECT ` + ltiPlatformColumns + ` FROM lti_platforms WHERE issuer = ?`
+	args := []interface{}{issuer}
+	if clientID != "" {
+		query += ` AND client_id = ?`
+		args = append(args, clientID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query+` LIMIT 2`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LTI platform: %w", err)
+	}
+	defer rows.Close()
+
+	var found *models.LTIPlatform
+	for rows.Next() {
+		if found != nil {
+			return nil, fmt.Errorf("client_id is required for issuer %s", issuer)
+		}
+		if found, err = scanLTIPlatform(rows); err != nil {
+			return nil, fmt.Errorf("failed to scan LTI platform: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch LTI platform: %w", err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("LTI platform not found")
+	}
+	return found, nil
+}
+
+// CreatePlatform registers a platform
+func (r *SQLLTIRepository) CreatePlatform(ctx context.Context, platform *models.LTIPlatform) error {
+	query := `
+		INSERT INTO lti_platforms
+		(name, issuer, client_id, deployment_id, auth_login_url, auth_token_url, jwks_url, public_key_pem, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	platform.CreatedAt = time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, query,
+		platform.Name,
+		platform.Issuer,
+		platform.ClientID,
+		platform.DeploymentID,
+		platform.AuthLoginURL,
+		platform.AuthTokenURL,
+		platform.JWKSURL,
+		platform.PublicKeyPEM,
+		platform.CreatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("LTI platform already registered")
+		}
+		return fmt.Errorf("failed to create LTI platform: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	platform.ID = id
+	return nil
+}
+
+// DeletePlatform removes a platform and its launch state
+func (r *SQLLTIRepository) DeletePlatform(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM lti_platforms WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete LTI platform: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted LTI platform: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("LTI platform not found")
+	}
+	return nil
+}
+
+// SaveState stores the state and nonce of an OIDC login
+func (r *SQLLTIRepository) SaveState(ctx context.Context, state, nonce string, platformID int64, expiresAt time.Time) error {
+	// Drop abandoned logins while we are here
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM lti_states WHERE expires_at < ?`, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to purge LTI states: %w", err)
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO lti_states (state, nonce, platform_id, expires_at) VALUES (?, ?, ?, ?)`,
+		state, nonce, platformID, expiresAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save LTI state: %w", err)
+	}
+	return nil
+}
+
+// ConsumeState removes an unexpired login state, returning its nonce and platform
+func (r *SQLLTIRepository) ConsumeState(ctx context.Context, state string) (string, int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var nonce string
+	var platformID int64
+	var expiresAt time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT nonce, platform_id, expires_at FROM lti_states WHERE state = ?`, state,
+	).Scan(&nonce, &platformID, &expiresAt)
+	if err == sql.ErrNoRows {
+		return "", 0, fmt.Errorf("unknown LTI state")
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to fetch LTI state: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM lti_states WHERE state = ?`, state); err != nil {
+		return "", 0, fmt.Errorf("failed to consume LTI state: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", 0, fmt.Errorf("failed to commit LTI state: %w", err)
+	}
+
+	if time.Now().After(expiresAt) {
+		return "", 0, fmt.Errorf("expired LTI state")
+	}
+	return nonce, platformID, nil
+}
+
+// UseNonce records a launch nonce, failing when it has been used before
+func (r *SQLLTIRepository) UseNonce(ctx context.Context, platformID int64, nonce string, expiresAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM lti_nonces WHERE expires_at < ?`, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to purge LTI nonces: %w", err)
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO lti_nonces (platform_id, nonce, expires_at) VALUES (?, ?, ?)`,
+		platformID, nonce, expiresAt.UTC(),
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("nonce already used")
+		}
+		return fmt.Errorf("failed to record nonce: %w", err)
+	}
+	return nil
+}
+
+// LinkLearner returns the learner of a platform user, creating it on first launch
+func (r *SQLLTIRepository) LinkLearner(ctx context.Context, platformID int64, subject, name, email string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var learnerID int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT learner_id FROM lti_users WHERE platform_id = ? AND subject = ?`, platformID, subject,
+	).Scan(&learnerID)
+	switch {
+	case err == sql.ErrNoRows:
+		if name == "" {
+			name = subject
+		}
+		result, err := tx.ExecContext(ctx, `INSERT INTO learners (name, email) VALUES (?, ?)`, name, email)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create learner: %w", err)
+		}
+		if learnerID, err = result.LastInsertId(); err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO lti_users (platform_id, subject, learner_id) VALUES (?, ?, ?)`, platformID, subject, learnerID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to link LTI user: %w", err)
+		}
+	case err != nil:
+		return 0, fmt.Errorf("failed to fetch LTI user: %w", err)
+	case name != "" || email != "":
+		// Keep the learner in sync with the platform profile
+		_, err = tx.ExecContext(ctx, `
+			UPDATE learners SET
+				name = CASE WHEN ? != '' THEN ? ELSE name END,
+				email = CASE WHEN ? != '' THEN ? ELSE email END
+			WHERE id = ?`,
+			name, name, email, email, learnerID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update learner: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit learner: %w", err)
+	}
+	return learnerID, nil
+}
+
+// LinkContext returns the group of a platform course, creating an empty group on first launch
+func (r *SQLLTIRepository) LinkContext(ctx context.Context, platformID int64, contextID, title string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var groupID int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT group_id FROM lti_contexts WHERE platform_id = ? AND context_id = ?`, platformID, contextID,
+	).Scan(&groupID)
+	if err == nil {
+		return groupID, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to fetch LTI context: %w", err)
+	}
+
+	if title == "" {
+		title = contextID
+	}
+	result, err := tx.ExecContext(ctx, `INSERT INTO groups (name, words_count) VALUES (?, 0)`, title)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create group: %w", err)
+	}
+	if groupID, err = result.LastInsertId(); err != nil {
+		return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO lti_contexts (platform_id, context_id, group_id) VALUES (?, ?, ?)`, platformID, contextID, groupID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to link LTI context: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit LTI context: %w", err)
+	}
+	return groupID, nil
+}
+
+// CreateLaunchSession creates a study session along with the launch that started it, so
+// that no session of an LTI learner lacks the launch its score is published through
+func (r *SQLLTIRepository) CreateLaunchSession(ctx context.Context, session *models.StudySession, launch *models.LTILaunch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO study_sessions (group_id, study_activity_id, learner_id, created_at) VALUES (?, ?, ?, ?)`,
+		session.GroupID, session.StudyActivityID, session.LearnerID, session.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create study session: %w", err)
+	}
+	if session.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	launch.StudySessionID = session.ID
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO lti_launches (study_session_id, platform_id, subject, lineitem_url) VALUES (?, ?, ?, ?)`,
+		launch.StudySessionID, launch.PlatformID, launch.Subject, launch.LineItemURL,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save LTI launch: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit LTI launch: %w", err)
+	}
+	r.publisher.Publish(ctx, events.Event{Type: events.SessionCreated, Data: *session})
+	return nil
+}
+
+// GetLaunch retrieves the launch of a study session
+func (r *SQLLTIRepository) GetLaunch(ctx context.Context, studySessionID int64) (*models.LTILaunch, error) {
+	launch := &models.LTILaunch{StudySessionID: studySessionID}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT platform_id, subject, lineitem_url FROM lti_launches WHERE study_session_id = ?`, studySessionID,
+	).Scan(&launch.PlatformID, &launch.Subject, &launch.LineItemURL)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("study session was not launched through LTI")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LTI launch: %w", err)
+	}
+	return launch, nil
+}
+
+// SessionScore counts the correct and total reviews of a study session
+func (r *SQLLTIRepository) SessionScore(ctx context.Context, studySessionID int64) (int, int, bool, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END), 0),
+			COUNT(wri.id),
+			ss.completed_at IS NOT NULL
+		FROM study_sessions ss
+		LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
+		WHERE ss.id = ?
+		GROUP BY ss.id
+	`
+	var correct, total int
+	var completed bool
+	err := r.db.QueryRowContext(ctx, query, studySessionID).Scan(&correct, &total, &completed)
+	if err == sql.ErrNoRows {
+		return 0, 0, false, fmt.Errorf("study session not found")
+	}
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to compute session score: %w", err)
+	}
+	return correct, total, completed, nil
+}
+
+// MarkScorePublished records when the score of a session was last sent to the platform
+func (r *SQLLTIRepository) MarkScorePublished(ctx context.Context, studySessionID int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE lti_launches SET score_published_at = ? WHERE study_session_id = ?`, at.UTC(), studySessionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark score published: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"lang-portal/internal/models"
+)
+
+// MasteryRepository classifies words by how well they are known
+type MasteryRepository interface {
+	// GetGroupMastery retrieves the mastery of every word of a group
+	GetGroupMastery(ctx context.Context, groupID int64) (*models.GroupMastery, error)
+
+	// GetMasteryBreakdown counts mastery levels overall, per group and per study activity
+	GetMasteryBreakdown(ctx context.Context) (*models.MasteryBreakdown, error)
+}
+
+// SQLMasteryRepository implements MasteryRepository using SQLite
+type SQLMasteryRepository struct {
+	db *sql.DB
+}
+
+// NewMasteryRepository creates a new instance of SQLMasteryRepository
+func NewMasteryRepository(db *sql.DB) *SQLMasteryRepository {
+	return &SQLMasteryRepository{db: db}
+}
+
+// wordMasteryQuery computes the review count, the run of correct answers since the last
+// wrong one and the days that run spans for every word; %s filters the words w. Reviews
+// are ordered by time then id, as models.MasteryRun replays them, since quiz answers
+// scored together share their time.
+const wordMasteryQuery = `
+	WITH last_wrong AS (
+		SELECT word_id, at, id
+		FROM (
+			SELECT word_id, julianday(created_at) AS at, id,
+				ROW_NUMBER() OVER (PARTITION BY word_id ORDER BY julianday(created_at) DESC, id DESC) AS position
+			FROM word_review_items
+			WHERE correct = 0
+		)
+		WHERE position = 1
+	)
+	SELECT
+		w.id, w.kanji, w.romaji, w.english,
+		COUNT(wri.id) AS reviews,
+		COALESCE(SUM(CASE WHEN (julianday(wri.created_at), wri.id) > (COALESCE(lw.at, 0), COALESCE(lw.id, 0)) THEN 1 ELSE 0 END), 0) AS run,
+		COUNT(DISTINCT CASE WHEN (julianday(wri.created_at), wri.id) > (COALESCE(lw.at, 0), COALESCE(lw.id, 0)) THEN date(wri.created_at) END) AS run_days
+	FROM words w
+	LEFT JOIN word_review_items wri ON wri.word_id = w.id
+	LEFT JOIN last_wrong lw ON lw.word_id = w.id
+	%s
+	GROUP BY w.id, w.kanji, w.romaji, w.english
+	ORDER BY w.id
+`
+
+// wordMastery classifies the words matching where
+func (r *SQLMasteryRepository) wordMastery(ctx context.Context, where string, args ...interface{}) ([]models.WordMastery, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(wordMasteryQuery, where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute word mastery: %w", err)
+	}
+	defer rows.Close()
+
+	words := []models.WordMastery{}
+	for rows.Next() {
+		var word models.WordMastery
+		var runDays int
+		if err := rows.Scan(
+			&word.ID,
+			&word.Kanji,
+			&word.Romaji,
+			&word.English,
+			&word.Reviews,
+			&word.CorrectRun,
+			&runDays,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan word mastery: %w", err)
+		}
+		word.Level = models.ClassifyMastery(word.Reviews, word.CorrectRun, runDays)
+		words = append(words, word)
+	}
+	return words, rows.Err()
+}
+
+// GetGroupMastery retrieves the mastery of every word of a group
+func (r *SQLMasteryRepository) GetGroupMastery(ctx context.Context, groupID int64) (*models.GroupMastery, error) {
+	mastery := &models.GroupMastery{GroupID: groupID}
+	err := r.db.QueryRowContext(ctx, `SELECT name FROM groups WHERE id = ?`, groupID).Scan(&mastery.GroupName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("group not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve group: %w", err)
+	}
+
+	words, err := r.wordMastery(ctx, "WHERE w.id IN (SELECT word_id FROM word_groups WHERE group_id = ?)", groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, word := range words {
+		mastery.Add(word.Level)
+	}
+	mastery.Words = words
+
+	return mastery, nil
+}
+
+// GetMasteryBreakdown counts mastery levels overall, per group and per study activity
+func (r *SQLMasteryRepository) GetMasteryBreakdown(ctx context.Context) (*models.MasteryBreakdown, error) {
+	words, err := r.wordMastery(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := &models.MasteryBreakdown{
+		Groups:          []models.GroupMasterySummary{},
+		StudyActivities: []models.ActivityMasterySummary{},
+	}
+	levels := make(map[int64]string, len(words))
+	for _, word := range words {
+		levels[word.ID] = word.Level
+		breakdown.Overall.Add(word.Level)
+	}
+
+	// Groups count all their words, including the ones never reviewed
+	groupRows, err := r.db.QueryContext(ctx, `
+		SELECT g.id, g.name, wg.word_id
+		FROM groups g
+		LEFT JOIN word_groups wg ON wg.group_id = g.id
+		ORDER BY g.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve group words: %w", err)
+	}
+	defer groupRows.Close()
+
+	for groupRows.Next() {
+		var groupID int64
+		var groupName string
+		var wordID sql.NullInt64
+		if err := groupRows.Scan(&groupID, &groupName, &wordID); err != nil {
+			return nil, fmt.Errorf("failed to scan group word: %w", err)
+		}
+		n := len(breakdown.Groups)
+		if n == 0 || breakdown.Groups[n-1].GroupID != groupID {
+			breakdown.Groups = append(breakdown.Groups, models.GroupMasterySummary{GroupID: groupID, GroupName: groupName})
+			n++
+		}
+		if wordID.Valid {
+			breakdown.Groups[n-1].Add(levels[wordID.Int64])
+		}
+	}
+	if err := groupRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve group words: %w", err)
+	}
+
+	// Activities count the words practiced with them, by their overall mastery
+	activityRows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT sa.id, sa.name, wri.word_id
+		FROM study_activities sa
+		JOIN study_sessions ss ON ss.study_activity_id = sa.id
+		JOIN word_review_items wri ON wri.study_session_id = ss.id
+		ORDER BY sa.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve activity words: %w", err)
+	}
+	defer activityRows.Close()
+
+	for activityRows.Next() {
+		var activityID, wordID int64
+		var activityName string
+		if err := activityRows.Scan(&activityID, &activityName, &wordID); err != nil {
+			return nil, fmt.Errorf("failed to scan activity word: %w", err)
+		}
+		level, ok := levels[wordID]
+		if !ok {
+			continue
+		}
+		n := len(breakdown.StudyActivities)
+		if n == 0 || breakdown.StudyActivities[n-1].StudyActivityID != activityID {
+			breakdown.StudyActivities = append(breakdown.StudyActivities, models.ActivityMasterySummary{StudyActivityID: activityID, StudyActivityName: activityName})
+			n++
+		}
+		breakdown.StudyActivities[n-1].Add(level)
+	}
+	if err := activityRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve activity words: %w", err)
+	}
+
+	return breakdown, nil
+}
@@ -0,0 +1,465 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lang-portal/internal/models"
+)
+
+// MediaRepository stores the metadata of media files and what uses them. Saving and
+// deleting return the hashes of files left unused, so they can be removed from disk.
+type MediaRepository interface {
+	// GetMedia retrieves the metadata of a stored file
+	GetMedia(ctx context.Context, hash string) (*models.MediaFile, error)
+
+	// GetWordAudio retrieves the pronunciation of a word
+	GetWordAudio(ctx context.Context, wordID int64) (*models.WordAudio, error)
+
+	// SaveWordAudio records file as the pronunciation of a word, replacing its previous one
+	SaveWordAudio(ctx context.Context, file *models.MediaFile, audio *models.WordAudio) ([]string, error)
+
+	// DeleteWordAudio removes the pronunciation of a word
+	DeleteWordAudio(ctx context.Context, wordID int64) ([]string, error)
+
+	// GetWordImage retrieves the mnemonic image of a word
+	GetWordImage(ctx context.Context, wordID int64) (*models.WordImage, error)
+
+	// SaveWordImage records an image and its thumbnail, which may be nil, as the mnemonic
+	// image of a word, replacing its previous one
+	SaveWordImage(ctx context.Context, wordID int64, image, thumbnail *models.MediaFile) (*models.WordImage, []string, error)
+
+	// DeleteWordImage removes the mnemonic image of a word
+	DeleteWordImage(ctx context.Context, wordID int64) ([]string, error)
+
+	// GetStudyActivityImage retrieves the uploaded thumbnail of a study activity
+	GetStudyActivityImage(ctx context.Context, activityID int64) (*models.StudyActivityImage, error)
+
+	// SaveStudyActivityImage records an image and its thumbnail, which may be nil, as the
+	// thumbnail of a study activity and points the activity's preview_url at it
+	SaveStudyActivityImage(ctx context.Context, activityID int64, image, thumbnail *models.MediaFile) (*models.StudyActivityImage, []string, error)
+
+	// DeleteStudyActivityImage removes the uploaded thumbnail of a study activity, clearing
+	// its preview_url unless it was changed since
+	DeleteStudyActivityImage(ctx context.Context, activityID int64) ([]string, error)
+
+	// DeleteUnusedMedia removes the metadata of every file nothing uses, such as the
+	// media of deleted words
+	DeleteUnusedMedia(ctx context.Context) ([]string, error)
+}
+
+// SQLMediaRepository implements MediaRepository using SQLite
+type SQLMediaRepository struct {
+	db *sql.DB
+}
+
+// NewMediaRepository creates a new instance of SQLMediaRepository
+func NewMediaRepository(db *sql.DB) *SQLMediaRepository {
+	return &SQLMediaRepository{db: db}
+}
+
+// imageTable describes a table attaching one image to each row of another table
+type imageTable struct {
+	name  string
+	key   string
+	owner string
+}
+
+var (
+	wordImages          = imageTable{name: "word_images", key: "word_id", owner: "word"}
+	studyActivityImages = imageTable{name: "study_activity_images", key: "study_activity_id", owner: "study activity"}
+)
+
+// mediaInUse is true for the rows of media_files m that something still uses;
+// thumbnails are used by the images they belong to
+const mediaInUse = `(
+	EXISTS (SELECT 1 FROM word_audio WHERE word_audio.hash = m.hash)
+	OR EXISTS (SELECT 1 FROM word_images WHERE word_images.hash = m.hash)
+	OR EXISTS (SELECT 1 FROM study_activity_images WHERE study_activity_images.hash = m.hash)
+	OR EXISTS (SELECT 1 FROM media_files image WHERE image.thumbnail_hash = m.hash)
+)`
+
+// wordAudioHash selects the audio hash of the words aliased as table, or NULL
+func wordAudioHash(table string) string {
+	return "(SELECT hash FROM word_audio WHERE word_audio.word_id = " + table + ".id)"
+}
+
+// wordImageHashes selects the image hash and thumbnail hash of the words aliased as table,
+// or NULLs; the thumbnail of a small image is the image itself
+func wordImageHashes(table string) string {
+	return "(SELECT hash FROM word_images WHERE word_images.word_id = " + table + ".id), " +
+		"(SELECT COALESCE(m.thumbnail_hash, m.hash) FROM word_images JOIN media_files m ON m.hash = word_images.hash" +
+		" WHERE word_images.word_id = " + table + ".id)"
+}
+
+// mediaURL maps a hash selected with wordAudioHash or wordImageHashes to the URL it is served at
+func mediaURL(hash sql.NullString) string {
+	if !hash.Valid {
+		return ""
+	}
+	return models.MediaURL(hash.String)
+}
+
+// GetMedia retrieves the metadata of a stored file
+func (r *SQLMediaRepository) GetMedia(ctx context.Context, hash string) (*models.MediaFile, error) {
+	var file models.MediaFile
+	var width, height sql.NullInt64
+	var thumbnail sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT hash, content_type, size, created_at, width, height, thumbnail_hash
+		FROM media_files WHERE hash = ?
+	`, hash).Scan(&file.Hash, &file.ContentType, &file.Size, &file.CreatedAt, &width, &height, &thumbnail)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("media not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch media: %w", err)
+	}
+	file.Width, file.Height, file.ThumbnailHash = int(width.Int64), int(height.Int64), thumbnail.String
+	return &file, nil
+}
+
+// GetWordAudio retrieves the pronunciation of a word
+func (r *SQLMediaRepository) GetWordAudio(ctx context.Context, wordID int64) (*models.WordAudio, error) {
+	return getWordAudio(ctx, r.db, wordID)
+}
+
+// getWordAudio retrieves the pronunciation of a word with q, which may be a transaction
+func getWordAudio(ctx context.Context, q queryRower, wordID int64) (*models.WordAudio, error) {
+	var audio models.WordAudio
+	err := q.QueryRowContext(ctx, `
+		SELECT a.word_id, a.hash, a.source, a.voice, m.content_type, m.size, a.created_at
+		FROM word_audio a
+		JOIN media_files m ON m.hash = a.hash
+		WHERE a.word_id = ?
+	`, wordID).Scan(&audio.WordID, &audio.Hash, &audio.Source, &audio.Voice, &audio.ContentType, &audio.Size, &audio.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("audio not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch word audio: %w", err)
+	}
+	audio.URL = models.MediaURL(audio.Hash)
+	return &audio, nil
+}
+
+// SaveWordAudio records file as the pronunciation of a word, replacing its previous one
+func (r *SQLMediaRepository) SaveWordAudio(ctx context.Context, file *models.MediaFile, audio *models.WordAudio) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	previous, err := attachedHash(ctx, tx, "word_audio", "word_id", audio.WordID)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveMedia(ctx, tx, file); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO word_audio (word_id, hash, source, voice, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (word_id) DO UPDATE SET
+			hash = excluded.hash, source = excluded.source,
+			voice = excluded.voice, created_at = excluded.created_at
+	`, audio.WordID, file.Hash, audio.Source, audio.Voice, audio.CreatedAt); err != nil {
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+			return nil, fmt.Errorf("word not found")
+		}
+		return nil, fmt.Errorf("failed to save word audio: %w", err)
+	}
+
+	unused, err := deleteUnusedMedia(ctx, tx, previous)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := getWordAudio(ctx, tx, audio.WordID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	*audio = *saved
+	return unused, nil
+}
+
+// DeleteWordAudio removes the pronunciation of a word
+func (r *SQLMediaRepository) DeleteWordAudio(ctx context.Context, wordID int64) ([]string, error) {
+	return r.detach(ctx, "word_audio", "word_id", wordID, "audio not found", nil)
+}
+
+// GetWordImage retrieves the mnemonic image of a word
+func (r *SQLMediaRepository) GetWordImage(ctx context.Context, wordID int64) (*models.WordImage, error) {
+	image, err := getImage(ctx, r.db, wordImages, wordID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.WordImage{WordID: wordID, Image: *image}, nil
+}
+
+// SaveWordImage records an image as the mnemonic image of a word
+func (r *SQLMediaRepository) SaveWordImage(ctx context.Context, wordID int64, image, thumbnail *models.MediaFile) (*models.WordImage, []string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	saved, unused, err := saveImage(ctx, tx, wordImages, wordID, image, thumbnail)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &models.WordImage{WordID: wordID, Image: *saved}, unused, nil
+}
+
+// DeleteWordImage removes the mnemonic image of a word
+func (r *SQLMediaRepository) DeleteWordImage(ctx context.Context, wordID int64) ([]string, error) {
+	return r.detach(ctx, wordImages.name, wordImages.key, wordID, "image not found", nil)
+}
+
+// GetStudyActivityImage retrieves the uploaded thumbnail of a study activity
+func (r *SQLMediaRepository) GetStudyActivityImage(ctx context.Context, activityID int64) (*models.StudyActivityImage, error) {
+	image, err := getImage(ctx, r.db, studyActivityImages, activityID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.StudyActivityImage{StudyActivityID: activityID, Image: *image}, nil
+}
+
+// SaveStudyActivityImage records an image as the thumbnail of a study activity
+func (r *SQLMediaRepository) SaveStudyActivityImage(ctx context.Context, activityID int64, image, thumbnail *models.MediaFile) (*models.StudyActivityImage, []string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	saved, unused, err := saveImage(ctx, tx, studyActivityImages, activityID, image, thumbnail)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE study_activities SET preview_url = ? WHERE id = ?
+	`, saved.ThumbnailURL, activityID); err != nil {
+		return nil, nil, fmt.Errorf("failed to update study activity: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &models.StudyActivityImage{StudyActivityID: activityID, Image: *saved}, unused, nil
+}
+
+// DeleteStudyActivityImage removes the uploaded thumbnail of a study activity
+func (r *SQLMediaRepository) DeleteStudyActivityImage(ctx context.Context, activityID int64) ([]string, error) {
+	return r.detach(ctx, studyActivityImages.name, studyActivityImages.key, activityID, "image not found",
+		func(tx *sql.Tx) error {
+			// Only clear a preview_url that still shows the removed image
+			_, err := tx.ExecContext(ctx, `
+				UPDATE study_activities SET preview_url = ''
+				WHERE id = ? AND preview_url IN (
+					SELECT ? || hash FROM study_activity_images WHERE study_activity_id = ?
+					UNION
+					SELECT ? || m.thumbnail_hash FROM study_activity_images i
+					JOIN media_files m ON m.hash = i.hash
+					WHERE i.study_activity_id = ?
+				)
+			`, activityID, models.MediaPath, activityID, models.MediaPath, activityID)
+			if err != nil {
+				return fmt.Errorf("failed to update study activity: %w", err)
+			}
+			return nil
+		})
+}
+
+// DeleteUnusedMedia removes the metadata of every file nothing uses. Removing an image
+// frees its thumbnail, so this repeats until nothing is left to remove.
+func (r *SQLMediaRepository) DeleteUnusedMedia(ctx context.Context) ([]string, error) {
+	var hashes []string
+	for {
+		removed, err := r.deleteUnusedMediaOnce(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(removed) == 0 {
+			return hashes, nil
+		}
+		hashes = append(hashes, removed...)
+	}
+}
+
+// deleteUnusedMediaOnce removes the metadata of the files nothing uses right now
+func (r *SQLMediaRepository) deleteUnusedMediaOnce(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		DELETE FROM media_files AS m WHERE NOT `+mediaInUse+`
+		RETURNING hash
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete unused media: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+// detach removes the file attached to a row with the given key, running before first
+// while the attachment still exists, and deletes the file when nothing else uses it
+func (r *SQLMediaRepository) detach(ctx context.Context, table, key string, id int64, notFound string, before func(*sql.Tx) error) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	hash, err := attachedHash(ctx, tx, table, key, id)
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		return nil, errors.New(notFound)
+	}
+	if before != nil {
+		if err := before(tx); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE `+key+` = ?`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete from %s: %w", table, err)
+	}
+	unused, err := deleteUnusedMedia(ctx, tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return unused, nil
+}
+
+// attachedHash returns the hash of the file attached to a row, or an empty string
+func attachedHash(ctx context.Context, tx *sql.Tx, table, key string, id int64) (string, error) {
+	var hash string
+	err := tx.QueryRowContext(ctx, `SELECT hash FROM `+table+` WHERE `+key+` = ?`, id).Scan(&hash)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to fetch from %s: %w", table, err)
+	}
+	return hash, nil
+}
+
+// saveMedia records a file; identical files are stored once, so it may be known already
+func saveMedia(ctx context.Context, tx *sql.Tx, file *models.MediaFile) error {
+	var width, height sql.NullInt64
+	if file.Width > 0 {
+		width = sql.NullInt64{Int64: int64(file.Width), Valid: true}
+		height = sql.NullInt64{Int64: int64(file.Height), Valid: true}
+	}
+	var thumbnail sql.NullString
+	if file.ThumbnailHash != "" {
+		thumbnail = sql.NullString{String: file.ThumbnailHash, Valid: true}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO media_files (hash, content_type, size, created_at, width, height, thumbnail_hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, file.Hash, file.ContentType, file.Size, file.CreatedAt, width, height, thumbnail); err != nil {
+		return fmt.Errorf("failed to save media: %w", err)
+	}
+	return nil
+}
+
+// getImage retrieves the image attached to a row of table with q, which may be a transaction
+func getImage(ctx context.Context, q queryRower, table imageTable, id int64) (*models.Image, error) {
+	var image models.Image
+	var width, height sql.NullInt64
+	var thumbnail sql.NullString
+	err := q.QueryRowContext(ctx, `
+		SELECT i.hash, m.content_type, m.size, m.width, m.height, m.thumbnail_hash, i.created_at
+		FROM `+table.name+` i
+		JOIN media_files m ON m.hash = i.hash
+		WHERE i.`+table.key+` = ?
+	`, id).Scan(&image.Hash, &image.ContentType, &image.Size, &width, &height, &thumbnail, &image.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("image not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	image.Width, image.Height = int(width.Int64), int(height.Int64)
+	image.URL = models.MediaURL(image.Hash)
+	image.ThumbnailURL = image.URL
+	if thumbnail.Valid {
+		image.ThumbnailURL = models.MediaURL(thumbnail.String)
+	}
+	return &image, nil
+}
+
+// saveImage records an image and its thumbnail as attached to a row of table
+func saveImage(ctx context.Context, tx *sql.Tx, table imageTable, id int64, image, thumbnail *models.MediaFile) (*models.Image, []string, error) {
+	previous, err := attachedHash(ctx, tx, table.name, table.key, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if thumbnail != nil {
+		if err := saveMedia(ctx, tx, thumbnail); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := saveMedia(ctx, tx, image); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO `+table.name+` (`+table.key+`, hash, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (`+table.key+`) DO UPDATE SET hash = excluded.hash, created_at = excluded.created_at
+	`, id, image.Hash, image.CreatedAt); err != nil {
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+			return nil, nil, fmt.Errorf("%s not found", table.owner)
+		}
+		return nil, nil, fmt.Errorf("failed to save image: %w", err)
+	}
+
+	unused, err := deleteUnusedMedia(ctx, tx, previous)
+	if err != nil {
+		return nil, nil, err
+	}
+	saved, err := getImage(ctx, tx, table, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return saved, unused, nil
+}
+
+// deleteUnusedMedia removes the metadata of a file when nothing uses it anymore, then its
+// thumbnail likewise, and returns the hashes removed
+func deleteUnusedMedia(ctx context.Context, tx *sql.Tx, hash string) ([]string, error) {
+	var unused []string
+	for hash != "" {
+		var thumbnail sql.NullString
+		err := tx.QueryRowContext(ctx, `
+			DELETE FROM media_files AS m WHERE hash = ? AND NOT `+mediaInUse+`
+			RETURNING thumbnail_hash
+		`, hash).Scan(&thumbnail)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete media: %w", err)
+		}
+		unused = append(unused, hash)
+		hash = thumbnail.String
+	}
+	return unused, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"lang-portal/internal/events"
+	"lang-portal/internal/models"
+)
+
+// QuizRepository records the reviews of scored quizzes
+type QuizRepository interface {
+	// SaveScore records a quiz score and its reviews in a study session of groupID;
+	// a quiz can only be scored once
+	SaveScore(ctx context.Context, score *models.QuizScore, groupID int64, reviews []models.WordReviewItem) error
+}
+
+// SQLQuizRepository implements QuizRepository using SQLite
+type SQLQuizRepository struct {
+	db        *sql.DB
+	publisher events.Publisher
+}
+
+// NewQuizRepository creates a new instance of SQLQuizRepository
+// publishing review events to publisher
+func NewQuizRepository(db *sql.DB, publisher events.Publisher) *SQLQuizRepository {
+	return &SQLQuizRepository{db: db, publisher: publisher}
+}
+
+// SaveScore records a quiz score and its reviews in a study session of groupID;
+// a quiz can only be scored once
+func (r *SQLQuizRepository) SaveScore(ctx context.Context, score *models.QuizScore, groupID int64, reviews []models.WordReviewItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	session, err := getStudySession(ctx, tx, score.StudySessionID)
+	if err != nil {
+		return err
+	}
+	if session.GroupID != groupID {
+		return fmt.Errorf("study session is not for the quiz's group")
+	}
+	if session.CompletedAt != nil {
+		return fmt.Errorf("study session already completed")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO quiz_scores (quiz_id, study_session_id, correct, total, scored_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, score.QuizID, score.StudySessionID, score.Correct, score.Total, score.ScoredAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("quiz already scored")
+		}
+		return fmt.Errorf("failed to save quiz score: %w", err)
+	}
+
+	for i := range reviews {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO word_review_items (word_id, study_session_id, correct, created_at)
+			VALUES (?, ?, ?, ?)
+		`, reviews[i].WordID, reviews[i].StudySessionID, reviews[i].Correct, reviews[i].CreatedAt)
+		if err != nil {
+			// Words deleted since the quiz was generated fail the foreign key
+			if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+				return fmt.Errorf("word not found")
+			}
+			return fmt.Errorf("failed to create word review: %w", err)
+		}
+		if reviews[i].ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit quiz score: %w", err)
+	}
+
+	for _, review := range reviews {
+		r.publisher.Publish(ctx, events.Event{Type: events.ReviewCreated, Data: review})
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// orderByClause builds an ORDER BY clause from a whitelisted sort field.
+// columns maps API sort fields to SQL expressions; tiebreak keeps paging stable.
+func orderByClause(sortBy, order string, columns map[string]string, tiebreak string) (string, error) {
+	column, ok := columns[sortBy]
+	if !ok {
+		return "", fmt.Errorf("unsupported sort field: %s", sortBy)
+	}
+
+	direction := "ASC"
+	switch strings.ToLower(order) {
+	case "", "asc":
+	case "desc":
+		direction = "DESC"
+	default:
+		return "", fmt.Errorf("unsupported sort order: %s", order)
+	}
+
+	return fmt.Sprintf("ORDER BY %s %s, %s", column, direction, tiebreak), nil
+}
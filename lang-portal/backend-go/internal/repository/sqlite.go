@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// parseTimestamp parses a timestamp returned by SQLite as text, which happens for
+// aggregates such as MAX(created_at) where the column type is lost
+func parseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSuffix(value, "Z")
+	for _, format := range sqlite3.SQLiteTimestampFormats {
+		if t, err := time.ParseInLocation(format, value, time.UTC); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp: %s", value)
+}
+
+// nullTimestamp converts a nullable text timestamp into a time pointer
+func nullTimestamp(value sql.NullString) (*time.Time, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	t, err := parseTimestamp(value.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// queryRower is implemented by *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// quartersPerDay is the resolution activity is counted at for local days: every timezone
+// offset is a multiple of 15 minutes, so quarter hours map to exactly one local day anywhere
+const quartersPerDay = 96
+
+// julianToTime converts a julian day number to a UTC time, rounded to the second
+func julianToTime(julianDay float64) time.Time {
+	const unixEpochJulianDay = 2440587.5
+	seconds := math.Round((julianDay - unixEpochJulianDay) * 86400)
+	return time.Unix(int64(seconds), 0).UTC()
+}
+
+// sqliteTime formats t as UTC text that SQLite date functions accept
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.999999999")
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StreakRepository provides the study activity streaks are computed from
+type StreakRepository interface {
+	// GetActivityTimes returns the start of every quarter hour with a study session or review, oldest first
+	GetActivityTimes(ctx context.Context) ([]time.Time, error)
+}
+
+// StreakSource provides the current streak for dashboard statistics
+type StreakSource interface {
+	CurrentStreak(ctx context.Context) (int, error)
+}
+
+// SQLStreakRepository implements StreakRepository using SQLite
+type SQLStreakRepository struct {
+	db *sql.DB
+}
+
+// NewStreakRepository creates a new instance of SQLStreakRepository
+func NewStreakRepository(db *sql.DB) *SQLStreakRepository {
+	return &SQLStreakRepository{db: db}
+}
+
+// GetActivityTimes returns the start of every quarter hour with a study session or review, oldest first
+func (r *SQLStreakRepository) GetActivityTimes(ctx context.Context) ([]time.Time, error) {
+	query := `
+		SELECT DISTINCT CAST(julianday(created_at) * ? AS INTEGER) AS quarter
+		FROM (
+			SELECT created_at FROM study_sessions
+			UNION ALL
+			SELECT created_at FROM word_review_items
+		)
+		WHERE quarter IS NOT NULL
+		ORDER BY quarter
+	`
+	rows, err := r.db.QueryContext(ctx, query, quartersPerDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve study activity: %w", err)
+	}
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var quarter int64
+		if err := rows.Scan(&quarter); err != nil {
+			return nil, fmt.Errorf("failed to scan study activity: %w", err)
+		}
+		times = append(times, julianToTime(float64(quarter)/quartersPerDay))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve study activity: %w", err)
+	}
+	return times, nil
+}
@@ -3,25 +3,43 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"lang-portal/internal/models"
+	"time"
 )
 
 // StudyActivityRepository defines the interface for study activity-related database operations
 type StudyActivityRepository interface {
-	// List retrieves all study activities with optional pagination
-	List(ctx context.Context, page, pageSize int) ([]models.StudyActivity, int, error)
+	// List retrieves study activities with usage statistics and optional pagination
+	List(ctx context.Context, page, pageSize int, includeRetired bool) ([]StudyActivityListItem, int, error)
 
 	// GetByID retrieves a specific study activity by its ID
 	GetByID(ctx context.Context, id int64) (*models.StudyActivity, error)
 
 	// GetActivityDetails retrieves additional details for a study activity
 	GetActivityDetails(ctx context.Context, id int64) (*StudyActivityDetails, error)
+
+	// Create registers a new study activity
+	Create(ctx context.Context, activity *models.StudyActivity) error
+
+	// Update modifies an existing study activity
+	Update(ctx context.Context, activity *models.StudyActivity) error
+
+	// Retire hides a study activity from the launchpad while keeping its sessions
+	Retire(ctx context.Context, id int64) error
 }
 
 // StudyActivityDetails contains additional information about a study activity
 type StudyActivityDetails struct {
-	TotalSessions int `json:"total_sessions"`
+	TotalSessions int        `json:"total_sessions"`
+	LastUsed      *time.Time `json:"last_used"`
+}
+
+// StudyActivityListItem represents a study activity in the list view
+type StudyActivityListItem struct {
+	models.StudyActivity
+	StudyActivityDetails
 }
 
 // SQLStudyActivityRepository implements StudyActivityRepository using SQLite
@@ -34,10 +52,62 @@ func NewStudyActivityRepository(db *sql.DB) *SQLStudyActivityRepository {
 	return &SQLStudyActivityRepository{db: db}
 }
 
-// List retrieves study activities with pagination
-func (r *SQLStudyActivityRepository) List(ctx context.Context, page, pageSize int) ([]models.StudyActivity, int, error) {
+// studyActivityColumns is the column list scanned by scanStudyActivity
+const studyActivityColumns = `
+	sa.id,
+	sa.name,
+	sa.url,
+	sa.preview_url,
+	sa.description,
+	sa.supported_modes,
+	sa.required_word_fields,
+	sa.retired_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanStudyActivity scans studyActivityColumns followed by any extra destinations
+func scanStudyActivity(row rowScanner, activity *models.StudyActivity, extra ...interface{}) error {
+	var supportedModes, requiredWordFields string
+	var retiredAt sql.NullTime
+	dest := append([]interface{}{
+		&activity.ID,
+		&activity.Name,
+		&activity.URL,
+		&activity.ThumbnailURL,
+		&activity.Description,
+		&supportedModes,
+		&requiredWordFields,
+		&retiredAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(supportedModes), &activity.SupportedModes); err != nil {
+		return fmt.Errorf("failed to parse supported modes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(requiredWordFields), &activity.RequiredWordFields); err != nil {
+		return fmt.Errorf("failed to parse required word fields: %w", err)
+	}
+	if retiredAt.Valid {
+		activity.RetiredAt = &retiredAt.Time
+	}
+
+	return nil
+}
+
+// List retrieves study activities with usage statistics and pagination
+func (r *SQLStudyActivityRepository) List(ctx context.Context, page, pageSize int, includeRetired bool) ([]StudyActivityListItem, int, error) {
+	whereClause := "WHERE sa.retired_at IS NULL"
+	if includeRetired {
+		whereClause = ""
+	}
+
 	// Count total activities
-	countQuery := `SELECT COUNT(*) FROM study_activities`
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM study_activities sa %s`, whereClause)
 	var totalCount int
 	err := r.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
 	if err != nil {
@@ -47,33 +117,38 @@ func (r *SQLStudyActivityRepository) List(ctx context.Context, page, pageSize in
 	// Calculate pagination
 	offset := (page - 1) * pageSize
 
-	// Fetch activities
-	query := `
-		SELECT 
-			sa.id, 
-			sa.name, 
-			sa.url
+	// Fetch activities with their session usage
+	query := fmt.Sprintf(`
+		SELECT %s,
+			COUNT(ss.id) as total_sessions,
+			MAX(ss.created_at) as last_used
 		FROM study_activities sa
+		LEFT JOIN study_sessions ss ON ss.study_activity_id = sa.id
+		%s
+		GROUP BY sa.id
+		ORDER BY sa.id
 		LIMIT ? OFFSET ?
-	`
+	`, studyActivityColumns, whereClause)
 	rows, err := r.db.QueryContext(ctx, query, pageSize, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list study activities: %w", err)
 	}
 	defer rows.Close()
 
-	var activities []models.StudyActivity
+	var activities []StudyActivityListItem
 	for rows.Next() {
-		var activity models.StudyActivity
-		if err := rows.Scan(
-			&activity.ID,
-			&activity.Name,
-			&activity.URL,
-		); err != nil {
+		var item StudyActivityListItem
+		var lastUsed sql.NullString
+		if err := scanStudyActivity(rows, &item.StudyActivity, &item.TotalSessions, &lastUsed); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan study activity: %w", err)
 		}
 
-		activities = append(activities, activity)
+		item.LastUsed, err = nullTimestamp(lastUsed)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to parse last used time: %w", err)
+		}
+
+		activities = append(activities, item)
 	}
 
 	return activities, totalCount, nil
@@ -81,20 +156,13 @@ func (r *SQLStudyActivityRepository) List(ctx context.Context, page, pageSize in
 
 // GetByID retrieves a specific study activity by its ID
 func (r *SQLStudyActivityRepository) GetByID(ctx context.Context, id int64) (*models.StudyActivity, error) {
-	query := `
-		SELECT 
-			sa.id, 
-			sa.name, 
-			sa.url
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM study_activities sa
 		WHERE sa.id = ?
-	`
+	`, studyActivityColumns)
 	var activity models.StudyActivity
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&activity.ID,
-		&activity.Name,
-		&activity.URL,
-	)
+	err := scanStudyActivity(r.db.QueryRowContext(ctx, query, id), &activity)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("study activity not found")
@@ -109,15 +177,18 @@ func (r *SQLStudyActivityRepository) GetByID(ctx context.Context, id int64) (*mo
 func (r *SQLStudyActivityRepository) GetActivityDetails(ctx context.Context, id int64) (*StudyActivityDetails, error) {
 	query := `
 		SELECT 
-			COUNT(ss.id) as total_sessions
+			COUNT(ss.id) as total_sessions,
+			MAX(ss.created_at) as last_used
 		FROM study_activities sa
 		LEFT JOIN study_sessions ss ON ss.study_activity_id = sa.id
 		WHERE sa.id = ?
 		GROUP BY sa.id
 	`
 	var details StudyActivityDetails
+	var lastUsed sql.NullString
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&details.TotalSessions,
+		&lastUsed,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -126,5 +197,125 @@ func (r *SQLStudyActivityRepository) GetActivityDetails(ctx context.Context, id
 		return nil, fmt.Errorf("failed to get study activity details: %w", err)
 	}
 
+	details.LastUsed, err = nullTimestamp(lastUsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse last used time: %w", err)
+	}
+
 	return &details, nil
 }
+
+// Create registers a new study activity
+func (r *SQLStudyActivityRepository) Create(ctx context.Context, activity *models.StudyActivity) error {
+	supportedModes, requiredWordFields, err := marshalActivityLists(activity)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO study_activities 
+		(name, url, preview_url, description, supported_modes, required_word_fields) 
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		activity.Name,
+		activity.URL,
+		activity.ThumbnailURL,
+		activity.Description,
+		supportedModes,
+		requiredWordFields,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create study activity: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	activity.ID = id
+
+	return nil
+}
+
+// Update modifies an existing study activity
+func (r *SQLStudyActivityRepository) Update(ctx context.Context, activity *models.StudyActivity) error {
+	supportedModes, requiredWordFields, err := marshalActivityLists(activity)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE study_activities
+		SET name = ?, url = ?, preview_url = ?, description = ?, supported_modes = ?, required_word_fields = ?
+		WHERE id = ?
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		activity.Name,
+		activity.URL,
+		activity.ThumbnailURL,
+		activity.Description,
+		supportedModes,
+		requiredWordFields,
+		activity.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update study activity: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated study activity: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("study activity not found")
+	}
+
+	return nil
+}
+
+// Retire hides a study activity from the launchpad while keeping its sessions
+func (r *SQLStudyActivityRepository) Retire(ctx context.Context, id int64) error {
+	query := `
+		UPDATE study_activities
+		SET retired_at = COALESCE(retired_at, ?)
+		WHERE id = ?
+	`
+	result, err := r.db.ExecContext(ctx, query, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to retire study activity: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check retired study activity: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("study activity not found")
+	}
+
+	return nil
+}
+
+// marshalActivityLists encodes the JSON list columns of a study activity
+func marshalActivityLists(activity *models.StudyActivity) (string, string, error) {
+	supportedModes := activity.SupportedModes
+	if supportedModes == nil {
+		supportedModes = []string{}
+	}
+	requiredWordFields := activity.RequiredWordFields
+	if requiredWordFields == nil {
+		requiredWordFields = []string{}
+	}
+
+	modesJSON, err := json.Marshal(supportedModes)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal supported modes: %w", err)
+	}
+	fieldsJSON, err := json.Marshal(requiredWordFields)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal required word fields: %w", err)
+	}
+
+	return string(modesJSON), string(fieldsJSON), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"lang-portal/internal/events"
 	"lang-portal/internal/models"
 	"strings"
 	"time"
@@ -21,12 +22,15 @@ type StudySessionListItem struct {
 
 // StudySessionRepository defines the interface for study session-related database operations
 type StudySessionRepository interface {
-	// List retrieves study sessions with optional filtering and pagination
-	List(ctx context.Context, studyActivityID, groupID int64, page, pageSize int) ([]StudySessionListItem, int, error)
+	// List retrieves study sessions with optional filtering, sorting and pagination
+	List(ctx context.Context, params models.StudySessionQueryParams) ([]StudySessionListItem, int, error)
 
 	// Create adds a new study session
 	Create(ctx context.Context, session *models.StudySession) error
 
+	// Complete marks a study session as finished
+	Complete(ctx context.Context, sessionID int64) (*models.StudySession, error)
+
 	// CreateWordReview adds a new word review item to a study session
 	CreateWordReview(ctx context.Context, review *models.WordReviewItem) error
 
@@ -40,30 +44,55 @@ type StudySessionRepository interface {
 	GetStudySessionDetails(ctx context.Context, sessionID int64) (*StudySessionDetails, error)
 }
 
+// studySessionSortColumns maps study session sort fields to SQL expressions
+var studySessionSortColumns = map[string]string{
+	"start_time":           "ss.created_at",
+	"activity_name":        "sa.name",
+	"group_name":           "g.name",
+	"total_words_reviewed": "total_words_reviewed",
+}
+
 // SQLStudySessionRepository implements StudySessionRepository using SQLite
 type SQLStudySessionRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	publisher events.Publisher
 }
 
 // NewStudySessionRepository creates a new instance of SQLStudySessionRepository
-func NewStudySessionRepository(db *sql.DB) *SQLStudySessionRepository {
-	return &SQLStudySessionRepository{db: db}
+// publishing session and review events to publisher
+func NewStudySessionRepository(db *sql.DB, publisher events.Publisher) *SQLStudySessionRepository {
+	return &SQLStudySessionRepository{db: db, publisher: publisher}
 }
 
-// List retrieves study sessions with optional filtering and pagination
-func (r *SQLStudySessionRepository) List(ctx context.Context, studyActivityID, groupID int64, page, pageSize int) ([]StudySessionListItem, int, error) {
+// List retrieves study sessions with optional filtering, sorting and pagination
+func (r *SQLStudySessionRepository) List(ctx context.Context, params models.StudySessionQueryParams) ([]StudySessionListItem, int, error) {
+	orderBy, err := orderByClause(params.SortBy, params.Order, studySessionSortColumns, "ss.id DESC")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Prepare filter conditions
 	conditions := []string{}
 	args := []interface{}{}
 
-	if studyActivityID > 0 {
+	if params.StudyActivityID > 0 {
 		conditions = append(conditions, "ss.study_activity_id = ?")
-		args = append(args, studyActivityID)
+		args = append(args, params.StudyActivityID)
 	}
 
-	if groupID > 0 {
+	if params.GroupID > 0 {
 		conditions = append(conditions, "ss.group_id = ?")
-		args = append(args, groupID)
+		args = append(args, params.GroupID)
+	}
+
+	if !params.StartedFrom.IsZero() {
+		conditions = append(conditions, "julianday(ss.created_at) >= julianday(?)")
+		args = append(args, sqliteTime(params.StartedFrom))
+	}
+
+	if !params.StartedBefore.IsZero() {
+		conditions = append(conditions, "julianday(ss.created_at) < julianday(?)")
+		args = append(args, sqliteTime(params.StartedBefore))
 	}
 
 	// Construct where clause
@@ -75,13 +104,13 @@ func (r *SQLStudySessionRepository) List(ctx context.Context, studyActivityID, g
 	// Count total sessions
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM study_sessions ss %s", whereClause)
 	var totalSessions int
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalSessions)
+	err = r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalSessions)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count study sessions: %w", err)
 	}
 
 	// Calculate pagination
-	offset := (page - 1) * pageSize
+	offset := (params.Page - 1) * params.PerPage
 
 	// Query to fetch detailed session information
 	query := fmt.Sprintf(`
@@ -96,12 +125,12 @@ func (r *SQLStudySessionRepository) List(ctx context.Context, studyActivityID, g
 		JOIN study_activities sa ON ss.study_activity_id = sa.id
 		JOIN groups g ON ss.group_id = g.id
 		%s
-		ORDER BY ss.created_at DESC
+		%s
 		LIMIT ? OFFSET ?
-	`, whereClause)
+	`, whereClause, orderBy)
 	
 	// Add pagination args
-	args = append(args, pageSize, offset)
+	args = append(args, params.PerPage, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -132,12 +161,13 @@ func (r *SQLStudySessionRepository) List(ctx context.Context, studyActivityID, g
 func (r *SQLStudySessionRepository) Create(ctx context.Context, session *models.StudySession) error {
 	query := `
 		INSERT INTO study_sessions 
-		(group_id, study_activity_id, created_at) 
-		VALUES (?, ?, ?)
+		(group_id, study_activity_id, learner_id, created_at) 
+		VALUES (?, ?, ?, ?)
 	`
 	result, err := r.db.ExecContext(ctx, query,
 		session.GroupID,
 		session.StudyActivityID,
+		session.LearnerID,
 		session.CreatedAt,
 	)
 	if err != nil {
@@ -151,9 +181,68 @@ func (r *SQLStudySessionRepository) Create(ctx context.Context, session *models.
 	}
 	session.ID = id
 
+	r.publisher.Publish(ctx, events.Event{Type: events.SessionCreated, Data: *session})
 	return nil
 }
 
+// Complete marks a study session as finished
+func (r *SQLStudySessionRepository) Complete(ctx context.Context, sessionID int64) (*models.StudySession, error) {
+	query := `UPDATE study_sessions SET completed_at = ? WHERE id = ? AND completed_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, time.Now().UTC(), sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete study session: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check completed study session: %w", err)
+	}
+
+	session, err := getStudySession(ctx, r.db, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("study session already completed")
+	}
+
+	r.publisher.Publish(ctx, events.Event{Type: events.SessionCompleted, Data: *session})
+	return session, nil
+}
+
+// getStudySession loads a study session with its learner and completion time
+func getStudySession(ctx context.Context, q queryRower, sessionID int64) (*models.StudySession, error) {
+	query := `
+		SELECT id, group_id, study_activity_id, learner_id, created_at, completed_at
+		FROM study_sessions
+		WHERE id = ?
+	`
+	var session models.StudySession
+	var learnerID sql.NullInt64
+	var completedAt sql.NullTime
+	err := q.QueryRowContext(ctx, query, sessionID).Scan(
+		&session.ID,
+		&session.GroupID,
+		&session.StudyActivityID,
+		&learnerID,
+		&session.CreatedAt,
+		&completedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("study session not found")
+		}
+		return nil, fmt.Errorf("failed to fetch study session: %w", err)
+	}
+
+	if learnerID.Valid {
+		session.LearnerID = &learnerID.Int64
+	}
+	if completedAt.Valid {
+		session.CompletedAt = &completedAt.Time
+	}
+	return &session, nil
+}
+
 // CreateWordReview adds a new word review item to a study session
 func (r *SQLStudySessionRepository) CreateWordReview(ctx context.Context, review *models.WordReviewItem) error {
 	// Validate that the study session exists
@@ -200,6 +289,7 @@ func (r *SQLStudySessionRepository) CreateWordReview(ctx context.Context, review
 	}
 	review.ID = id
 
+	r.publisher.Publish(ctx, events.Event{Type: events.ReviewCreated, Data: *review})
 	return nil
 }
 
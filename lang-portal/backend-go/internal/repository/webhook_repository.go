@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+// PendingDelivery is a due delivery with what is needed to send it
+type PendingDelivery struct {
+	models.WebhookDelivery
+	URL     string
+	Secret  string
+	Payload []byte
+}
+
+// DeliveryAttempt is the outcome of sending a delivery
+type DeliveryAttempt struct {
+	DeliveryID     int64
+	Status         string
+	AttemptedAt    time.Time
+	ResponseStatus *int
+	Error          string
+	// NextAttemptAt is set when the delivery stays pending
+	NextAttemptAt *time.Time
+}
+
+// WebhookRepository defines the interface for webhook subscriptions and their delivery queue
+type WebhookRepository interface {
+	// ListSubscriptions retrieves all subscriptions
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+
+	// GetSubscription retrieves a subscription by ID
+	GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+
+	// CreateSubscription adds a subscription
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+
+	// UpdateSubscription saves the URL, secret, event types and active flag of a subscription
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+
+	// DeleteSubscription removes a subscription and its deliveries
+	DeleteSubscription(ctx context.Context, id int64) error
+
+	// EnqueueEvent queues a delivery of an event for every active subscription to its type
+	EnqueueEvent(ctx context.Context, eventID, eventType string, payload []byte) (int, error)
+
+	// DueDeliveries retrieves pending deliveries whose next attempt is due
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]PendingDelivery, error)
+
+	// RecordAttempt stores the outcome of sending a delivery
+	RecordAttempt(ctx context.Context, attempt DeliveryAttempt) error
+
+	// ListDeliveries retrieves the delivery log of a subscription, newest first, optionally by status
+	ListDeliveries(ctx context.Context, subscriptionID int64, status string, page, perPage int) ([]models.WebhookDelivery, int, error)
+
+	// RetryDelivery queues a delivery again with a fresh attempt budget
+	RetryDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+}
+
+// SQLWebhookRepository implements WebhookRepository using SQLite
+type SQLWebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new instance of SQLWebhookRepository
+func NewWebhookRepository(db *sql.DB) *SQLWebhookRepository {
+	return &SQLWebhookRepository{db: db}
+}
+
+// webhookSubscriptionColumns lists the columns scanned by scanWebhookSubscription
+const webhookSubscriptionColumns = `id, url, secret, event_types, active, created_at, updated_at`
+
+// scanWebhookSubscription scans a row selected with webhookSubscriptionColumns
+func scanWebhookSubscription(row rowScanner) (*models.WebhookSubscription, error) {
+	var s models.WebhookSubscription
+	var eventTypes string
+	if err := row.Scan(&s.ID, &s.URL, &s.Secret, &eventTypes, &s.Active, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(eventTypes), &s.EventTypes); err != nil {
+		return nil, fmt.Errorf("invalid event types of subscription %d: %w", s.ID, err)
+	}
+	return &s, nil
+}
+
+// ListSubscriptions retrieves all subscriptions
+func (r *SQLWebhookRepository) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		s, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, *s)
+	}
+	return subscriptions, rows.Err()
+}
+
+// GetSubscription retrieves a subscription by ID
+func (r *SQLWebhookRepository) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE id = ?`, id)
+	s, err := scanWebhookSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook subscription not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook subscription: %w", err)
+	}
+	return s, nil
+}
+
+// CreateSubscription adds a subscription
+func (r *SQLWebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return fmt.Errorf("failed to encode event types: %w", err)
+	}
+
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, subscription.URL, subscription.Secret, string(eventTypes), subscription.Active, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	subscription.ID = id
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+	return nil
+}
+
+// UpdateSubscription saves the URL, secret, event types and active flag of a subscription
+func (r *SQLWebhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return fmt.Errorf("failed to encode event types: %w", err)
+	}
+
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = ?, secret = ?, event_types = ?, active = ?, updated_at = ?
+		WHERE id = ?
+	`, subscription.URL, subscription.Secret, string(eventTypes), subscription.Active, now, subscription.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated webhook subscription: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("webhook subscription not found")
+	}
+	subscription.UpdatedAt = now
+	return nil
+}
+
+// DeleteSubscription removes a subscription and its deliveries
+func (r *SQLWebhookRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted webhook subscription: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("webhook subscription not found")
+	}
+	return nil
+}
+
+// EnqueueEvent queues a delivery of an event for every active subscription to its type
+func (r *SQLWebhookRepository) EnqueueEvent(ctx context.Context, eventID, eventType string, payload []byte) (int, error) {
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		SELECT ws.id, ?, ?, ?, ?, ?, ?
+		FROM webhook_subscriptions ws
+		WHERE ws.active = 1
+			AND EXISTS (SELECT 1 FROM json_each(ws.event_types) WHERE value = ?)
+	`, eventID, eventType, string(payload), models.DeliveryPending, now, now, eventType)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+	return int(affected), nil
+}
+
+// webhookDeliveryColumns lists the columns scanned by scanWebhookDelivery
+const webhookDeliveryColumns = `wd.id, wd.subscription_id, wd.event_id, wd.event_type, wd.status, wd.attempts,
+	wd.next_attempt_at, wd.last_attempt_at, wd.response_status, wd.last_error, wd.created_at, wd.delivered_at`
+
+// scanWebhookDelivery scans a row selected with webhookDeliveryColumns, followed by extra columns
+func scanWebhookDelivery(row rowScanner, d *models.WebhookDelivery, extra ...interface{}) error {
+	var nextAttemptAt, lastAttemptAt, deliveredAt sql.NullTime
+	var responseStatus sql.NullInt64
+	dest := append([]interface{}{
+		&d.ID,
+		&d.SubscriptionID,
+		&d.EventID,
+		&d.EventType,
+		&d.Status,
+		&d.Attempts,
+		&nextAttemptAt,
+		&lastAttemptAt,
+		&responseStatus,
+		&d.LastError,
+		&d.CreatedAt,
+		&deliveredAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+
+	if nextAttemptAt.Valid {
+		d.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if lastAttemptAt.Valid {
+		d.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		d.ResponseStatus = &status
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return nil
+}
+
+// DueDeliveries retrieves pending deliveries whose next attempt is due
+func (r *SQLWebhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]PendingDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `, ws.url, ws.secret, wd.payload
+		FROM webhook_deliveries wd
+		JOIN webhook_subscriptions ws ON ws.id = wd.subscription_id
+		WHERE wd.status = ? AND wd.next_attempt_at <= ?
+		ORDER BY wd.next_attempt_at, wd.id
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, models.DeliveryPending, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []PendingDelivery
+	for rows.Next() {
+		var d PendingDelivery
+		var payload string
+		if err := scanWebhookDelivery(rows, &d.WebhookDelivery, &d.URL, &d.Secret, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt stores the outcome of sending a delivery
+func (r *SQLWebhookRepository) RecordAttempt(ctx context.Context, attempt DeliveryAttempt) error {
+	var deliveredAt, nextAttemptAt interface{}
+	if attempt.Status == models.DeliveryDelivered {
+		deliveredAt = attempt.AttemptedAt.UTC()
+	}
+	if attempt.NextAttemptAt != nil {
+		nextAttemptAt = attempt.NextAttemptAt.UTC()
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = attempts + 1, last_attempt_at = ?, response_status = ?,
+			last_error = ?, next_attempt_at = ?, delivered_at = ?
+		WHERE id = ?
+	`, attempt.Status, attempt.AttemptedAt.UTC(), attempt.ResponseStatus, attempt.Error, nextAttemptAt, deliveredAt, attempt.DeliveryID)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries retrieves the delivery log of a subscription, newest first, optionally by status
+func (r *SQLWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID int64, status string, page, perPage int) ([]models.WebhookDelivery, int, error) {
+	where := `WHERE wd.subscription_id = ?`
+	args := []interface{}{subscriptionID}
+	if status != "" {
+		where += ` AND wd.status = ?`
+		args = append(args, status)
+	}
+
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries wd `+where, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	offset := (page - 1) * perPage
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries wd ` + where + ` ORDER BY wd.id DESC LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, append(args, perPage, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, totalCount, rows.Err()
+}
+
+// RetryDelivery queues a delivery again with a fresh attempt budget
+func (r *SQLWebhookRepository) RetryDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = 0, next_attempt_at = ?, delivered_at = NULL
+		WHERE id = ?
+	`, models.DeliveryPending, time.Now().UTC(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry webhook delivery: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check retried webhook delivery: %w", err)
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("webhook delivery not found")
+	}
+
+	var d models.WebhookDelivery
+	row := r.db.QueryRowContext(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries wd WHERE wd.id = ?`, id)
+	if err := scanWebhookDelivery(row, &d); err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook delivery: %w", err)
+	}
+	return &d, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"lang-portal/internal/models"
+)
+
+// WordContentRepository stores the example sentences, mnemonics and explanations of words
+type WordContentRepository interface {
+	// List retrieves every kind of content stored for a word
+	List(ctx context.Context, wordID int64) ([]models.WordContent, error)
+
+	// Get retrieves one kind of content of a word
+	Get(ctx context.Context, wordID int64, kind string) (*models.WordContent, error)
+
+	// Save stores content, replacing the word's content of the same kind; content written by
+	// a teacher is only replaced when overwrite is set
+	Save(ctx context.Context, content *models.WordContent, overwrite bool) error
+
+	// Delete removes one kind of content of a word
+	Delete(ctx context.Context, wordID int64, kind string) error
+}
+
+// SQLWordContentRepository implements WordContentRepository using SQLite
+type SQLWordContentRepository struct {
+	db *sql.DB
+}
+
+// NewWordContentRepository creates a new instance of SQLWordContentRepository
+func NewWordContentRepository(db *sql.DB) *SQLWordContentRepository {
+	return &SQLWordContentRepository{db: db}
+}
+
+// wordContentColumns lists the columns scanned by scanWordContent
+const wordContentColumns = `word_id, kind, content, source, model, created_at, updated_at`
+
+// scanWordContent scans a row selected with wordContentColumns
+func scanWordContent(row rowScanner) (*models.WordContent, error) {
+	var c models.WordContent
+	if err := row.Scan(&c.WordID, &c.Kind, &c.Content, &c.Source, &c.Model, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// List retrieves every kind of content stored for a word
+func (r *SQLWordContentRepository) List(ctx context.Context, wordID int64) ([]models.WordContent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+wordContentColumns+` FROM word_contents WHERE word_id = ? ORDER BY kind
+	`, wordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch word content: %w", err)
+	}
+	defer rows.Close()
+
+	contents := []models.WordContent{}
+	for rows.Next() {
+		c, err := scanWordContent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan word content: %w", err)
+		}
+		contents = append(contents, *c)
+	}
+	return contents, rows.Err()
+}
+
+// Get retrieves one kind of content of a word
+func (r *SQLWordContentRepository) Get(ctx context.Context, wordID int64, kind string) (*models.WordContent, error) {
+	c, err := scanWordContent(r.db.QueryRowContext(ctx, `
+		SELECT `+wordContentColumns+` FROM word_contents WHERE word_id = ? AND kind = ?
+	`, wordID, kind))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("content not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch word content: %w", err)
+	}
+	return c, nil
+}
+
+// Save stores content, replacing the word's content of the same kind. The teacher check is
+// part of the upsert, so a teacher edit made while content was being generated is kept.
+func (r *SQLWordContentRepository) Save(ctx context.Context, content *models.WordContent, overwrite bool) error {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO word_contents (word_id, kind, content, source, model, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (word_id, kind) DO UPDATE SET
+			content = excluded.content,
+			source = excluded.source,
+			model = excluded.model,
+			updated_at = excluded.updated_at
+		WHERE ? OR word_contents.source <> ?
+	`, content.WordID, content.Kind, content.Content, content.Source, content.Model, content.CreatedAt, content.UpdatedAt,
+		overwrite, models.ContentTeacher)
+	if err != nil {
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+			return fmt.Errorf("word not found")
+		}
+		return fmt.Errorf("failed to save word content: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check saved word content: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("content was written by a teacher")
+	}
+
+	// An update keeps the original creation time
+	saved, err := r.Get(ctx, content.WordID, content.Kind)
+	if err != nil {
+		return err
+	}
+	*content = *saved
+	return nil
+}
+
+// Delete removes one kind of content of a word
+func (r *SQLWordContentRepository) Delete(ctx context.Context, wordID int64, kind string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM word_contents WHERE word_id = ? AND kind = ?`, wordID, kind)
+	if err != nil {
+		return fmt.Errorf("failed to delete word content: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted word content: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("content not found")
+	}
+	return nil
+}
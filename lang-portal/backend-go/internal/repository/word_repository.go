@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"lang-portal/internal/events"
 	"lang-portal/internal/models"
 	"strings"
 )
@@ -16,6 +17,9 @@ type WordRepository interface {
 	// GetByID retrieves a word by its ID
 	GetByID(ctx context.Context, id int64) (*models.Word, error)
 
+	// FindID retrieves the ID of the word with exactly this kanji, romaji and meaning
+	FindID(ctx context.Context, kanji, romaji, english string) (int64, error)
+
 	// Update modifies an existing word
 	Update(ctx context.Context, word *models.Word) error
 
@@ -42,21 +46,29 @@ type WordFilter struct {
 
 // SQLWordRepository implements WordRepository using SQLite
 type SQLWordRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	publisher events.Publisher
 }
 
 // NewWordRepository creates a new instance of SQLWordRepository
-func NewWordRepository(db *sql.DB) *SQLWordRepository {
-	return &SQLWordRepository{db: db}
+// publishing word events to publisher
+func NewWordRepository(db *sql.DB, publisher events.Publisher) *SQLWordRepository {
+	return &SQLWordRepository{db: db, publisher: publisher}
 }
 
 // Create adds a new word to the database
 func (r *SQLWordRepository) Create(ctx context.Context, word *models.Word) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO words (kanji, romaji, english, parts)
 		VALUES (?, ?, ?, ?)
 	`
-	result, err := r.db.ExecContext(ctx, query, word.Kanji, word.Romaji, word.English, word.Parts)
+	result, err := tx.ExecContext(ctx, query, word.Kanji, word.Romaji, word.English, word.Parts)
 	if err != nil {
 		return fmt.Errorf("failed to create word: %w", err)
 	}
@@ -65,6 +77,12 @@ func (r *SQLWordRepository) Create(ctx context.Context, word *models.Word) error
 	if err != nil {
 		return fmt.Errorf("failed to get last insert ID: %w", err)
 	}
+	if err := linkWordKanji(ctx, tx, id, word.Kanji, word.Parts); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit word: %w", err)
+	}
 	word.ID = id
 	return nil
 }
@@ -72,14 +90,15 @@ func (r *SQLWordRepository) Create(ctx context.Context, word *models.Word) error
 // GetByID retrieves a word by its ID
 func (r *SQLWordRepository) GetByID(ctx context.Context, id int64) (*models.Word, error) {
 	query := `
-		SELECT id, kanji, romaji, english, parts
+		SELECT id, kanji, romaji, english, parts, ` + wordAudioHash("words") + `, ` + wordImageHashes("words") + `
 		FROM words
 		WHERE id = ?
 	`
 	var word models.Word
 	var partsData interface{}
+	var audioHash, imageHash, thumbnailHash sql.NullString
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&word.ID, &word.Kanji, &word.Romaji, &word.English, &partsData,
+		&word.ID, &word.Kanji, &word.Romaji, &word.English, &partsData, &audioHash, &imageHash, &thumbnailHash,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -92,10 +111,29 @@ func (r *SQLWordRepository) GetByID(ctx context.Context, id int64) (*models.Word
 	if err := word.UnmarshalParts(partsData); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal parts: %w", err)
 	}
+	word.AudioURL = mediaURL(audioHash)
+	word.ImageURL, word.ImageThumbnailURL = mediaURL(imageHash), mediaURL(thumbnailHash)
 
 	return &word, nil
 }
 
+// FindID retrieves the ID of the word with exactly this kanji, romaji and meaning
+func (r *SQLWordRepository) FindID(ctx context.Context, kanji, romaji, english string) (int64, error) {
+	return findWordID(ctx, r.db, kanji, romaji, english)
+}
+
+// findWordID looks up a word by its fields, returning sql.ErrNoRows when there is none
+func findWordID(ctx context.Context, q queryRower, kanji, romaji, english string) (int64, error) {
+	var id int64
+	err := q.QueryRowContext(ctx, `
+		SELECT id FROM words WHERE kanji = ? AND romaji = ? AND english = ? ORDER BY id LIMIT 1
+	`, kanji, romaji, english).Scan(&id)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to find word: %w", err)
+	}
+	return id, err
+}
+
 // Update modifies an existing word
 func (r *SQLWordRepository) Update(ctx context.Context, word *models.Word) error {
 	query := `
@@ -103,12 +141,34 @@ func (r *SQLWordRepository) Update(ctx context.Context, word *models.Word) error
 		SET kanji = ?, romaji = ?, english = ?, parts = ?
 		WHERE id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query,
 		word.Kanji, word.Romaji, word.English, word.Parts, word.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update word: %w", err)
 	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated word: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if err := linkWordKanji(ctx, tx, word.ID, word.Kanji, word.Parts); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit word: %w", err)
+	}
+
+	r.publisher.Publish(ctx, events.Event{Type: events.WordUpdated, Data: *word})
 	return nil
 }
 
@@ -125,7 +185,7 @@ func (r *SQLWordRepository) Delete(ctx context.Context, id int64) error {
 // List retrieves words with optional filtering and pagination
 func (r *SQLWordRepository) List(ctx context.Context, filter WordFilter, page, pageSize int) ([]models.Word, int, error) {
 	// Build dynamic query based on filter
-	baseQuery := `SELECT id, kanji, romaji, english, parts FROM words`
+	baseQuery := `SELECT id, kanji, romaji, english, parts, ` + wordAudioHash("words") + `, ` + wordImageHashes("words") + ` FROM words`
 	countQuery := `SELECT COUNT(*) FROM words`
 	var conditions []string
 	var args []interface{}
@@ -178,12 +238,16 @@ func (r *SQLWordRepository) List(ctx context.Context, filter WordFilter, page, p
 	for rows.Next() {
 		var word models.Word
 		var partsData interface{}
+		var audioHash, imageHash, thumbnailHash sql.NullString
 		if err := rows.Scan(
 			&word.ID,
 			&word.Kanji,
 			&word.Romaji,
 			&word.English,
 			&partsData,
+			&audioHash,
+			&imageHash,
+			&thumbnailHash,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan word: %w", err)
 		}
@@ -192,6 +256,8 @@ func (r *SQLWordRepository) List(ctx context.Context, filter WordFilter, page, p
 		if err := word.UnmarshalParts(partsData); err != nil {
 			return nil, 0, fmt.Errorf("failed to unmarshal parts: %w", err)
 		}
+		word.AudioURL = mediaURL(audioHash)
+		word.ImageURL, word.ImageThumbnailURL = mediaURL(imageHash), mediaURL(thumbnailHash)
 
 		words = append(words, word)
 	}
@@ -0,0 +1,492 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"lang-portal/internal/events"
+	"lang-portal/internal/models"
+	"lang-portal/internal/xapi"
+)
+
+// XAPIRepository defines the interface for the xAPI statement store
+type XAPIRepository interface {
+	// SaveStatements stores statements and maps portal-related ones onto reviews and sessions
+	SaveStatements(ctx context.Context, statements []*xapi.StoredStatement) error
+
+	// GetStatement retrieves a single statement; voided selects voided instead of valid statements
+	GetStatement(ctx context.Context, id string, voided bool) (json.RawMessage, error)
+
+	// QueryStatements retrieves valid statements matching a filter, returning the cursor of the next page (0 when done)
+	QueryStatements(ctx context.Context, filter xapi.StatementFilter) ([]json.RawMessage, int64, error)
+
+	// ReviewStatements emits word reviews not recorded through xAPI as statements, after the given review ID
+	ReviewStatements(ctx context.Context, afterID int64, limit int) ([]json.RawMessage, int64, error)
+}
+
+// SQLXAPIRepository implements XAPIRepository using SQLite
+type SQLXAPIRepository struct {
+	db        *sql.DB
+	iris      xapi.IRIs
+	publisher events.Publisher
+}
+
+// NewXAPIRepository creates a new instance of SQLXAPIRepository
+// publishing the session and review events of mapped statements to publisher
+func NewXAPIRepository(db *sql.DB, iris xapi.IRIs, publisher events.Publisher) *SQLXAPIRepository {
+	return &SQLXAPIRepository{db: db, iris: iris, publisher: publisher}
+}
+
+// SaveStatements stores statements and maps portal-related ones onto reviews and sessions
+func (r *SQLXAPIRepository) SaveStatements(ctx context.Context, statements []*xapi.StoredStatement) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var batch events.Batch
+	for _, statement := range statements {
+		// Resubmitting an identical statement is a no-op, a different one is a conflict
+		var original string
+		err := tx.QueryRowContext(ctx, `SELECT original FROM xapi_statements WHERE id = ?`, statement.ID).Scan(&original)
+		if err == nil {
+			if !sameJSON([]byte(original), statement.Original) {
+				return fmt.Errorf("statement conflict: %s already exists with different content", statement.ID)
+			}
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to check statement: %w", err)
+		}
+
+		var reviewID, sessionID sql.NullInt64
+		if statement.IsVoiding() {
+			if err := r.voidStatement(ctx, tx, statement.Object.ID, &batch); err != nil {
+				return err
+			}
+		} else {
+			reviewID, sessionID, err = r.mapStatement(ctx, tx, statement, &batch)
+			if err != nil {
+				return err
+			}
+		}
+
+		registration := sql.NullString{}
+		if statement.Context != nil && statement.Context.Registration != "" {
+			registration = sql.NullString{String: strings.ToLower(statement.Context.Registration), Valid: true}
+		}
+
+		query := `
+			INSERT INTO xapi_statements 
+			(id, actor_ifi, verb_id, object_id, registration, statement, original, timestamp, stored, word_review_item_id, study_session_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err = tx.ExecContext(ctx, query,
+			statement.ID,
+			statement.Actor.Identifier(),
+			statement.Verb.ID,
+			statement.Object.ID,
+			registration,
+			string(statement.Raw),
+			string(statement.Original),
+			statement.TimestampTime,
+			statement.StoredTime,
+			reviewID,
+			sessionID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store statement: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit statements: %w", err)
+	}
+
+	batch.Publish(ctx, r.publisher)
+	return nil
+}
+
+// voidStatement marks a statement as voided and removes the review derived from it
+func (r *SQLXAPIRepository) voidStatement(ctx context.Context, tx *sql.Tx, targetID string, batch *events.Batch) error {
+	var verbID string
+	var reviewID sql.NullInt64
+	query := `SELECT verb_id, word_review_item_id FROM xapi_statements WHERE id = ?`
+	err := tx.QueryRowContext(ctx, query, strings.ToLower(targetID)).Scan(&verbID, &reviewID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("voided statement not found: %s", targetID)
+		}
+		return fmt.Errorf("failed to find voided statement: %w", err)
+	}
+	if verbID == xapi.VerbVoided {
+		return fmt.Errorf("cannot void a voiding statement: %s", targetID)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE xapi_statements SET voided = 1, word_review_item_id = NULL WHERE id = ?`, strings.ToLower(targetID))
+	if err != nil {
+		return fmt.Errorf("failed to void statement: %w", err)
+	}
+
+	if reviewID.Valid {
+		review := models.WordReviewItem{ID: reviewID.Int64}
+		err := tx.QueryRowContext(ctx, `
+			DELETE FROM word_review_items WHERE id = ?
+			RETURNING word_id, study_session_id, correct, created_at
+		`, reviewID.Int64).Scan(&review.WordID, &review.StudySessionID, &review.Correct, &review.CreatedAt)
+		switch {
+		case err == nil:
+			batch.Add(events.ReviewDeleted, review)
+		case err != sql.ErrNoRows:
+			return fmt.Errorf("failed to remove voided review: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// mapStatement records answered statements on words as word reviews and completed
+// statements as session completions, returning the review and session they map to
+func (r *SQLXAPIRepository) mapStatement(ctx context.Context, tx *sql.Tx, statement *xapi.StoredStatement, batch *events.Batch) (sql.NullInt64, sql.NullInt64, error) {
+	var reviewID, sessionID sql.NullInt64
+
+	wordID, isWord := r.iris.ParseWord(statement.Object.ID)
+	answered := statement.Verb.ID == xapi.VerbAnswered && isWord &&
+		statement.Result != nil && statement.Result.Success != nil
+	completed := statement.Verb.ID == xapi.VerbCompleted
+	if !answered && !completed {
+		return reviewID, sessionID, nil
+	}
+
+	id, ok, err := r.resolveSession(ctx, tx, statement, batch)
+	if err != nil || !ok {
+		return reviewID, sessionID, err
+	}
+	sessionID = sql.NullInt64{Int64: id, Valid: true}
+
+	if answered {
+		var exists int
+		err := tx.QueryRowContext(ctx, `SELECT 1 FROM words WHERE id = ?`, wordID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return reviewID, sessionID, nil
+		}
+		if err != nil {
+			return reviewID, sessionID, fmt.Errorf("failed to validate word: %w", err)
+		}
+
+		query := `
+			INSERT INTO word_review_items 
+			(word_id, study_session_id, correct, created_at) 
+			VALUES (?, ?, ?, ?)
+		`
+		result, err := tx.ExecContext(ctx, query, wordID, id, *statement.Result.Success, occurredAt(statement))
+		if err != nil {
+			return reviewID, sessionID, fmt.Errorf("failed to record review: %w", err)
+		}
+		insertedID, err := result.LastInsertId()
+		if err != nil {
+			return reviewID, sessionID, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		reviewID = sql.NullInt64{Int64: insertedID, Valid: true}
+		batch.Add(events.ReviewCreated, models.WordReviewItem{
+			ID:             insertedID,
+			WordID:         wordID,
+			StudySessionID: id,
+			Correct:        *statement.Result.Success,
+			CreatedAt:      occurredAt(statement),
+		})
+	}
+
+	if completed {
+		query := `UPDATE study_sessions SET completed_at = ? WHERE id = ? AND completed_at IS NULL`
+		result, err := tx.ExecContext(ctx, query, occurredAt(statement), id)
+		if err != nil {
+			return reviewID, sessionID, fmt.Errorf("failed to complete study session: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			session, err := getStudySession(ctx, tx, id)
+			if err != nil {
+				return reviewID, sessionID, err
+			}
+			batch.Add(events.SessionCompleted, *session)
+		}
+	}
+
+	return reviewID, sessionID, nil
+}
+
+// occurredAt dates the reviews and sessions a statement maps to: its timestamp, unless
+// that is later than when it was stored, so a clock ahead cannot reach into future streaks
+func occurredAt(statement *xapi.StoredStatement) time.Time {
+	if statement.TimestampTime.After(statement.StoredTime) {
+		return statement.StoredTime
+	}
+	return statement.TimestampTime
+}
+
+// resolveSession finds the study session of a statement from the session extension
+// or its registration, creating one when the context names a group and study activity
+func (r *SQLXAPIRepository) resolveSession(ctx context.Context, tx *sql.Tx, statement *xapi.StoredStatement, batch *events.Batch) (int64, bool, error) {
+	if id, ok := r.iris.StudySessionID(statement.Context); ok {
+		var exists int
+		err := tx.QueryRowContext(ctx, `SELECT 1 FROM study_sessions WHERE id = ?`, id).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to validate study session: %w", err)
+		}
+		return id, true, nil
+	}
+
+	if statement.Context == nil || statement.Context.Registration == "" {
+		return 0, false, nil
+	}
+	registration := strings.ToLower(statement.Context.Registration)
+
+	var sessionID int64
+	err := tx.QueryRowContext(ctx, `SELECT study_session_id FROM xapi_registrations WHERE registration = ?`, registration).Scan(&sessionID)
+	if err == nil {
+		return sessionID, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, false, fmt.Errorf("failed to find registration: %w", err)
+	}
+
+	// Start a session for a new registration when the group and activity are known
+	var groupID, activityID int64
+	if activities := statement.Context.ContextActivities; activities != nil {
+		related := append(append([]xapi.Object{}, activities.Parent...), activities.Grouping...)
+		for _, object := range related {
+			if id, ok := r.iris.ParseGroup(object.ID); ok {
+				groupID = id
+			}
+			if id, ok := r.iris.ParseStudyActivity(object.ID); ok {
+				activityID = id
+			}
+		}
+	}
+	if groupID == 0 || activityID == 0 {
+		return 0, false, nil
+	}
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `
+		SELECT 1 FROM groups g, study_activities sa WHERE g.id = ? AND sa.id = ?
+	`, groupID, activityID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to validate group and activity: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO study_sessions (group_id, study_activity_id, created_at) VALUES (?, ?, ?)
+	`, groupID, activityID, occurredAt(statement))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create study session: %w", err)
+	}
+	sessionID, err = result.LastInsertId()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO xapi_registrations (registration, study_session_id) VALUES (?, ?)
+	`, registration, sessionID)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to record registration: %w", err)
+	}
+
+	batch.Add(events.SessionCreated, models.StudySession{
+		ID:              sessionID,
+		GroupID:         groupID,
+		StudyActivityID: activityID,
+		CreatedAt:       occurredAt(statement),
+	})
+	return sessionID, true, nil
+}
+
+// GetStatement retrieves a single statement; voided selects voided instead of valid statements
+func (r *SQLXAPIRepository) GetStatement(ctx context.Context, id string, voided bool) (json.RawMessage, error) {
+	query := `SELECT statement FROM xapi_statements WHERE id = ? AND voided = ?`
+	var statement string
+	err := r.db.QueryRowContext(ctx, query, strings.ToLower(id), voided).Scan(&statement)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("statement not found")
+		}
+		return nil, fmt.Errorf("failed to get statement: %w", err)
+	}
+
+	return json.RawMessage(statement), nil
+}
+
+// QueryStatements retrieves valid statements matching a filter, returning the cursor of the next page (0 when done)
+func (r *SQLXAPIRepository) QueryStatements(ctx context.Context, filter xapi.StatementFilter) ([]json.RawMessage, int64, error) {
+	conditions := []string{"voided = 0"}
+	args := []interface{}{}
+
+	if filter.Agent != "" {
+		conditions = append(conditions, "actor_ifi = ?")
+		args = append(args, filter.Agent)
+	}
+	if filter.Verb != "" {
+		conditions = append(conditions, "verb_id = ?")
+		args = append(args, filter.Verb)
+	}
+	if filter.Activity != "" {
+		conditions = append(conditions, "object_id = ?")
+		args = append(args, filter.Activity)
+	}
+	if filter.Registration != "" {
+		conditions = append(conditions, "registration = ?")
+		args = append(args, strings.ToLower(filter.Registration))
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "stored > ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if filter.Until != nil {
+		conditions = append(conditions, "stored <= ?")
+		args = append(args, filter.Until.UTC())
+	}
+
+	order := "DESC"
+	if filter.Ascending {
+		order = "ASC"
+	}
+	if filter.Cursor > 0 {
+		if filter.Ascending {
+			conditions = append(conditions, "seq > ?")
+		} else {
+			conditions = append(conditions, "seq < ?")
+		}
+		args = append(args, filter.Cursor)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > xapi.MaxLimit {
+		limit = xapi.MaxLimit
+	}
+
+	// Fetch one extra row to know whether another page exists
+	query := fmt.Sprintf(`
+		SELECT seq, statement
+		FROM xapi_statements
+		WHERE %s
+		ORDER BY seq %s
+		LIMIT ?
+	`, strings.Join(conditions, " AND "), order)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit+1)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query statements: %w", err)
+	}
+	defer rows.Close()
+
+	statements := []json.RawMessage{}
+	var lastSeq, nextCursor int64
+	for rows.Next() {
+		var seq int64
+		var statement string
+		if err := rows.Scan(&seq, &statement); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan statement: %w", err)
+		}
+		if len(statements) == limit {
+			nextCursor = lastSeq
+			break
+		}
+		statements = append(statements, json.RawMessage(statement))
+		lastSeq = seq
+	}
+
+	return statements, nextCursor, nil
+}
+
+// ReviewStatements emits word reviews not recorded through xAPI as statements, after the given review ID
+func (r *SQLXAPIRepository) ReviewStatements(ctx context.Context, afterID int64, limit int) ([]json.RawMessage, int64, error) {
+	if limit <= 0 || limit > xapi.MaxLimit {
+		limit = xapi.MaxLimit
+	}
+
+	query := `
+		SELECT 
+			wri.id,
+			wri.word_id,
+			w.kanji,
+			w.english,
+			wri.study_session_id,
+			ss.group_id,
+			ss.study_activity_id,
+			ss.learner_id,
+			wri.correct,
+			wri.created_at
+		FROM word_review_items wri
+		JOIN words w ON w.id = wri.word_id
+		JOIN study_sessions ss ON ss.id = wri.study_session_id
+		WHERE wri.id > ?
+			AND NOT EXISTS (SELECT 1 FROM xapi_statements xs WHERE xs.word_review_item_id = wri.id)
+		ORDER BY wri.id
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit+1)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	defer rows.Close()
+
+	statements := []json.RawMessage{}
+	var lastID, nextCursor int64
+	for rows.Next() {
+		var review xapi.Review
+		var learnerID sql.NullInt64
+		if err := rows.Scan(
+			&review.ID,
+			&review.WordID,
+			&review.Kanji,
+			&review.English,
+			&review.StudySessionID,
+			&review.GroupID,
+			&review.StudyActivityID,
+			&learnerID,
+			&review.Correct,
+			&review.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan review: %w", err)
+		}
+		// Sessions from before learners existed are attributed to a shared account
+		review.Learner = "learner"
+		if learnerID.Valid {
+			review.Learner = fmt.Sprintf("learner-%d", learnerID.Int64)
+		}
+		if len(statements) == limit {
+			nextCursor = lastID
+			break
+		}
+
+		statement, err := r.iris.ReviewStatement(review)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to build review statement: %w", err)
+		}
+		statements = append(statements, statement)
+		lastID = review.ID
+	}
+
+	return statements, nextCursor, nil
+}
+
+// sameJSON reports whether two JSON documents are semantically equal
+func sameJSON(a, b []byte) bool {
+	var left, right interface{}
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
@@ -1,11 +1,11 @@
 package routes
 
 import (
-	"fmt"
-
 	"github.com/gin-gonic/gin"
 
 	"lang-portal/internal/handlers"
+	"lang-portal/internal/launch"
+	"lang-portal/internal/middleware"
 )
 
 // SetupRoutes configures and returns the main router with all API routes
@@ -14,27 +14,124 @@ func SetupRoutes(
 	studyActivityHandler *handlers.StudyActivityHandler,
 	studySessionHandler *handlers.StudySessionHandler,
 	dashboardHandler *handlers.DashboardHandler,
+	launchHandler *handlers.LaunchHandler,
+	launchSigner *launch.Signer,
+	xapiHandler *handlers.XAPIHandler,
+	ltiHandler *handlers.LTIHandler,
+	wordHandler *handlers.WordHandler,
+	webhookHandler *handlers.WebhookHandler,
+	dashboardStreamHandler *handlers.DashboardStreamHandler,
+	quizHandler *handlers.QuizHandler,
+	streakHandler *handlers.StreakHandler,
+	leechHandler *handlers.LeechHandler,
+	calendarHandler *handlers.CalendarHandler,
+	masteryHandler *handlers.MasteryHandler,
+	goalHandler *handlers.GoalHandler,
+	achievementHandler *handlers.AchievementHandler,
+	leaderboardHandler *handlers.LeaderboardHandler,
+	wordContentHandler *handlers.WordContentHandler,
+	vocabHandler *handlers.VocabHandler,
+	mediaHandler *handlers.MediaHandler,
+	assetHandler *handlers.AssetHandler,
+	kanjiHandler *handlers.KanjiHandler,
+	dictionaryHandler *handlers.DictionaryHandler,
 ) *gin.Engine {
 	router := gin.Default()
 
 	// API versioning
 	v1 := router.Group("/api/v1")
 	{
+		// Words routes
+		words := v1.Group("/words")
+		{
+			words.GET("", wordHandler.GetWords)
+			words.POST("", wordHandler.CreateWord)
+			words.GET("/leeches", leechHandler.GetLeeches)
+			words.POST("/leeches/needs-work", leechHandler.MoveLeechesToNeedsWork)
+			words.GET("/:id", wordHandler.GetWord)
+			words.PUT("/:id", wordHandler.UpdateWord)
+			words.DELETE("/:id", wordHandler.DeleteWord)
+			words.GET("/:id/content", wordContentHandler.ListWordContent)
+			words.GET("/:id/content/:kind", wordContentHandler.GetWordContent)
+			words.PUT("/:id/content/:kind", wordContentHandler.UpdateWordContent)
+			words.DELETE("/:id/content/:kind", wordContentHandler.DeleteWordContent)
+			words.POST("/:id/content/:kind/generate", wordContentHandler.GenerateWordContent)
+			words.GET("/:id/audio", mediaHandler.GetWordAudio)
+			words.PUT("/:id/audio", mediaHandler.UploadWordAudio)
+			words.DELETE("/:id/audio", mediaHandler.DeleteWordAudio)
+			words.POST("/:id/audio/generate", mediaHandler.GenerateWordAudio)
+			words.GET("/:id/image", mediaHandler.GetWordImage)
+			words.PUT("/:id/image", mediaHandler.UploadWordImage)
+			words.DELETE("/:id/image", mediaHandler.DeleteWordImage)
+			words.GET("/:id/kanji", kanjiHandler.GetWordKanji)
+		}
+
+		// Kanji dictionary
+		v1.GET("/kanji/:char", kanjiHandler.GetKanji)
+
+		// Dictionary routes
+		dictionary := v1.Group("/dictionary")
+		{
+			dictionary.GET("/lookup", dictionaryHandler.Lookup)
+			dictionary.GET("/entries/:id", dictionaryHandler.GetEntry)
+			dictionary.GET("/entries/:id/word", dictionaryHandler.DraftWord)
+			dictionary.POST("/entries/:id/word", dictionaryHandler.CreateWord)
+		}
+
+		// Media files, addressed by the hash of their content
+		v1.GET("/media/:hash", mediaHandler.GetMedia)
+
 		// Groups routes
 		groups := v1.Group("/groups")
 		{
 			groups.GET("", groupHandler.GetGroups)
+			groups.POST("", groupHandler.CreateGroup)
+			groups.POST("/generate", vocabHandler.GenerateGroup)
+			groups.POST("/generate/confirm", vocabHandler.ConfirmGeneratedGroup)
 			groups.GET("/:id", groupHandler.GetGroup)
+			groups.PATCH("/:id", groupHandler.UpdateGroup)
+			groups.DELETE("/:id", groupHandler.DeleteGroup)
 			groups.GET("/:id/words", groupHandler.GetGroupWords)
+			groups.POST("/:id/words", groupHandler.AddGroupWords)
+			groups.DELETE("/:id/words", groupHandler.RemoveGroupWords)
 			groups.GET("/:id/words/raw", groupHandler.GetGroupWordsRaw)
 			groups.GET("/:id/study-sessions", groupHandler.GetGroupStudySessions)
+			groups.GET("/:id/problem-words", leechHandler.GetGroupProblemWords)
+			groups.GET("/:id/mastery", masteryHandler.GetGroupMastery)
 		}
 
 		// Study Activities routes
 		studyActivities := v1.Group("/study-activities")
 		{
 			studyActivities.GET("", studyActivityHandler.ListStudyActivities)
+			studyActivities.POST("", studyActivityHandler.CreateStudyActivity)
 			studyActivities.GET("/:id", studyActivityHandler.GetStudyActivity)
+			studyActivities.PATCH("/:id", studyActivityHandler.UpdateStudyActivity)
+			studyActivities.DELETE("/:id", studyActivityHandler.RetireStudyActivity)
+			studyActivities.POST("/:id/launch", launchHandler.LaunchStudyActivity)
+			studyActivities.GET("/:id/thumbnail", mediaHandler.GetStudyActivityThumbnail)
+			studyActivities.PUT("/:id/thumbnail", mediaHandler.UploadStudyActivityThumbnail)
+			studyActivities.DELETE("/:id/thumbnail", mediaHandler.DeleteStudyActivityThumbnail)
+		}
+
+		// Launch callback routes, authenticated by the launch token
+		launched := v1.Group("/launch", middleware.LaunchTokenAuth(launchSigner))
+		{
+			launched.GET("/session", launchHandler.GetLaunchSession)
+			launched.POST("/reviews", launchHandler.CreateLaunchReview)
+			launched.POST("/complete", launchHandler.CompleteLaunchSession)
+		}
+
+		// Portal reviews exported as xAPI statements
+		v1.GET("/xapi/reviews", xapiHandler.GetReviewStatements)
+
+		// LTI platform registrations and grade passback
+		ltiAdmin := v1.Group("/lti")
+		{
+			ltiAdmin.GET("/platforms", ltiHandler.ListPlatforms)
+			ltiAdmin.POST("/platforms", ltiHandler.CreatePlatform)
+			ltiAdmin.DELETE("/platforms/:id", ltiHandler.DeletePlatform)
+			ltiAdmin.POST("/sessions/:id/score", ltiHandler.PublishScore)
 		}
 
 		// Study Sessions routes
@@ -43,19 +140,117 @@ func SetupRoutes(
 			studySessions.GET("", studySessionHandler.ListStudySessions)
 			studySessions.POST("", studySessionHandler.CreateStudySession)
 			studySessions.GET("/:id", studySessionHandler.GetStudySessionDetails)
+			studySessions.POST("/:id/complete", studySessionHandler.CompleteStudySession)
 			studySessions.GET("/:id/words", studySessionHandler.ListStudySessionWords)
 			studySessions.POST("/:id/words/:word-id/review", studySessionHandler.CreateWordReview)
 		}
 
+		// Webhook subscriptions and delivery log
+		webhooks := v1.Group("/webhooks")
+		{
+			webhooks.GET("", webhookHandler.ListWebhooks)
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.GET("/:id", webhookHandler.GetWebhook)
+			webhooks.PATCH("/:id", webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListWebhookDeliveries)
+			webhooks.POST("/deliveries/:delivery_id/retry", webhookHandler.RetryWebhookDelivery)
+		}
+
 		// Dashboard routes
 		dashboard := v1.Group("/dashboard")
 		{
 			dashboard.GET("/last-study-session", dashboardHandler.GetLastStudySession)
 			dashboard.GET("/study-progress", dashboardHandler.GetStudyProgress)
 			dashboard.GET("/quick-stats", dashboardHandler.GetQuickStats)
+			dashboard.GET("/stream", dashboardStreamHandler.StreamDashboard)
+			dashboard.GET("/history/reviews", dashboardHandler.GetReviewHistory)
+			dashboard.GET("/history/new-words", dashboardHandler.GetNewWordsHistory)
+			dashboard.GET("/history/time-spent", dashboardHandler.GetTimeSpentHistory)
+			dashboard.GET("/calendar", calendarHandler.GetCalendar)
+			dashboard.GET("/calendar/:date", calendarHandler.GetCalendarDay)
+			dashboard.GET("/mastery", masteryHandler.GetMasteryBreakdown)
+			dashboard.GET("/goals", goalHandler.GetDashboardGoals)
+		}
+
+		// Streak routes
+		streaks := v1.Group("/streaks")
+		{
+			streaks.GET("", streakHandler.GetStreak)
+			streaks.GET("/history", streakHandler.GetStreakHistory)
+		}
+
+		// Learner goals and their progress
+		goals := v1.Group("/goals")
+		{
+			goals.GET("", goalHandler.ListGoals)
+			goals.POST("", goalHandler.CreateGoal)
+			goals.GET("/progress", goalHandler.GetGoalProgress)
+			goals.GET("/history", goalHandler.GetGoalHistory)
+			goals.GET("/:id", goalHandler.GetGoal)
+			goals.PATCH("/:id", goalHandler.UpdateGoal)
+			goals.DELETE("/:id", goalHandler.DeleteGoal)
+		}
+
+		// Achievements and badges
+		achievements := v1.Group("/achievements")
+		{
+			achievements.GET("", achievementHandler.ListAchievements)
+			achievements.POST("/backfill", achievementHandler.BackfillAchievements)
+			achievements.GET("/:id", achievementHandler.GetAchievement)
+		}
+
+		// Leaderboards across learners and classes
+		leaderboards := v1.Group("/leaderboards")
+		{
+			leaderboards.GET("", leaderboardHandler.GetLeaderboard)
+			leaderboards.GET("/classes", leaderboardHandler.ListClasses)
+		}
+
+		// Learner privacy settings
+		learners := v1.Group("/learners")
+		{
+			learners.GET("/:id/privacy", leaderboardHandler.GetLearnerPrivacy)
+			learners.PATCH("/:id/privacy", leaderboardHandler.UpdateLearnerPrivacy)
+		}
+
+		// Live quiz rooms
+		quizRooms := v1.Group("/quiz/rooms")
+		{
+			quizRooms.POST("", quizHandler.CreateQuizRoom)
+			quizRooms.GET("/:code", quizHandler.GetQuizRoom)
+			quizRooms.GET("/:code/ws", quizHandler.ConnectQuizRoom)
+		}
+
+		// Generated quizzes, scored into study sessions
+		quizzes := v1.Group("/quizzes")
+		{
+			quizzes.POST("", quizHandler.GenerateQuiz)
+			quizzes.POST("/score", quizHandler.ScoreQuiz)
 		}
 	}
 
+	// xAPI Learning Record Store routes
+	lrs := router.Group("/xapi", middleware.XAPIVersion())
+	{
+		lrs.GET("/statements", xapiHandler.GetStatements)
+		lrs.POST("/statements", xapiHandler.PostStatements)
+		lrs.PUT("/statements", xapiHandler.PutStatement)
+	}
+
+	// LTI 1.3 tool endpoints called by platforms
+	ltiTool := router.Group("/lti")
+	{
+		ltiTool.GET("/login", ltiHandler.Login)
+		ltiTool.POST("/login", ltiHandler.Login)
+		ltiTool.POST("/launch", ltiHandler.Launch)
+		ltiTool.GET("/jwks", ltiHandler.GetJWKS)
+	}
+
+	// Static assets, such as the preview images of study activities
+	router.GET("/assets/*path", assetHandler.GetAsset)
+	router.HEAD("/assets/*path", assetHandler.GetAsset)
+
 	// Health check route
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -65,17 +260,3 @@ func SetupRoutes(
 
 	return router
 }
-
-// RunServer starts the HTTP server
-func RunServer(router *gin.Engine, port string) error {
-	// Remove leading ':' if present and use localhost
-	if len(port) > 0 && port[0] == ':' {
-		port = port[1:]
-	}
-
-	// Set up the address to listen on
-	address := "localhost:" + port
-
-	fmt.Printf("Starting server on %s\n", address)
-	return router.Run(address)
-}
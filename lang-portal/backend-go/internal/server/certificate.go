@@ -0,0 +1,104 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// certCheckInterval is how often handshakes look for renewed certificate files
+const certCheckInterval = time.Minute
+
+// certificate serves a TLS certificate that is reloaded when its files change,
+// so renewed certificates are picked up without a restart
+type certificate struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// loadCertificate reads a PEM certificate and key pair
+func loadCertificate(certFile, keyFile string) (*certificate, error) {
+	c := &certificate{certFile: certFile, keyFile: keyFile}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCertificate is a tls.Config callback returning the current certificate
+func (c *certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	cert, stale := c.cert, time.Since(c.checkedAt) > certCheckInterval
+	c.mu.RUnlock()
+
+	if stale {
+		if changed, err := c.changed(); err != nil {
+			log.Printf("Failed to check TLS certificate: %v", err)
+		} else if changed {
+			// Keep serving the old certificate if the new files are unusable, e.g. half written
+			if err := c.reload(); err != nil {
+				log.Printf("Failed to reload TLS certificate: %v", err)
+			}
+		}
+		c.mu.RLock()
+		cert = c.cert
+		c.mu.RUnlock()
+	}
+
+	return cert, nil
+}
+
+// changed reports whether the files were modified since the last load
+func (c *certificate) changed() (bool, error) {
+	modTime, err := c.latestModTime()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkedAt = time.Now()
+	if err != nil {
+		return false, err
+	}
+	return modTime.After(c.modTime), nil
+}
+
+// reload reads both files and swaps the certificate in
+func (c *certificate) reload() error {
+	modTime, err := c.latestModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cert = &cert
+	c.modTime = modTime
+	c.checkedAt = time.Now()
+	return nil
+}
+
+// latestModTime returns the most recent modification time of the two files
+func (c *certificate) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error reading TLS certificate: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Config holds the listener settings of the HTTP server
+type Config struct {
+	// Addr is the host:port to listen on
+	Addr string
+	// ReadHeaderTimeout bounds reading request headers
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout bounds reading a whole request, including the body
+	ReadTimeout time.Duration
+	// WriteTimeout bounds writing a response; streaming handlers extend it per write
+	WriteTimeout time.Duration
+	// IdleTimeout closes keep-alive connections without requests
+	IdleTimeout time.Duration
+	// CertFile and KeyFile enable TLS when both are set
+	CertFile string
+	KeyFile  string
+}
+
+// TLS reports whether the configuration serves HTTPS
+func (c Config) TLS() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// Server runs the HTTP API with timeouts, optional TLS and graceful shutdown
+type Server struct {
+	http *http.Server
+	cert *certificate
+}
+
+// New creates a server for handler; with TLS configured the certificate is loaded up front
+func New(handler http.Handler, cfg Config) (*Server, error) {
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, fmt.Errorf("TLS needs both a certificate and a key file")
+	}
+
+	s := &Server{
+		http: &http.Server{
+			Addr:              cfg.Addr,
+			Handler:           handler,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			ReadTimeout:       cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+	}
+
+	if cfg.TLS() {
+		cert, err := loadCertificate(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.cert = cert
+		s.http.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: cert.GetCertificate,
+		}
+	}
+
+	return s, nil
+}
+
+// RegisterOnShutdown registers fn to run when Shutdown starts, such as
+// ending long-lived streams that would otherwise hold the drain open
+func (s *Server) RegisterOnShutdown(fn func()) {
+	s.http.RegisterOnShutdown(fn)
+}
+
+// ListenAndServe serves until Shutdown is called, which makes it return nil
+func (s *Server) ListenAndServe() error {
+	var err error
+	if s.cert != nil {
+		log.Printf("Starting server on https://%s", s.http.Addr)
+		// The certificate comes from TLSConfig.GetCertificate
+		err = s.http.ListenAndServeTLS("", "")
+	} else {
+		log.Printf("Starting server on http://%s", s.http.Addr)
+		err = s.http.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests.
+// Connections still open when ctx ends are closed forcibly.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.http.Shutdown(ctx); err != nil {
+		s.http.Close()
+		return fmt.Errorf("graceful shutdown interrupted: %w", err)
+	}
+	return nil
+}
+
+// ReloadCertificate reads the certificate files again; without TLS it does nothing
+func (s *Server) ReloadCertificate() error {
+	if s.cert == nil {
+		return nil
+	}
+	return s.cert.reload()
+}
@@ -0,0 +1,44 @@
+package streak
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/internal/repository"
+)
+
+// Service computes streaks from the recorded study activity
+type Service struct {
+	repo     repository.StreakRepository
+	location *time.Location
+	rules    Rules
+}
+
+// NewService creates a service counting days in loc unless a request asks for another timezone
+func NewService(repo repository.StreakRepository, loc *time.Location, rules Rules) *Service {
+	return &Service{repo: repo, location: loc, rules: rules}
+}
+
+// Location returns the default timezone of the service
+func (s *Service) Location() *time.Location {
+	return s.location
+}
+
+// Summary returns the streak as of now, counting days in loc
+func (s *Service) Summary(ctx context.Context, loc *time.Location, now time.Time) (Summary, []Day, error) {
+	activity, err := s.repo.GetActivityTimes(ctx)
+	if err != nil {
+		return Summary{}, nil, err
+	}
+	summary, days := Calculate(activity, loc, now, s.rules)
+	return summary, days, nil
+}
+
+// CurrentStreak returns today's streak in the default timezone; it implements repository.StreakSource
+func (s *Service) CurrentStreak(ctx context.Context) (int, error) {
+	summary, _, err := s.Summary(ctx, s.location, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return summary.CurrentStreak, nil
+}
@@ -0,0 +1,113 @@
+package streak
+
+import "time"
+
+// Day statuses
+const (
+	// StatusStudied marks a day with a study session or review
+	StatusStudied = "studied"
+	// StatusFrozen marks a missed day covered by a streak freeze
+	StatusFrozen = "frozen"
+	// StatusMissed marks a day without study that ended the streak, if there was one
+	StatusMissed = "missed"
+	// StatusPending marks today before anything was studied; the streak is not lost yet
+	StatusPending = "pending"
+)
+
+// DateFormat is the format of streak days, which are calendar days in the chosen timezone
+const DateFormat = "2006-01-02"
+
+// Rules decide how streak freezes are earned
+type Rules struct {
+	// FreezeEvery earns a freeze each time the streak reaches a multiple of this many days
+	FreezeEvery int
+	// MaxFreezes bounds the freezes that can be banked
+	MaxFreezes int
+}
+
+// DefaultRules earn a freeze per week of study, banking up to two
+var DefaultRules = Rules{FreezeEvery: 7, MaxFreezes: 2}
+
+// Day is the streak state at the end of one day
+type Day struct {
+	Date             string `json:"date"`
+	Status           string `json:"status"`
+	Streak           int    `json:"streak"`
+	FreezesAvailable int    `json:"freezes_available"`
+}
+
+// Summary is the streak state as of today
+type Summary struct {
+	Timezone         string `json:"timezone"`
+	CurrentStreak    int    `json:"current_streak"`
+	LongestStreak    int    `json:"longest_streak"`
+	StudiedToday     bool   `json:"studied_today"`
+	FreezesAvailable int    `json:"freezes_available"`
+	FreezesUsed      int    `json:"freezes_used"`
+	FreezeEvery      int    `json:"freeze_every_days"`
+	MaxFreezes       int    `json:"max_freezes"`
+}
+
+// Calculate replays activity day by day, from the first day anything was studied up to today in loc.
+// A missed day uses a banked freeze when there is a streak to protect; otherwise the streak ends.
+// Frozen days keep the streak but do not lengthen it.
+func Calculate(activity []time.Time, loc *time.Location, now time.Time, rules Rules) (Summary, []Day) {
+	summary := Summary{
+		Timezone:    loc.String(),
+		FreezeEvery: rules.FreezeEvery,
+		MaxFreezes:  rules.MaxFreezes,
+	}
+	if len(activity) == 0 {
+		return summary, nil
+	}
+
+	studied := make(map[string]bool, len(activity))
+	first := date(activity[0], loc)
+	for _, at := range activity {
+		day := date(at, loc)
+		studied[day.Format(DateFormat)] = true
+		if day.Before(first) {
+			first = day
+		}
+	}
+
+	today := date(now, loc)
+	var days []Day
+	streak, freezes := 0, 0
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		key := day.Format(DateFormat)
+		status := StatusMissed
+		switch {
+		case studied[key]:
+			status = StatusStudied
+			streak++
+			if rules.FreezeEvery > 0 && streak%rules.FreezeEvery == 0 && freezes < rules.MaxFreezes {
+				freezes++
+			}
+		case day.Equal(today):
+			status = StatusPending
+		case streak > 0 && freezes > 0:
+			status = StatusFrozen
+			freezes--
+			summary.FreezesUsed++
+		default:
+			streak = 0
+		}
+
+		if streak > summary.LongestStreak {
+			summary.LongestStreak = streak
+		}
+		days = append(days, Day{Date: key, Status: status, Streak: streak, FreezesAvailable: freezes})
+	}
+
+	summary.CurrentStreak = streak
+	summary.StudiedToday = studied[today.Format(DateFormat)]
+	summary.FreezesAvailable = freezes
+	return summary, days
+}
+
+// date returns midnight of the calendar day of t in loc, as a UTC time so days can be added without DST shifts
+func date(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
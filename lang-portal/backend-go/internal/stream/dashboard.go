@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lang-portal/internal/events"
+	"lang-portal/internal/repository"
+)
+
+// Dashboard stream events, named after the endpoints they mirror
+const (
+	EventQuickStats       = "quick-stats"
+	EventStudyProgress    = "study-progress"
+	EventLastStudySession = "last-study-session"
+)
+
+// DashboardFeed recomputes the dashboard when sessions or reviews are written
+// and broadcasts it to the hub
+type DashboardFeed struct {
+	repo     repository.DashboardRepository
+	hub      *Hub
+	debounce time.Duration
+	changed  chan struct{}
+}
+
+// NewDashboardFeed creates a feed; bursts of changes within debounce cause a single refresh
+func NewDashboardFeed(repo repository.DashboardRepository, hub *Hub, debounce time.Duration) *DashboardFeed {
+	return &DashboardFeed{
+		repo:     repo,
+		hub:      hub,
+		debounce: debounce,
+		changed:  make(chan struct{}, 1),
+	}
+}
+
+// Hub returns the hub the feed broadcasts to
+func (f *DashboardFeed) Hub() *Hub {
+	return f.hub
+}
+
+// HandleEvent is an events.Handler scheduling a refresh for changes the dashboard shows
+func (f *DashboardFeed) HandleEvent(_ context.Context, event events.Event) {
+	switch event.Type {
+	case events.SessionCreated, events.SessionCompleted, events.ReviewCreated, events.ReviewDeleted:
+		select {
+		case f.changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run refreshes the dashboard after changes until ctx is cancelled
+func (f *DashboardFeed) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.changed:
+		}
+
+		// Let a burst of reviews settle into one refresh
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.debounce):
+		}
+		select {
+		case <-f.changed:
+		default:
+		}
+
+		if f.hub.Clients() == 0 {
+			continue
+		}
+		messages, err := f.Snapshot(ctx)
+		if err != nil {
+			log.Printf("Failed to refresh dashboard stream: %v", err)
+			continue
+		}
+		f.hub.Broadcast(messages...)
+	}
+}
+
+// Snapshot computes the current quick stats, study progress and last study session
+func (f *DashboardFeed) Snapshot(ctx context.Context) ([]Message, error) {
+	stats, err := f.repo.GetQuickStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := f.repo.GetStudyProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []Message{
+		f.hub.NewMessage(EventQuickStats, stats),
+		f.hub.NewMessage(EventStudyProgress, progress),
+	}
+
+	lastSession, err := f.repo.GetLastStudySession(ctx)
+	switch {
+	case err == nil:
+		messages = append(messages, f.hub.NewMessage(EventLastStudySession, lastSession))
+	case err.Error() != "no study sessions found":
+		return nil, err
+	}
+
+	return messages, nil
+}
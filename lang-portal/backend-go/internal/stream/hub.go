@@ -0,0 +1,146 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Subscription errors
+var (
+	ErrTooManyClients = errors.New("too many stream clients")
+	ErrHubClosed      = errors.New("stream hub is closed")
+)
+
+// Message is a named snapshot pushed to stream clients
+type Message struct {
+	ID    uint64
+	Event string
+	Data  interface{}
+}
+
+// Client is one subscriber of a hub.
+// Messages are snapshots, so a client that falls behind only keeps the latest
+// message of each event: memory per client is bounded and a slow reader never
+// blocks the broadcaster or the other clients.
+type Client struct {
+	mu      sync.Mutex
+	pending map[string]Message
+	order   []string
+	ready   chan struct{}
+}
+
+// newClient creates a client without pending messages
+func newClient() *Client {
+	return &Client{pending: map[string]Message{}, ready: make(chan struct{}, 1)}
+}
+
+// Ready is signalled when messages are waiting to be drained
+func (c *Client) Ready() <-chan struct{} {
+	return c.ready
+}
+
+// Drain returns the pending messages in the order their events first became pending
+func (c *Client) Drain() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages := make([]Message, 0, len(c.order))
+	for _, event := range c.order {
+		messages = append(messages, c.pending[event])
+		delete(c.pending, event)
+	}
+	c.order = c.order[:0]
+	return messages
+}
+
+// offer queues a message, replacing an unread message of the same event
+func (c *Client) offer(message Message) {
+	c.mu.Lock()
+	if _, waiting := c.pending[message.Event]; !waiting {
+		c.order = append(c.order, message.Event)
+	}
+	c.pending[message.Event] = message
+	c.mu.Unlock()
+
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Hub fans messages out to its clients
+type Hub struct {
+	mu         sync.RWMutex
+	clients    map[*Client]struct{}
+	maxClients int
+	seq        atomic.Uint64
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewHub creates a hub accepting up to maxClients clients
+func NewHub(maxClients int) *Hub {
+	return &Hub{clients: map[*Client]struct{}{}, maxClients: maxClients, closed: make(chan struct{})}
+}
+
+// Subscribe registers a new client
+func (h *Hub) Subscribe() (*Client, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	select {
+	case <-h.closed:
+		return nil, ErrHubClosed
+	default:
+	}
+	if len(h.clients) >= h.maxClients {
+		return nil, ErrTooManyClients
+	}
+	client := newClient()
+	h.clients[client] = struct{}{}
+	return client, nil
+}
+
+// Unsubscribe removes a client
+func (h *Hub) Unsubscribe(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, client)
+}
+
+// Clients returns the number of connected clients
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Close tells clients to stop streaming and refuses new ones
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closeOnce.Do(func() { close(h.closed) })
+}
+
+// Closed is closed once the hub is shutting down
+func (h *Hub) Closed() <-chan struct{} {
+	return h.closed
+}
+
+// NewMessage stamps a message with the next id of the hub
+func (h *Hub) NewMessage(event string, data interface{}) Message {
+	return Message{ID: h.seq.Add(1), Event: event, Data: data}
+}
+
+// Broadcast queues messages for every client
+func (h *Hub) Broadcast(messages ...Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		for _, message := range messages {
+			client.offer(message)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxAudioSize bounds the audio read from the service
+const maxAudioSize = 20 << 20
+
+// OPEAClient talks to the OPEA text-to-speech microservice, e.g. the SpeechT5 one
+// of opea-comps
+type OPEAClient struct {
+	url    string
+	voice  string
+	client *http.Client
+}
+
+// NewOPEAClient creates a client for the service at url, e.g. http://localhost:9088
+func NewOPEAClient(url, voice string, timeout time.Duration) *OPEAClient {
+	return &OPEAClient{
+		url:    strings.TrimSuffix(url, "/"),
+		voice:  voice,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Voice names the voice asked for
+func (c *OPEAClient) Voice() string {
+	if c.voice == "" {
+		return ProviderOPEA
+	}
+	return c.voice
+}
+
+// Synthesize returns the speech from POST /v1/audio/speech, which answers with WAV audio
+func (c *OPEAClient) Synthesize(ctx context.Context, text string) ([]byte, string, error) {
+	request := map[string]string{"input": text}
+	if c.voice != "" {
+		request["voice"] = c.voice
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode speech request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/v1/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid text-to-speech URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", fmt.Errorf("%w: %s responded %s", ErrUnavailable, c.url, resp.Status)
+	}
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxAudioSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if len(audio) == 0 || len(audio) > maxAudioSize {
+		return nil, "", fmt.Errorf("%w: %s returned %d bytes of audio", ErrUnavailable, c.url, len(audio))
+	}
+
+	contentType := "audio/wav"
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && strings.HasPrefix(mediaType, "audio/") {
+		contentType = mediaType
+	}
+	return audio, contentType, nil
+}
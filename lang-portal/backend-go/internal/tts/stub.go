@@ -0,0 +1,59 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// Stub sample format: 8 kHz mono 8-bit PCM
+const (
+	stubSampleRate = 8000
+	stubDuration   = stubSampleRate / 2
+)
+
+// Stub is a deterministic synthesizer for development and demos without a speech service:
+// it answers every text with a short WAV tone whose pitch depends on the text
+type Stub struct{}
+
+// Voice names the stub
+func (Stub) Voice() string {
+	return ProviderStub
+}
+
+// Synthesize returns half a second of a sine tone between 220 and 880 Hz
+func (Stub) Synthesize(_ context.Context, text string) ([]byte, string, error) {
+	hash := fnv.New32a()
+	hash.Write([]byte(text))
+	frequency := 220 + float64(hash.Sum32()%661)
+
+	samples := make([]byte, stubDuration)
+	for i := range samples {
+		// Fade in and out so the tone does not click
+		envelope := math.Min(1, math.Min(float64(i), float64(len(samples)-i))/400)
+		value := math.Sin(2 * math.Pi * frequency * float64(i) / stubSampleRate)
+		samples[i] = byte(128 + 100*envelope*value)
+	}
+
+	var wav bytes.Buffer
+	wav.WriteString("RIFF")
+	binary.Write(&wav, binary.LittleEndian, uint32(36+len(samples)))
+	wav.WriteString("WAVEfmt ")
+	for _, field := range []interface{}{
+		uint32(16),             // fmt chunk size
+		uint16(1),              // PCM
+		uint16(1),              // mono
+		uint32(stubSampleRate), // sample rate
+		uint32(stubSampleRate), // byte rate
+		uint16(1),              // block align
+		uint16(8),              // bits per sample
+	} {
+		binary.Write(&wav, binary.LittleEndian, field)
+	}
+	wav.WriteString("data")
+	binary.Write(&wav, binary.LittleEndian, uint32(len(samples)))
+	wav.Write(samples)
+	return wav.Bytes(), "audio/wav", nil
+}
@@ -0,0 +1,55 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Synthesizer names accepted by New
+const (
+	ProviderStub = "stub"
+	ProviderOPEA = "opea"
+)
+
+// Providers lists the accepted synthesizer names
+var Providers = []string{ProviderStub, ProviderOPEA}
+
+// ErrUnavailable wraps failures to reach a speech service or to get audio from it
+var ErrUnavailable = errors.New("text-to-speech unavailable")
+
+// Synthesizer turns text into speech
+type Synthesizer interface {
+	// Synthesize returns the spoken text along with its content type
+	Synthesize(ctx context.Context, text string) ([]byte, string, error)
+
+	// Voice names the voice speech comes from, as stored with generated audio
+	Voice() string
+}
+
+// Config selects and configures a synthesizer
+type Config struct {
+	// Provider is one of Providers
+	Provider string
+	// URL is the address of an OPEA text-to-speech service
+	URL     string
+	Voice   string
+	Timeout time.Duration
+}
+
+// New creates the synthesizer named in cfg
+func New(cfg Config) (Synthesizer, error) {
+	switch cfg.Provider {
+	case ProviderStub:
+		return Stub{}, nil
+	case ProviderOPEA:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("the %s provider needs a URL", ProviderOPEA)
+		}
+		return NewOPEAClient(cfg.URL, cfg.Voice, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("text-to-speech provider must be one of: %s", strings.Join(Providers, ", "))
+	}
+}
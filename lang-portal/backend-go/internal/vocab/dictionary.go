@@ -0,0 +1,360 @@
+package vocab
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"lang-portal/internal/dictionary"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// maxDraftGlosses bounds the glosses of a sense that make up a drafted meaning
+const maxDraftGlosses = 3
+
+// DraftOptions picks what a word is drafted from; empty options pick the first written
+// form (or reading, for words usually written in kana), its first reading and the first
+// sense applying to them
+type DraftOptions struct {
+	Kanji   string `json:"kanji" form:"kanji"`
+	Reading string `json:"reading" form:"reading"`
+	Sense   *int   `json:"sense" form:"sense"`
+	// English replaces the glosses of the sense as the meaning of the word
+	English string `json:"english" form:"english"`
+}
+
+// Draft is a word drafted from a dictionary entry; ExistingWordID is set when the word
+// is already in the portal
+type Draft struct {
+	EntryID int64  `json:"entry_id"`
+	Reading string `json:"reading"`
+	Sense   int    `json:"sense"`
+	Word
+	ExistingWordID *int64 `json:"existing_word_id,omitempty"`
+}
+
+// Drafter turns dictionary entries into words, splitting them into parts
+type Drafter struct {
+	dictionaryRepo repository.DictionaryRepository
+	kanjiRepo      repository.KanjiRepository
+	wordRepo       repository.WordRepository
+}
+
+// NewDrafter creates a drafter reading kanji from the kanji dictionary to split words
+func NewDrafter(dictionaryRepo repository.DictionaryRepository, kanjiRepo repository.KanjiRepository, wordRepo repository.WordRepository) *Drafter {
+	return &Drafter{dictionaryRepo: dictionaryRepo, kanjiRepo: kanjiRepo, wordRepo: wordRepo}
+}
+
+// Draft fills the fields of a word from a dictionary entry without saving anything
+func (d *Drafter) Draft(ctx context.Context, entryID int64, options DraftOptions) (*Draft, error) {
+	entry, err := d.dictionaryRepo.GetEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	draft := &Draft{EntryID: entry.ID}
+
+	draft.Kanji, draft.Reading, err = pickForm(entry, strings.TrimSpace(options.Kanji), strings.TrimSpace(options.Reading))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case options.Sense == nil:
+		for i, sense := range entry.Senses {
+			if sense.AppliesTo(draft.Kanji, draft.Reading) {
+				draft.Sense = i
+				break
+			}
+		}
+	case *options.Sense < 0 || *options.Sense >= len(entry.Senses):
+		return nil, fmt.Errorf("sense must be between 0 and %d", len(entry.Senses)-1)
+	default:
+		draft.Sense = *options.Sense
+	}
+	draft.English = strings.TrimSpace(options.English)
+	if draft.English == "" {
+		glosses := entry.Senses[draft.Sense].Glosses
+		draft.English = strings.Join(glosses[:min(len(glosses), maxDraftGlosses)], "; ")
+	}
+
+	readings, err := d.kanjiReadings(ctx, draft.Kanji)
+	if err != nil {
+		return nil, err
+	}
+	draft.Romaji, draft.Parts, err = splitParts(draft.Kanji, draft.Reading, readings)
+	if err != nil {
+		return nil, err
+	}
+	draft.normalize()
+	if err := draft.validate(); err != nil {
+		return nil, fmt.Errorf("entry cannot be made into a word: %v", err)
+	}
+
+	id, err := d.wordRepo.FindID(ctx, draft.Kanji, draft.Romaji, draft.English)
+	switch {
+	case err == nil:
+		draft.ExistingWordID = &id
+	case err != sql.ErrNoRows:
+		return nil, err
+	}
+	return draft, nil
+}
+
+// Create drafts a word from a dictionary entry and saves it
+func (d *Drafter) Create(ctx context.Context, entryID int64, options DraftOptions) (*models.Word, error) {
+	draft, err := d.Draft(ctx, entryID, options)
+	if err != nil {
+		return nil, err
+	}
+	if draft.ExistingWordID != nil {
+		return nil, fmt.Errorf("word already exists with id %d", *draft.ExistingWordID)
+	}
+
+	parts, err := json.Marshal(draft.Parts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode parts: %w", err)
+	}
+	word := &models.Word{Kanji: draft.Kanji, Romaji: draft.Romaji, English: draft.English, Parts: parts}
+	if err := d.wordRepo.Create(ctx, word); err != nil {
+		return nil, err
+	}
+	return word, nil
+}
+
+// pickForm returns the written form and reading of a word drafted from entry: kanji is
+// a form or a reading of the entry and reading one of the readings belonging to it
+func pickForm(entry *models.DictionaryEntry, kanji, reading string) (string, string, error) {
+	isReading := func(text string) bool {
+		for _, r := range entry.Readings {
+			if r.Text == text {
+				return true
+			}
+		}
+		return false
+	}
+
+	if reading != "" && !isReading(reading) {
+		return "", "", fmt.Errorf("reading must be a reading of the entry")
+	}
+	if kanji == "" {
+		usuallyKana := false
+		for _, misc := range entry.Senses[0].Misc {
+			usuallyKana = usuallyKana || misc == dictionary.UsuallyKana
+		}
+		switch {
+		case (len(entry.Kanji) == 0 || usuallyKana) && reading != "":
+			kanji = reading
+		case len(entry.Kanji) == 0 || usuallyKana:
+			kanji = entry.Readings[0].Text
+		default:
+			// The first form the reading belongs to
+			kanji = entry.Kanji[0].Text
+			for i := len(entry.Kanji) - 1; i >= 0 && reading != ""; i-- {
+				for _, r := range entry.Readings {
+					if r.Text == reading && r.BelongsTo(entry.Kanji[i].Text) {
+						kanji = entry.Kanji[i].Text
+					}
+				}
+			}
+		}
+	}
+
+	// A word written in kana is read as written
+	if isReading(kanji) {
+		if reading != "" && reading != kanji {
+			return "", "", fmt.Errorf("reading must be %s for a word written in kana", kanji)
+		}
+		return kanji, kanji, nil
+	}
+	found := false
+	for _, k := range entry.Kanji {
+		found = found || k.Text == kanji
+	}
+	if !found {
+		return "", "", fmt.Errorf("kanji must be a form or reading of the entry")
+	}
+
+	for _, r := range entry.Readings {
+		if !r.BelongsTo(kanji) {
+			continue
+		}
+		if reading == "" || reading == r.Text {
+			return kanji, r.Text, nil
+		}
+	}
+	if reading != "" {
+		return "", "", fmt.Errorf("reading must be a reading of %s", kanji)
+	}
+	return "", "", fmt.Errorf("entry cannot be made into a word: %s has no reading", kanji)
+}
+
+// kanjiReadings returns the readings of the kanji of a written form as morae, along with
+// their sound changes in compounds, from the kanji dictionary
+func (d *Drafter) kanjiReadings(ctx context.Context, form string) (map[rune][][]string, error) {
+	var literals []string
+	for _, r := range form {
+		if unicode.Is(unicode.Han, r) {
+			literals = append(literals, string(r))
+		}
+	}
+	kanji, err := d.kanjiRepo.GetMany(ctx, literals)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := map[rune][][]string{}
+	for literal, k := range kanji {
+		r := []rune(literal)[0]
+		for _, reading := range append(append([]string{}, k.OnReadings...), k.KunReadings...) {
+			// Kun readings mark where the okurigana start with a dot and affixes with a dash
+			reading, _, _ = strings.Cut(strings.Trim(reading, "-"), ".")
+			for _, variant := range soundChanges(dictionary.Hiragana(reading)) {
+				readings[r] = append(readings[r], dictionary.Morae(variant))
+			}
+		}
+	}
+	return readings, nil
+}
+
+// voiced maps kana to their voiced forms, which start the second kanji of many compounds
+var voiced = map[rune]string{
+	'か': "が", 'き': "ぎ", 'く': "ぐ", 'け': "げ", 'こ': "ご",
+	'さ': "ざ", 'し': "じ", 'す': "ず", 'せ': "ぜ", 'そ': "ぞ",
+	'た': "だ", 'ち': "ぢじ", 'つ': "づず", 'て': "で", 'と': "ど",
+	'は': "ばぱ", 'ひ': "びぴ", 'ふ': "ぶぷ", 'へ': "べぺ", 'ほ': "ぼぽ",
+}
+
+// soundChanges returns a reading along with its voiced forms and the forms ending in
+// a small っ that kanji ending in つ, く, ち or き take before another
+func soundChanges(reading string) []string {
+	if reading == "" {
+		return nil
+	}
+	variants := []string{reading}
+	runes := []rune(reading)
+	for _, v := range voiced[runes[0]] {
+		variants = append(variants, string(v)+string(runes[1:]))
+	}
+	if last := runes[len(runes)-1]; len(runes) > 1 && strings.ContainsRune("つくちき", last) {
+		for _, variant := range variants {
+			v := []rune(variant)
+			variants = append(variants, string(v[:len(v)-1])+"っ")
+		}
+	}
+	return variants
+}
+
+// splitParts splits a written form into parts along its reading: kana are parts of their
+// own and each kanji takes the morae of one of its readings. Runs of kanji whose readings
+// do not fit, such as 今日, become a single part, as do all kanji missing from readings.
+// It returns the romaji of the reading along with the parts.
+func splitParts(form, reading string, readings map[rune][][]string) (string, []repository.WordPart, error) {
+	morae := dictionary.Morae(dictionary.Hiragana(reading))
+	syllables, ok := dictionary.Romaji(morae)
+	if !ok {
+		return "", nil, fmt.Errorf("entry cannot be made into a word: %s is not written in kana", reading)
+	}
+	a := &aligner{tokens: tokenize(form), morae: morae, syllables: syllables, readings: readings}
+	parts, ok := a.align(0, 0, true)
+	if !ok {
+		parts, ok = a.align(0, 0, false)
+	}
+	if !ok {
+		return "", nil, fmt.Errorf("entry cannot be made into a word: %s does not match its reading %s", form, reading)
+	}
+	return strings.Join(syllables, ""), parts, nil
+}
+
+// formToken is a kana mora or a run of kanji of a written form
+type formToken struct {
+	text  string
+	kanji bool
+}
+
+// tokenize splits a written form into runs of kanji and kana morae
+func tokenize(form string) []formToken {
+	var tokens []formToken
+	for _, r := range form {
+		isKanji := unicode.Is(unicode.Han, r) || r == '々'
+		last := len(tokens) - 1
+		switch {
+		case last >= 0 && isKanji && tokens[last].kanji:
+			tokens[last].text += string(r)
+		case last >= 0 && !isKanji && !tokens[last].kanji && len(dictionary.Morae(tokens[last].text+string(r))) == 1:
+			// Small kana join the mora before them
+			tokens[last].text += string(r)
+		default:
+			tokens = append(tokens, formToken{text: string(r), kanji: isKanji})
+		}
+	}
+	return tokens
+}
+
+// aligner matches the tokens of a written form to the morae of its reading
+type aligner struct {
+	tokens    []formToken
+	morae     []string
+	syllables []string
+	readings  map[rune][][]string
+}
+
+// part returns the part of text read as morae i to j
+func (a *aligner) part(text string, i, j int) repository.WordPart {
+	return repository.WordPart{Kanji: text, Romaji: append([]string{}, a.syllables[i:j]...)}
+}
+
+// align matches the tokens from i to the morae from j; strict requires every kanji to
+// take the morae of one of its readings
+func (a *aligner) align(i, j int, strict bool) ([]repository.WordPart, bool) {
+	if i == len(a.tokens) {
+		return nil, j == len(a.morae)
+	}
+	token := a.tokens[i]
+	if !token.kanji {
+		if j == len(a.morae) || dictionary.Hiragana(token.text) != a.morae[j] {
+			return nil, false
+		}
+		rest, ok := a.align(i+1, j+1, strict)
+		return append([]repository.WordPart{a.part(token.text, j, j+1)}, rest...), ok
+	}
+
+	// Try the shortest readings of the run first, as kana following it anchor the rest
+	for end := j + 1; end <= len(a.morae); end++ {
+		parts, ok := a.split([]rune(token.text), j, end, 0)
+		if !ok {
+			if strict {
+				continue
+			}
+			parts = []repository.WordPart{a.part(token.text, j, end)}
+		}
+		if rest, ok := a.align(i+1, end, strict); ok {
+			return append(parts, rest...), true
+		}
+	}
+	return nil, false
+}
+
+// split gives each kanji of run from k one of its readings within the morae from j to end;
+// 々 repeats the kanji before it
+func (a *aligner) split(run []rune, j, end, k int) ([]repository.WordPart, bool) {
+	if k == len(run) {
+		return nil, j == end
+	}
+	literal := run[k]
+	if literal == '々' && k > 0 {
+		literal = run[k-1]
+	}
+	for _, reading := range a.readings[literal] {
+		next := j + len(reading)
+		if next > end || strings.Join(a.morae[j:next], "") != strings.Join(reading, "") {
+			continue
+		}
+		if rest, ok := a.split(run, next, end, k+1); ok {
+			return append([]repository.WordPart{a.part(string(run[k]), j, next)}, rest...), true
+		}
+	}
+	return nil, false
+}
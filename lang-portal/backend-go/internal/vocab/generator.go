@@ -0,0 +1,193 @@
+package vocab
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"lang-portal/internal/llm"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// Size bounds of a generated word list
+const (
+	DefaultSize = 10
+	MaxSize     = 50
+)
+
+// MaxThemeLength bounds themes, in characters
+const MaxThemeLength = 100
+
+// systemPrompt keeps replies to the JSON the prompt asks for
+const systemPrompt = "You build Japanese vocabulary lists for English-speaking beginners. " +
+	"Reply with a JSON array only, without any other text."
+
+// instructions asks for a themed word list in the shape of the seed files;
+// %[1]q is the theme and %[2]d the number of words
+const instructions = `For the theme %[1]q, generate %[2]d Japanese vocabulary words as a JSON array. Each word has this structure:
+{
+  "kanji": "the word in Japanese script",
+  "romaji": "its reading in lowercase romaji",
+  "english": "its English meaning",
+  "parts": [
+    { "kanji": "one character of the word", "romaji": ["the romaji syllables of that character"] }
+  ]
+}
+The parts spell the whole word in order and their syllables spell the romaji, for example:
+{"kanji": "払う", "romaji": "harau", "english": "to pay", "parts": [{"kanji": "払", "romaji": ["ha", "ra"]}, {"kanji": "う", "romaji": ["u"]}]}`
+
+// PreviewWord is a valid generated word; ExistingWordID is set when the word is
+// already in the portal and would be reused instead of created
+type PreviewWord struct {
+	Word
+	ExistingWordID *int64 `json:"existing_word_id,omitempty"`
+}
+
+// Rejected is a generated word that failed validation, as the model wrote it
+type Rejected struct {
+	Index  int             `json:"index"`
+	Word   json.RawMessage `json:"word"`
+	Reason string          `json:"reason"`
+}
+
+// Preview is a generated word list to review before a group is created from it
+type Preview struct {
+	Theme string `json:"theme"`
+	// Name is the suggested group name
+	Name     string        `json:"name"`
+	Model    string        `json:"model"`
+	Words    []PreviewWord `json:"words"`
+	Rejected []Rejected    `json:"rejected"`
+}
+
+// Generator asks a language model for themed word lists and turns confirmed lists into groups
+type Generator struct {
+	provider  llm.Provider
+	wordRepo  repository.WordRepository
+	groupRepo repository.GroupRepository
+}
+
+// NewGenerator creates a generator asking provider for words
+func NewGenerator(provider llm.Provider, wordRepo repository.WordRepository, groupRepo repository.GroupRepository) *Generator {
+	return &Generator{provider: provider, wordRepo: wordRepo, groupRepo: groupRepo}
+}
+
+// Preview generates up to size words about theme without saving anything
+func (g *Generator) Preview(ctx context.Context, theme string, size int) (*Preview, error) {
+	theme = strings.TrimSpace(theme)
+	if size == 0 {
+		size = DefaultSize
+	}
+	if theme == "" || utf8.RuneCountInString(theme) > MaxThemeLength {
+		return nil, fmt.Errorf("theme is required and must be at most %d characters", MaxThemeLength)
+	}
+	if size < 1 || size > MaxSize {
+		return nil, fmt.Errorf("size must be between 1 and %d", MaxSize)
+	}
+
+	reply, err := g.provider.Complete(ctx, []llm.Message{
+		{Role: llm.RoleSystem, Content: systemPrompt},
+		{Role: llm.RoleUser, Content: fmt.Sprintf(instructions, theme, size)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	items, err := parseReply(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &Preview{
+		Theme:    theme,
+		Name:     theme,
+		Model:    g.provider.Model(),
+		Words:    []PreviewWord{},
+		Rejected: []Rejected{},
+	}
+	seen := map[string]int{}
+	for i, item := range items {
+		reject := func(reason string) {
+			preview.Rejected = append(preview.Rejected, Rejected{Index: i + 1, Word: item, Reason: reason})
+		}
+		if len(preview.Words) == size {
+			reject("more words than asked for")
+			continue
+		}
+
+		var word Word
+		if err := json.Unmarshal(item, &word); err != nil {
+			reject("not a word object")
+			continue
+		}
+		word.normalize()
+		if err := word.validate(); err != nil {
+			reject(err.Error())
+			continue
+		}
+		key := word.Kanji + "\x00" + word.English
+		if first, ok := seen[key]; ok {
+			reject(fmt.Sprintf("duplicate of word %d", first))
+			continue
+		}
+		seen[key] = i + 1
+
+		previewed := PreviewWord{Word: word}
+		id, err := g.wordRepo.FindID(ctx, word.Kanji, word.Romaji, word.English)
+		switch {
+		case err == nil:
+			previewed.ExistingWordID = &id
+		case err != sql.ErrNoRows:
+			return nil, err
+		}
+		preview.Words = append(preview.Words, previewed)
+	}
+	return preview, nil
+}
+
+// Confirm validates a reviewed word list again and creates a group with its words in one
+// transaction, reusing existing words; it returns the group and how many words were created
+func (g *Generator) Confirm(ctx context.Context, name string, words []Word) (*repository.GroupDetails, int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, 0, fmt.Errorf("name is required")
+	}
+	if len(words) == 0 || len(words) > MaxSize {
+		return nil, 0, fmt.Errorf("words must contain between 1 and %d words", MaxSize)
+	}
+
+	rows := make([]models.Word, 0, len(words))
+	for i := range words {
+		word := words[i]
+		word.normalize()
+		if err := word.validate(); err != nil {
+			return nil, 0, fmt.Errorf("words: word %d: %v", i+1, err)
+		}
+		parts, err := json.Marshal(word.Parts)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode parts: %w", err)
+		}
+		rows = append(rows, models.Word{Kanji: word.Kanji, Romaji: word.Romaji, English: word.English, Parts: parts})
+	}
+	return g.groupRepo.CreateWithWords(ctx, name, rows)
+}
+
+// parseReply extracts the JSON array of a reply, which models often wrap in a code block
+// or a sentence, or nest in an object
+func parseReply(reply string) ([]json.RawMessage, error) {
+	start, end := strings.Index(reply, "["), strings.LastIndex(reply, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("%w: no JSON array of words", llm.ErrInvalidReply)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &items); err != nil {
+		return nil, fmt.Errorf("%w: %v", llm.ErrInvalidReply, err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no words", llm.ErrInvalidReply)
+	}
+	return items, nil
+}
@@ -0,0 +1,261 @@
+package vocab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/events"
+	"lang-portal/internal/llm"
+	"lang-portal/internal/repository"
+)
+
+// Words of the replies in the tests, in the shape the prompt asks for
+const (
+	harau  = `{"kanji": "払う", "romaji": "harau", "english": "to pay", "parts": [{"kanji": "払", "romaji": ["ha", "ra"]}, {"kanji": "う", "romaji": ["u"]}]}`
+	taberu = `{"kanji": "食べる", "romaji": "taberu", "english": "to eat", "parts": [{"kanji": "食", "romaji": ["ta"]}, {"kanji": "べ", "romaji": ["be"]}, {"kanji": "る", "romaji": ["ru"]}]}`
+	nomu   = `{"kanji": "飲む", "romaji": "nomu", "english": "to drink", "parts": [{"kanji": "飲", "romaji": ["no"]}, {"kanji": "む", "romaji": ["mu"]}]}`
+	miru   = `{"kanji": "見る", "romaji": "miru", "english": "to see", "parts": [{"kanji": "見", "romaji": ["mi"]}, {"kanji": "る", "romaji": ["ru"]}]}`
+)
+
+// newTestGenerator creates a generator replying with replies over a database with the
+// schema of the migrations and no words
+func newTestGenerator(t *testing.T, replies ...string) (*Generator, *llm.Fake, *database.Database) {
+	t.Helper()
+	db, err := database.CreateDatabase(database.DatabaseConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range migrations {
+		migration, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.DB.Exec(string(migration)); err != nil {
+			t.Fatalf("migration %s: %v", filepath.Base(path), err)
+		}
+	}
+
+	fake := llm.NewFake(replies...)
+	generator := NewGenerator(fake, repository.NewWordRepository(db.DB, events.NewBus()), repository.NewGroupRepository(db.DB))
+	return generator, fake, db
+}
+
+// insertWord stores a word given in the shape of the replies and returns its ID
+func insertWord(t *testing.T, db *database.Database, word string) int64 {
+	t.Helper()
+	var w Word
+	if err := json.Unmarshal([]byte(word), &w); err != nil {
+		t.Fatal(err)
+	}
+	parts, err := json.Marshal(w.Parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := db.DB.Exec(`INSERT INTO words (kanji, romaji, english, parts) VALUES (?, ?, ?, ?)`,
+		w.Kanji, w.Romaji, w.English, string(parts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		words int
+	}{
+		{"bare array", "[" + harau + ", " + taberu + "]", 2},
+		{"fenced", "```json\n[" + harau + "]\n```", 1},
+		{"wrapped in sentences", "Here are the words you asked for: [" + harau + ", " + nomu + "] Enjoy studying!", 2},
+		{"nested in an object", `{"words": [` + harau + `]}`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := parseReply(tt.reply)
+			if err != nil {
+				t.Fatalf("parseReply: %v", err)
+			}
+			if len(items) != tt.words {
+				t.Errorf("got %d words, want %d", len(items), tt.words)
+			}
+		})
+	}
+
+	for _, reply := range []string{"I cannot help with that.", "[]", "[" + harau + ","} {
+		if _, err := parseReply(reply); !errors.Is(err, llm.ErrInvalidReply) {
+			t.Errorf("parseReply(%q) = %v, want ErrInvalidReply", reply, err)
+		}
+	}
+}
+
+func TestWordValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+		err  string
+	}{
+		{"valid", harau, ""},
+		{"spaced reading", `{"kanji": "払う", "romaji": "Ha rau", "english": "to pay", "parts": [{"kanji": "払", "romaji": ["ha", "ra"]}, {"kanji": "う", "romaji": ["u"]}]}`, ""},
+		{"missing syllable", `{"kanji": "払う", "romaji": "harau", "english": "to pay", "parts": [{"kanji": "払", "romaji": ["ha"]}, {"kanji": "う", "romaji": ["u"]}]}`, "parts read hau instead of harau"},
+		{"extra syllable", `{"kanji": "飲む", "romaji": "nomu", "english": "to drink", "parts": [{"kanji": "飲", "romaji": ["no", "n"]}, {"kanji": "む", "romaji": ["mu"]}]}`, "parts read nonmu instead of nomu"},
+		{"missing part", `{"kanji": "払う", "romaji": "harau", "english": "to pay", "parts": [{"kanji": "払", "romaji": ["ha", "ra", "u"]}]}`, "parts spell 払 instead of 払う"},
+		{"latin kanji", `{"kanji": "harau", "romaji": "harau", "english": "to pay", "parts": [{"kanji": "harau", "romaji": ["harau"]}]}`, "kanji must only contain Japanese characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w Word
+			if err := json.Unmarshal([]byte(tt.word), &w); err != nil {
+				t.Fatal(err)
+			}
+			w.normalize()
+			err := w.validate()
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("validate: %v", err)
+			case tt.err != "" && (err == nil || err.Error() != tt.err):
+				t.Errorf("validate = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	reply := "```json\n[" + strings.Join([]string{harau, `"払う"`, harau, taberu, miru}, ",\n") + "]\n```"
+	generator, fake, db := newTestGenerator(t, reply)
+	existing := insertWord(t, db, taberu)
+
+	preview, err := generator.Preview(context.Background(), "  shopping ", 2)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	if preview.Theme != "shopping" || preview.Name != "shopping" || preview.Model != "fake" {
+		t.Errorf("preview is %q named %q by %q", preview.Theme, preview.Name, preview.Model)
+	}
+
+	if len(preview.Words) != 2 {
+		t.Fatalf("got %d words, want 2", len(preview.Words))
+	}
+	if w := preview.Words[0]; w.Kanji != "払う" || w.ExistingWordID != nil {
+		t.Errorf("first word is %s, existing %v", w.Kanji, w.ExistingWordID)
+	}
+	if w := preview.Words[1]; w.Kanji != "食べる" || w.ExistingWordID == nil || *w.ExistingWordID != existing {
+		t.Errorf("second word is %s, existing %v, want existing word %d", w.Kanji, w.ExistingWordID, existing)
+	}
+
+	want := []Rejected{
+		{Index: 2, Reason: "not a word object"},
+		{Index: 3, Reason: "duplicate of word 1"},
+		{Index: 5, Reason: "more words than asked for"},
+	}
+	if len(preview.Rejected) != len(want) {
+		t.Fatalf("got rejected %+v, want %d", preview.Rejected, len(want))
+	}
+	for i, r := range preview.Rejected {
+		if r.Index != want[i].Index || r.Reason != want[i].Reason {
+			t.Errorf("rejected %d is word %d for %q, want word %d for %q", i, r.Index, r.Reason, want[i].Index, want[i].Reason)
+		}
+	}
+
+	requests := fake.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("sent %d requests, want 1", len(requests))
+	}
+	if prompt := requests[0][len(requests[0])-1].Content; !strings.Contains(prompt, `"shopping"`) || !strings.Contains(prompt, "generate 2 ") {
+		t.Errorf("prompt does not ask for 2 words about shopping: %s", prompt)
+	}
+}
+
+func TestPreviewRejectsOversizeGroups(t *testing.T) {
+	generator, fake, _ := newTestGenerator(t, "["+harau+"]")
+	for _, size := range []int{-1, MaxSize + 1} {
+		if _, err := generator.Preview(context.Background(), "shopping", size); err == nil || !strings.HasPrefix(err.Error(), "size ") {
+			t.Errorf("Preview of %d words = %v, want a size error", size, err)
+		}
+	}
+	if _, err := generator.Preview(context.Background(), strings.Repeat("a", MaxThemeLength+1), 1); err == nil || !strings.HasPrefix(err.Error(), "theme ") {
+		t.Errorf("Preview of a long theme = %v, want a theme error", err)
+	}
+	if n := len(fake.Requests()); n != 0 {
+		t.Errorf("sent %d requests for invalid previews", n)
+	}
+}
+
+func TestPreviewInvalidReply(t *testing.T) {
+	generator, _, _ := newTestGenerator(t, "Sorry, I only speak English.")
+	if _, err := generator.Preview(context.Background(), "shopping", 1); !errors.Is(err, llm.ErrInvalidReply) {
+		t.Errorf("Preview = %v, want ErrInvalidReply", err)
+	}
+}
+
+func TestConfirmReusesExistingWords(t *testing.T) {
+	generator, _, db := newTestGenerator(t)
+	existing := insertWord(t, db, taberu)
+
+	var words []Word
+	if err := json.Unmarshal([]byte("["+taberu+","+nomu+"]"), &words); err != nil {
+		t.Fatal(err)
+	}
+	group, created, err := generator.Confirm(context.Background(), " Food ", words)
+	if err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	if group.Name != "Food" || group.TotalWordCount != 2 {
+		t.Errorf("group %q has %d words, want Food with 2", group.Name, group.TotalWordCount)
+	}
+	if created != 1 {
+		t.Errorf("created %d words, want 1", created)
+	}
+
+	var count int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM words`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("%d words stored, want 2", count)
+	}
+	var linked int
+	err = db.DB.QueryRow(`SELECT COUNT(*) FROM word_groups WHERE group_id = ? AND word_id = ?`, group.ID, existing).Scan(&linked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linked != 1 {
+		t.Errorf("existing word %d is not in the group", existing)
+	}
+}
+
+func TestConfirmRejectsInvalidWords(t *testing.T) {
+	generator, _, db := newTestGenerator(t)
+	words := []Word{
+		{Kanji: "飲む", Romaji: "nomu", English: "to drink", Parts: []repository.WordPart{{Kanji: "飲", Romaji: []string{"no"}}, {Kanji: "む", Romaji: []string{"mu"}}}},
+		{Kanji: "払う", Romaji: "harau", English: "to pay", Parts: []repository.WordPart{{Kanji: "払う", Romaji: []string{"ha", "u"}}}},
+	}
+	_, _, err := generator.Confirm(context.Background(), "Food", words)
+	if err == nil || err.Error() != "words: word 2: parts read hau instead of harau" {
+		t.Errorf("Confirm = %v, want word 2 rejected", err)
+	}
+
+	var groups int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM groups`).Scan(&groups); err != nil {
+		t.Fatal(err)
+	}
+	if groups != 0 {
+		t.Errorf("%d groups created from invalid words", groups)
+	}
+}
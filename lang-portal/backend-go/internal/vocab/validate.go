@@ -0,0 +1,91 @@
+package vocab
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"lang-portal/internal/repository"
+)
+
+// Word is a generated word, in the shape of the seed files
+type Word struct {
+	Kanji   string                `json:"kanji"`
+	Romaji  string                `json:"romaji"`
+	English string                `json:"english"`
+	Parts   []repository.WordPart `json:"parts"`
+}
+
+// normalize trims the fields of a word and lowercases its romaji
+func (w *Word) normalize() {
+	w.Kanji = strings.TrimSpace(w.Kanji)
+	w.Romaji = strings.ToLower(strings.TrimSpace(w.Romaji))
+	w.English = strings.TrimSpace(w.English)
+	for i := range w.Parts {
+		w.Parts[i].Kanji = strings.TrimSpace(w.Parts[i].Kanji)
+		for j := range w.Parts[i].Romaji {
+			w.Parts[i].Romaji[j] = strings.ToLower(strings.TrimSpace(w.Parts[i].Romaji[j]))
+		}
+	}
+}
+
+// validate checks a normalized word against the schema of the words table: the parts
+// spell the word in order and their romaji syllables spell its reading
+func (w *Word) validate() error {
+	switch {
+	case w.Kanji == "":
+		return fmt.Errorf("kanji is required")
+	case w.Romaji == "":
+		return fmt.Errorf("romaji is required")
+	case w.English == "":
+		return fmt.Errorf("english is required")
+	case len(w.Parts) == 0:
+		return fmt.Errorf("parts are required")
+	}
+	for _, r := range w.Kanji {
+		if !isJapanese(r) {
+			return fmt.Errorf("kanji must only contain Japanese characters")
+		}
+	}
+	for _, r := range w.Romaji {
+		if !isRomaji(r) {
+			return fmt.Errorf("romaji must only contain latin letters")
+		}
+	}
+
+	var kanji, romaji strings.Builder
+	for i, part := range w.Parts {
+		if part.Kanji == "" || len(part.Romaji) == 0 {
+			return fmt.Errorf("part %d needs kanji and romaji", i+1)
+		}
+		kanji.WriteString(part.Kanji)
+		for _, syllable := range part.Romaji {
+			if syllable == "" {
+				return fmt.Errorf("part %d has an empty romaji syllable", i+1)
+			}
+			romaji.WriteString(syllable)
+		}
+	}
+	if kanji.String() != w.Kanji {
+		return fmt.Errorf("parts spell %s instead of %s", kanji.String(), w.Kanji)
+	}
+	if compact(romaji.String()) != compact(w.Romaji) {
+		return fmt.Errorf("parts read %s instead of %s", romaji.String(), w.Romaji)
+	}
+	return nil
+}
+
+// isJapanese reports whether r is a kanji, kana or the long vowel mark
+func isJapanese(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー' || r == '々'
+}
+
+// isRomaji reports whether r can appear in a romaji reading
+func isRomaji(r rune) bool {
+	return (r >= 'a' && r <= 'z') || strings.ContainsRune(" '-āīūēō", r)
+}
+
+// compact drops the separators readings are sometimes written with
+func compact(romaji string) string {
+	return strings.NewReplacer(" ", "", "'", "", "-", "").Replace(romaji)
+}
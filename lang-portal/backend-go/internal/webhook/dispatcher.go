@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"lang-portal/internal/events"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// Dispatcher queues events for subscribed webhooks and delivers them,
+// retrying failed deliveries with exponential backoff
+type Dispatcher struct {
+	repo         repository.WebhookRepository
+	client       *http.Client
+	maxAttempts  int
+	retryBase    time.Duration
+	pollInterval time.Duration
+	batchSize    int
+	wake         chan struct{}
+}
+
+// maxRetryDelay caps the backoff between attempts
+const maxRetryDelay = 6 * time.Hour
+
+// NewDispatcher creates a dispatcher giving up on a delivery after maxAttempts,
+// waiting retryBase, then twice as long after each failed attempt
+func NewDispatcher(repo repository.WebhookRepository, maxAttempts int, retryBase time.Duration) *Dispatcher {
+	return &Dispatcher{
+		repo:         repo,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		maxAttempts:  maxAttempts,
+		retryBase:    retryBase,
+		pollInterval: 5 * time.Second,
+		batchSize:    20,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Enqueue is an events.Handler storing a delivery of the event for every subscription to it.
+// Events are published after the change they describe is committed, so an event is lost
+// when the server stops in between: webhooks are delivered at most once per event raised,
+// and at least once per stored delivery.
+func (d *Dispatcher) Enqueue(ctx context.Context, event events.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode %s event for webhooks: %v", event.Type, err)
+		return
+	}
+
+	queued, err := d.repo.EnqueueEvent(ctx, event.ID, event.Type, payload)
+	if err != nil {
+		log.Printf("Failed to queue %s event for webhooks: %v", event.Type, err)
+		return
+	}
+	if queued > 0 {
+		d.Wake()
+	}
+}
+
+// Wake makes the dispatcher look for due deliveries without waiting for the next poll
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due deliveries until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back; after an error, wait for the next
+		// poll so that a failing database is not hammered with the same batch
+		for d.deliverDue(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// deliverDue sends one batch of due deliveries concurrently; it reports whether more
+// may be due right away, which is when the batch was full and every attempt was recorded
+func (d *Dispatcher) deliverDue(ctx context.Context) bool {
+	due, err := d.repo.DueDeliveries(ctx, time.Now(), d.batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to fetch webhook deliveries: %v", err)
+		}
+		return false
+	}
+
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for _, delivery := range due {
+		wg.Add(1)
+		go func(delivery repository.PendingDelivery) {
+			defer wg.Done()
+			attempt := d.send(ctx, delivery)
+			if ctx.Err() != nil {
+				// Interrupted by shutdown, which is not the endpoint's fault:
+				// the delivery stays pending and is sent again after a restart
+				failed.Store(true)
+				return
+			}
+			if err := d.repo.RecordAttempt(ctx, attempt); err != nil {
+				log.Printf("Failed to record webhook delivery %d: %v", delivery.ID, err)
+				failed.Store(true)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(due) == d.batchSize && !failed.Load()
+}
+
+// send posts a delivery and decides whether it is done, retried or given up
+func (d *Dispatcher) send(ctx context.Context, delivery repository.PendingDelivery) repository.DeliveryAttempt {
+	now := time.Now().UTC()
+	attempt := repository.DeliveryAttempt{DeliveryID: delivery.ID, AttemptedAt: now}
+
+	status, err := d.post(ctx, delivery, now)
+	if status != 0 {
+		attempt.ResponseStatus = &status
+	}
+	if err == nil {
+		attempt.Status = models.DeliveryDelivered
+		return attempt
+	}
+
+	attempt.Error = err.Error()
+	if delivery.Attempts+1 >= d.maxAttempts {
+		attempt.Status = models.DeliveryFailed
+		return attempt
+	}
+
+	attempt.Status = models.DeliveryPending
+	next := now.Add(d.backoff(delivery.Attempts + 1))
+	attempt.NextAttemptAt = &next
+	return attempt
+}
+
+// post sends the signed payload, returning the response status
+func (d *Dispatcher) post(ctx context.Context, delivery repository.PendingDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LangPortal-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff is the wait after the given number of failed attempts
+func (d *Dispatcher) backoff(failures int) time.Duration {
+	delay := d.retryBase
+	for i := 1; i < failures && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-LangPortal-Event"
+	HeaderDelivery  = "X-LangPortal-Delivery"
+	HeaderTimestamp = "X-LangPortal-Timestamp"
+	HeaderSignature = "X-LangPortal-Signature"
+)
+
+// Sign computes the signature header value of a payload: "sha256=" followed by the
+// hex HMAC-SHA256 of "<timestamp>.<payload>" keyed with the subscription secret.
+// Receivers recompute it to authenticate the delivery and reject stale timestamps.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret generates a secret for a subscription created without one
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
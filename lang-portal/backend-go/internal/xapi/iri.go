@@ -0,0 +1,106 @@
+package xapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IRIs builds and parses the activity IRIs and extensions the portal uses in statements
+type IRIs struct {
+	base string
+}
+
+// NewIRIs creates IRIs rooted at the public base URL of the server
+func NewIRIs(publicBaseURL string) IRIs {
+	return IRIs{base: strings.TrimRight(publicBaseURL, "/") + "/xapi"}
+}
+
+// Word returns the activity IRI of a word
+func (i IRIs) Word(id int64) string {
+	return fmt.Sprintf("%s/activities/words/%d", i.base, id)
+}
+
+// Group returns the activity IRI of a group
+func (i IRIs) Group(id int64) string {
+	return fmt.Sprintf("%s/activities/groups/%d", i.base, id)
+}
+
+// StudyActivity returns the activity IRI of a study activity
+func (i IRIs) StudyActivity(id int64) string {
+	return fmt.Sprintf("%s/activities/study-activities/%d", i.base, id)
+}
+
+// StudySessionExtension is the context extension carrying a study session id
+func (i IRIs) StudySessionExtension() string {
+	return i.base + "/extensions/study-session-id"
+}
+
+// Learner returns the account agent used for portal learners
+func (i IRIs) Learner(name string) Agent {
+	return Agent{
+		ObjectType: "Agent",
+		Account:    &Account{HomePage: strings.TrimSuffix(i.base, "/xapi"), Name: name},
+	}
+}
+
+// Authority is the agent recorded as the authority of stored statements
+func (i IRIs) Authority() Agent {
+	return Agent{
+		ObjectType: "Agent",
+		Name:       "Lang Portal LRS",
+		Account:    &Account{HomePage: strings.TrimSuffix(i.base, "/xapi"), Name: "lrs"},
+	}
+}
+
+// ParseWord extracts the word id from a word activity IRI
+func (i IRIs) ParseWord(iri string) (int64, bool) {
+	return i.parseID(iri, "/activities/words/")
+}
+
+// ParseGroup extracts the group id from a group activity IRI
+func (i IRIs) ParseGroup(iri string) (int64, bool) {
+	return i.parseID(iri, "/activities/groups/")
+}
+
+// ParseStudyActivity extracts the study activity id from a study activity IRI
+func (i IRIs) ParseStudyActivity(iri string) (int64, bool) {
+	return i.parseID(iri, "/activities/study-activities/")
+}
+
+// StudySessionID reads the study session extension from a statement context
+func (i IRIs) StudySessionID(ctx *Context) (int64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	raw, ok := ctx.Extensions[i.StudySessionExtension()]
+	if !ok {
+		return 0, false
+	}
+
+	var id int64
+	if err := json.Unmarshal(raw, &id); err == nil && id > 0 {
+		return id, true
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if id, err := strconv.ParseInt(text, 10, 64); err == nil && id > 0 {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// parseID extracts the numeric id following prefix in an IRI rooted at the base
+func (i IRIs) parseID(iri, prefix string) (int64, bool) {
+	rest, ok := strings.CutPrefix(iri, i.base+prefix)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
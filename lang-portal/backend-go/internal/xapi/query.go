@@ -0,0 +1,98 @@
+package xapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MaxLimit caps the number of statements returned per page
+const MaxLimit = 500
+
+// StatementFilter holds the supported GET /statements filters
+type StatementFilter struct {
+	// Agent is the inverse functional identifier of the actor, see Agent.Identifier
+	Agent        string
+	Verb         string
+	Activity     string
+	Registration string
+	Since        *time.Time
+	Until        *time.Time
+	Limit        int
+	Ascending    bool
+	// Cursor continues a previous query after the given position
+	Cursor int64
+}
+
+// StatementResult is the response body of a statement query
+type StatementResult struct {
+	Statements []json.RawMessage `json:"statements"`
+	More       string            `json:"more"`
+}
+
+// Review is a portal word review to be emitted as an xAPI statement
+type Review struct {
+	ID              int64
+	WordID          int64
+	Kanji           string
+	English         string
+	StudySessionID  int64
+	GroupID         int64
+	StudyActivityID int64
+	Correct         bool
+	CreatedAt       time.Time
+	// Learner identifies the account of the learner who answered
+	Learner string
+}
+
+// ReviewStatement renders a portal review as an "answered" statement with a stable id
+func (i IRIs) ReviewStatement(review Review) (json.RawMessage, error) {
+	correct := review.Correct
+	timestamp := review.CreatedAt.UTC().Format(time.RFC3339Nano)
+	authority := i.Authority()
+	sessionID, err := json.Marshal(review.StudySessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	definition, err := json.Marshal(map[string]interface{}{
+		"name":        map[string]string{"ja-JP": review.Kanji},
+		"description": map[string]string{"en-US": review.English},
+		"type":        "http://adlnet.gov/expapi/activities/cmi.interaction",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statement := Statement{
+		ID:    NameUUID(fmt.Sprintf("word_review_item:%d", review.ID)),
+		Actor: i.Learner(review.Learner),
+		Verb: Verb{
+			ID:      VerbAnswered,
+			Display: map[string]string{"en-US": "answered"},
+		},
+		Object: Object{
+			ObjectType: "Activity",
+			ID:         i.Word(review.WordID),
+			Definition: definition,
+		},
+		Result: &Result{Success: &correct},
+		Context: &Context{
+			ContextActivities: &ContextActivities{
+				Grouping: []Object{
+					{ObjectType: "Activity", ID: i.Group(review.GroupID)},
+					{ObjectType: "Activity", ID: i.StudyActivity(review.StudyActivityID)},
+				},
+			},
+			Extensions: map[string]json.RawMessage{
+				i.StudySessionExtension(): sessionID,
+			},
+		},
+		Timestamp: timestamp,
+		Stored:    timestamp,
+		Authority: &authority,
+		Version:   "1.0.0",
+	}
+
+	return json.Marshal(statement)
+}
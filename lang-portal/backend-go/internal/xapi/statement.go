@@ -0,0 +1,250 @@
+package xapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Version is the xAPI version implemented by the LRS
+const Version = "1.0.3"
+
+// Verbs understood by the portal
+const (
+	VerbAnswered  = "http://adlnet.gov/expapi/verbs/answered"
+	VerbCompleted = "http://adlnet.gov/expapi/verbs/completed"
+	VerbVoided    = "http://adlnet.gov/expapi/verbs/voided"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Account identifies an agent by an account on a system
+type Account struct {
+	HomePage string `json:"homePage"`
+	Name     string `json:"name"`
+}
+
+// Agent is the actor or authority of a statement
+type Agent struct {
+	ObjectType  string   `json:"objectType,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Mbox        string   `json:"mbox,omitempty"`
+	MboxSHA1Sum string   `json:"mbox_sha1sum,omitempty"`
+	OpenID      string   `json:"openid,omitempty"`
+	Account     *Account `json:"account,omitempty"`
+	Member      []Agent  `json:"member,omitempty"`
+}
+
+// Identifier returns the inverse functional identifier of the agent, used for filtering
+func (a Agent) Identifier() string {
+	switch {
+	case a.Mbox != "":
+		return "mbox:" + a.Mbox
+	case a.MboxSHA1Sum != "":
+		return "mbox_sha1sum:" + a.MboxSHA1Sum
+	case a.OpenID != "":
+		return "openid:" + a.OpenID
+	case a.Account != nil && a.Account.HomePage != "" && a.Account.Name != "":
+		return "account:" + a.Account.HomePage + "|" + a.Account.Name
+	}
+	return ""
+}
+
+// Verb describes the action of a statement
+type Verb struct {
+	ID      string            `json:"id"`
+	Display map[string]string `json:"display,omitempty"`
+}
+
+// Object is the target of a statement: an Activity, Agent, Group or StatementRef
+type Object struct {
+	ObjectType string          `json:"objectType,omitempty"`
+	ID         string          `json:"id,omitempty"`
+	Definition json.RawMessage `json:"definition,omitempty"`
+}
+
+// Result is the outcome of a statement
+type Result struct {
+	Success    *bool  `json:"success,omitempty"`
+	Completion *bool  `json:"completion,omitempty"`
+	Response   string `json:"response,omitempty"`
+	Duration   string `json:"duration,omitempty"`
+}
+
+// ContextActivities relates a statement to other activities
+type ContextActivities struct {
+	Parent   []Object `json:"parent,omitempty"`
+	Grouping []Object `json:"grouping,omitempty"`
+	Category []Object `json:"category,omitempty"`
+	Other    []Object `json:"other,omitempty"`
+}
+
+// Context gives the circumstances of a statement
+type Context struct {
+	Registration      string                     `json:"registration,omitempty"`
+	ContextActivities *ContextActivities         `json:"contextActivities,omitempty"`
+	Extensions        map[string]json.RawMessage `json:"extensions,omitempty"`
+}
+
+// Statement is the typed view of an xAPI statement used for validation and mapping.
+// The original JSON is kept alongside it so unknown properties are preserved.
+type Statement struct {
+	ID        string   `json:"id,omitempty"`
+	Actor     Agent    `json:"actor"`
+	Verb      Verb     `json:"verb"`
+	Object    Object   `json:"object"`
+	Result    *Result  `json:"result,omitempty"`
+	Context   *Context `json:"context,omitempty"`
+	Timestamp string   `json:"timestamp,omitempty"`
+	Stored    string   `json:"stored,omitempty"`
+	Authority *Agent   `json:"authority,omitempty"`
+	Version   string   `json:"version,omitempty"`
+}
+
+// StoredStatement is a validated statement ready to be persisted
+type StoredStatement struct {
+	Statement
+	// Raw is the full statement JSON including the server-assigned properties
+	Raw json.RawMessage
+	// Original is the statement as submitted, used to detect conflicting resubmissions
+	Original json.RawMessage
+	// TimestampTime and StoredTime are the parsed timestamp and stored properties
+	TimestampTime time.Time
+	StoredTime    time.Time
+}
+
+// IsVoiding reports whether the statement voids another statement
+func (s Statement) IsVoiding() bool {
+	return s.Verb.ID == VerbVoided
+}
+
+// ParseStatements decodes a single statement or an array of statements
+func ParseStatements(body []byte) ([]json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("request body is empty")
+	}
+
+	if trimmed[0] == '[' {
+		var statements []json.RawMessage
+		if err := json.Unmarshal(trimmed, &statements); err != nil {
+			return nil, fmt.Errorf("invalid statement array: %w", err)
+		}
+		return statements, nil
+	}
+
+	return []json.RawMessage{trimmed}, nil
+}
+
+// Prepare validates a submitted statement and assigns its id, stored, timestamp,
+// authority and version properties
+func Prepare(raw json.RawMessage, authority Agent, now time.Time) (*StoredStatement, error) {
+	var properties map[string]interface{}
+	if err := json.Unmarshal(raw, &properties); err != nil {
+		return nil, fmt.Errorf("statement must be a JSON object: %w", err)
+	}
+	// stored and authority are always set by the LRS
+	delete(properties, "stored")
+	delete(properties, "authority")
+
+	var statement Statement
+	if err := json.Unmarshal(raw, &statement); err != nil {
+		return nil, fmt.Errorf("invalid statement: %w", err)
+	}
+	if err := statement.validate(); err != nil {
+		return nil, err
+	}
+
+	if statement.ID == "" {
+		statement.ID = NewUUID()
+	}
+	statement.ID = strings.ToLower(statement.ID)
+	properties["id"] = statement.ID
+
+	original, err := json.Marshal(properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode statement: %w", err)
+	}
+
+	stored := now.UTC()
+	timestamp := stored
+	if statement.Timestamp != "" {
+		timestamp, err = time.Parse(time.RFC3339Nano, statement.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("timestamp must be an ISO 8601 date: %w", err)
+		}
+	} else {
+		statement.Timestamp = timestamp.Format(time.RFC3339Nano)
+		properties["timestamp"] = statement.Timestamp
+	}
+	if statement.Version == "" {
+		statement.Version = "1.0.0"
+		properties["version"] = statement.Version
+	}
+
+	statement.Stored = stored.Format(time.RFC3339Nano)
+	statement.Authority = &authority
+	properties["stored"] = statement.Stored
+	properties["authority"] = authority
+
+	full, err := json.Marshal(properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode statement: %w", err)
+	}
+
+	return &StoredStatement{
+		Statement:     statement,
+		Raw:           full,
+		Original:      original,
+		TimestampTime: timestamp.UTC(),
+		StoredTime:    stored,
+	}, nil
+}
+
+// validate checks the properties the LRS requires
+func (s Statement) validate() error {
+	if s.ID != "" && !uuidPattern.MatchString(s.ID) {
+		return fmt.Errorf("id must be a UUID")
+	}
+	if s.Actor.Identifier() == "" && !(s.Actor.ObjectType == "Group" && len(s.Actor.Member) > 0) {
+		return fmt.Errorf("actor must have an mbox, mbox_sha1sum, openid or account")
+	}
+	if s.Actor.Mbox != "" && !strings.HasPrefix(s.Actor.Mbox, "mailto:") {
+		return fmt.Errorf("actor mbox must be a mailto IRI")
+	}
+	if !isIRI(s.Verb.ID) {
+		return fmt.Errorf("verb id must be an IRI")
+	}
+
+	switch s.Object.ObjectType {
+	case "", "Activity":
+		if !isIRI(s.Object.ID) {
+			return fmt.Errorf("activity object id must be an IRI")
+		}
+	case "StatementRef":
+		if !uuidPattern.MatchString(s.Object.ID) {
+			return fmt.Errorf("statement reference id must be a UUID")
+		}
+	case "Agent", "Group", "SubStatement":
+	default:
+		return fmt.Errorf("unsupported object type: %s", s.Object.ObjectType)
+	}
+
+	if s.IsVoiding() && s.Object.ObjectType != "StatementRef" {
+		return fmt.Errorf("voiding statements must reference a statement")
+	}
+	if s.Context != nil && s.Context.Registration != "" && !uuidPattern.MatchString(s.Context.Registration) {
+		return fmt.Errorf("context registration must be a UUID")
+	}
+
+	return nil
+}
+
+// isIRI performs a light check that a value is an absolute IRI
+func isIRI(value string) bool {
+	scheme, rest, ok := strings.Cut(value, ":")
+	return ok && scheme != "" && rest != "" && !strings.ContainsAny(scheme, "/ ")
+}
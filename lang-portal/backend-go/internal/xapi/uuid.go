@@ -0,0 +1,38 @@
+package xapi
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"fmt"
+)
+
+// namespace is the UUID namespace for statements derived from portal data
+var namespace = [16]byte{0x6c, 0x61, 0x6e, 0x67, 0x2d, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2d, 0x78, 0x61, 0x70, 0x69}
+
+// NewUUID returns a random (version 4) UUID
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("xapi: failed to read random bytes: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUUID(b)
+}
+
+// NameUUID returns a name-based (version 5) UUID so derived statements keep stable ids
+func NameUUID(name string) string {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+	var b [16]byte
+	copy(b[:], h.Sum(nil))
+	b[6] = (b[6] & 0x0f) | 0x50
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUUID(b)
+}
+
+// formatUUID renders 16 bytes in the canonical 8-4-4-4-12 form
+func formatUUID(b [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,24 @@
+-- Keep groups.words_count in sync with word_groups
+CREATE TRIGGER IF NOT EXISTS trg_word_groups_insert_count
+AFTER INSERT ON word_groups
+BEGIN
+    UPDATE groups SET words_count = words_count + 1 WHERE id = NEW.group_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_word_groups_delete_count
+AFTER DELETE ON word_groups
+BEGIN
+    UPDATE groups SET words_count = words_count - 1 WHERE id = OLD.group_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_word_groups_update_count
+AFTER UPDATE OF group_id ON word_groups
+WHEN NEW.group_id <> OLD.group_id
+BEGIN
+    UPDATE groups SET words_count = words_count - 1 WHERE id = OLD.group_id;
+    UPDATE groups SET words_count = words_count + 1 WHERE id = NEW.group_id;
+END;
+
+-- Recompute counts that drifted before the triggers existed
+UPDATE groups
+SET words_count = (SELECT COUNT(*) FROM word_groups wg WHERE wg.group_id = groups.id);
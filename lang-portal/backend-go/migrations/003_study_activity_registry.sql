@@ -0,0 +1,8 @@
+-- Launchpad metadata for study activities; url remains the launch URL
+ALTER TABLE study_activities ADD COLUMN preview_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE study_activities ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE study_activities ADD COLUMN supported_modes JSON NOT NULL DEFAULT '[]';
+ALTER TABLE study_activities ADD COLUMN required_word_fields JSON NOT NULL DEFAULT '[]';
+ALTER TABLE study_activities ADD COLUMN retired_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_study_activities_retired_at ON study_activities(retired_at);
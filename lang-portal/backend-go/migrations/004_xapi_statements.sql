@@ -0,0 +1,35 @@
+-- Sessions can be marked as completed
+ALTER TABLE study_sessions ADD COLUMN completed_at TIMESTAMP;
+
+-- xAPI statements stored by the Learning Record Store
+CREATE TABLE IF NOT EXISTS xapi_statements (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    actor_ifi TEXT NOT NULL,
+    verb_id TEXT NOT NULL,
+    object_id TEXT NOT NULL,
+    registration TEXT,
+    statement JSON NOT NULL,
+    original JSON NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    stored TIMESTAMP NOT NULL,
+    voided BOOLEAN NOT NULL DEFAULT 0,
+    word_review_item_id INTEGER,
+    study_session_id INTEGER,
+    FOREIGN KEY (word_review_item_id) REFERENCES word_review_items(id) ON DELETE SET NULL,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id) ON DELETE SET NULL
+);
+
+-- Study sessions created for xAPI registrations
+CREATE TABLE IF NOT EXISTS xapi_registrations (
+    registration TEXT PRIMARY KEY,
+    study_session_id INTEGER NOT NULL,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_actor ON xapi_statements(actor_ifi);
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_verb ON xapi_statements(verb_id);
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_object ON xapi_statements(object_id);
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_registration ON xapi_statements(registration);
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_stored ON xapi_statements(stored);
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_review ON xapi_statements(word_review_item_id);
@@ -0,0 +1,74 @@
+-- Learners, so sessions can be attributed to a person
+CREATE TABLE IF NOT EXISTS learners (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE study_sessions ADD COLUMN learner_id INTEGER REFERENCES learners(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_study_sessions_learner_id ON study_sessions(learner_id);
+
+-- LTI 1.3 platforms (LMS registrations) trusted by the tool
+CREATE TABLE IF NOT EXISTS lti_platforms (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    issuer TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    deployment_id TEXT NOT NULL,
+    auth_login_url TEXT NOT NULL,
+    auth_token_url TEXT NOT NULL,
+    jwks_url TEXT NOT NULL DEFAULT '',
+    public_key_pem TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (issuer, client_id)
+);
+
+-- OIDC login state waiting for the launch
+CREATE TABLE IF NOT EXISTS lti_states (
+    state TEXT PRIMARY KEY,
+    nonce TEXT NOT NULL,
+    platform_id INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (platform_id) REFERENCES lti_platforms(id) ON DELETE CASCADE
+);
+
+-- Nonces already used in launches, to reject replays
+CREATE TABLE IF NOT EXISTS lti_nonces (
+    platform_id INTEGER NOT NULL,
+    nonce TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (platform_id, nonce),
+    FOREIGN KEY (platform_id) REFERENCES lti_platforms(id) ON DELETE CASCADE
+);
+
+-- LTI users mapped to learners
+CREATE TABLE IF NOT EXISTS lti_users (
+    platform_id INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    learner_id INTEGER NOT NULL,
+    PRIMARY KEY (platform_id, subject),
+    FOREIGN KEY (platform_id) REFERENCES lti_platforms(id) ON DELETE CASCADE,
+    FOREIGN KEY (learner_id) REFERENCES learners(id) ON DELETE CASCADE
+);
+
+-- LTI contexts (courses) mapped to groups
+CREATE TABLE IF NOT EXISTS lti_contexts (
+    platform_id INTEGER NOT NULL,
+    context_id TEXT NOT NULL,
+    group_id INTEGER NOT NULL,
+    PRIMARY KEY (platform_id, context_id),
+    FOREIGN KEY (platform_id) REFERENCES lti_platforms(id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE
+);
+
+-- Study sessions started from an LTI launch, with their grade passback target
+CREATE TABLE IF NOT EXISTS lti_launches (
+    study_session_id INTEGER PRIMARY KEY,
+    platform_id INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    lineitem_url TEXT NOT NULL DEFAULT '',
+    score_published_at TIMESTAMP,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (platform_id) REFERENCES lti_platforms(id) ON DELETE CASCADE
+);
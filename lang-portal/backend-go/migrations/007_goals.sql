@@ -0,0 +1,15 @@
+-- Daily and weekly study goals; goals without a learner belong to the portal's own user
+CREATE TABLE IF NOT EXISTS goals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    learner_id INTEGER,
+    metric TEXT NOT NULL CHECK (metric IN ('reviews', 'minutes', 'new_words')),
+    period TEXT NOT NULL CHECK (period IN ('daily', 'weekly')),
+    target INTEGER NOT NULL CHECK (target > 0),
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (learner_id) REFERENCES learners(id) ON DELETE CASCADE
+);
+
+-- One goal per learner, metric and period; NULL learners are distinct in a plain UNIQUE constraint
+CREATE UNIQUE INDEX IF NOT EXISTS idx_goals_learner_metric_period ON goals(COALESCE(learner_id, 0), metric, period);
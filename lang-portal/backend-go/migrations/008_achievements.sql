@@ -0,0 +1,8 @@
+-- Achievements earned, at the time their rule was first met
+CREATE TABLE IF NOT EXISTS achievement_awards (
+    achievement_id TEXT PRIMARY KEY,
+    awarded_at TIMESTAMP NOT NULL,
+    study_session_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id) ON DELETE SET NULL
+);
@@ -0,0 +1,3 @@
+-- Leaderboard privacy settings of learners
+ALTER TABLE learners ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE learners ADD COLUMN leaderboard_opt_out BOOLEAN NOT NULL DEFAULT 0;
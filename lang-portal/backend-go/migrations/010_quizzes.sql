@@ -0,0 +1,11 @@
+-- Generated quizzes that have been scored, so an answer key only records reviews once
+CREATE TABLE IF NOT EXISTS quiz_scores (
+    quiz_id TEXT PRIMARY KEY,
+    study_session_id INTEGER NOT NULL,
+    correct INTEGER NOT NULL,
+    total INTEGER NOT NULL,
+    scored_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_scores_session_id ON quiz_scores(study_session_id);
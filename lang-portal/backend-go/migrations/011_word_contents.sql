@@ -0,0 +1,13 @@
+-- Example sentences, mnemonics and grammar explanations of words, generated by a language model or written by teachers
+CREATE TABLE IF NOT EXISTS word_contents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('example_sentences', 'mnemonic', 'grammar')),
+    content TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('generated', 'teacher')),
+    model TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+    UNIQUE (word_id, kind)
+);
@@ -0,0 +1,20 @@
+-- Media files, stored on disk under their SHA-256 so identical files are kept once
+CREATE TABLE IF NOT EXISTS media_files (
+    hash TEXT PRIMARY KEY,
+    content_type TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Pronunciation audio of words, uploaded or generated by text-to-speech
+CREATE TABLE IF NOT EXISTS word_audio (
+    word_id INTEGER PRIMARY KEY,
+    hash TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('upload', 'tts')),
+    voice TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+    FOREIGN KEY (hash) REFERENCES media_files(hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_audio_hash ON word_audio(hash);
@@ -0,0 +1,27 @@
+-- Dimensions of stored images, and the smaller rendition shown in lists; images that
+-- already fit a thumbnail have none
+ALTER TABLE media_files ADD COLUMN width INTEGER;
+ALTER TABLE media_files ADD COLUMN height INTEGER;
+ALTER TABLE media_files ADD COLUMN thumbnail_hash TEXT REFERENCES media_files(hash);
+
+-- Mnemonic images of words
+CREATE TABLE IF NOT EXISTS word_images (
+    word_id INTEGER PRIMARY KEY,
+    hash TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+    FOREIGN KEY (hash) REFERENCES media_files(hash)
+);
+
+-- Uploaded thumbnails of study activities, which become their preview_url
+CREATE TABLE IF NOT EXISTS study_activity_images (
+    study_activity_id INTEGER PRIMARY KEY,
+    hash TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (study_activity_id) REFERENCES study_activities(id) ON DELETE CASCADE,
+    FOREIGN KEY (hash) REFERENCES media_files(hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_images_hash ON word_images(hash);
+CREATE INDEX IF NOT EXISTS idx_study_activity_images_hash ON study_activity_images(hash);
+CREATE INDEX IF NOT EXISTS idx_media_files_thumbnail_hash ON media_files(thumbnail_hash);
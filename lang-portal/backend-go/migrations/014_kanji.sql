@@ -0,0 +1,27 @@
+-- Kanji dictionary imported from KANJIDIC2; readings, meanings and radicals are JSON arrays
+CREATE TABLE IF NOT EXISTS kanji (
+    literal TEXT PRIMARY KEY,
+    meanings JSON NOT NULL DEFAULT '[]',
+    on_readings JSON NOT NULL DEFAULT '[]',
+    kun_readings JSON NOT NULL DEFAULT '[]',
+    stroke_count INTEGER NOT NULL,
+    radicals JSON NOT NULL DEFAULT '[]',
+    grade INTEGER,
+    jlpt INTEGER,
+    frequency INTEGER
+);
+
+-- Kanji used by each word, found in its parts; position is the index of the part,
+-- or NULL when the word has no usable parts and the kanji was found in the word itself.
+-- Kanji missing from the dictionary are linked too, so importing it later needs no relinking.
+CREATE TABLE IF NOT EXISTS word_kanji (
+    word_id INTEGER NOT NULL,
+    literal TEXT NOT NULL,
+    position INTEGER,
+    PRIMARY KEY (word_id, literal),
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_kanji_literal ON word_kanji(literal);
+CREATE INDEX IF NOT EXISTS idx_kanji_grade ON kanji(grade);
+CREATE INDEX IF NOT EXISTS idx_kanji_jlpt ON kanji(jlpt);
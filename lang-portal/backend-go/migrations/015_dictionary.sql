@@ -0,0 +1,27 @@
+-- Dictionary entries imported from JMdict; id is the JMdict entry sequence number and
+-- forms, readings and senses are JSON arrays
+CREATE TABLE IF NOT EXISTS dictionary_entries (
+    id INTEGER PRIMARY KEY,
+    kanji JSON NOT NULL DEFAULT '[]',
+    readings JSON NOT NULL DEFAULT '[]',
+    senses JSON NOT NULL DEFAULT '[]',
+    common BOOLEAN NOT NULL DEFAULT 0
+);
+
+-- Written forms and readings of the entries for lookups, with katakana folded into hiragana
+CREATE TABLE IF NOT EXISTS dictionary_forms (
+    text TEXT NOT NULL,
+    entry_id INTEGER NOT NULL,
+    PRIMARY KEY (text, entry_id),
+    FOREIGN KEY (entry_id) REFERENCES dictionary_entries(id) ON DELETE CASCADE
+) WITHOUT ROWID;
+
+-- Lowercased English glosses of the entries for lookups; sense is the index of the sense
+CREATE TABLE IF NOT EXISTS dictionary_glosses (
+    gloss TEXT NOT NULL,
+    entry_id INTEGER NOT NULL,
+    sense INTEGER NOT NULL,
+    FOREIGN KEY (entry_id) REFERENCES dictionary_entries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_dictionary_glosses_gloss ON dictionary_glosses(gloss);
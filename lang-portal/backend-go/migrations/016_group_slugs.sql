@@ -0,0 +1,10 @@
+-- Stable keys of the groups the portal refers to, such as the groups achievements count
+-- mastered words of; unlike names they do not change when a group is renamed
+ALTER TABLE groups ADD COLUMN slug TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_slug ON groups(slug);
+
+-- Databases seeded before slugs existed keep their core groups under the seeded names
+UPDATE groups SET slug = 'core-adjectives'
+WHERE id = (SELECT MIN(id) FROM groups WHERE name = 'Core Adjectives');
+UPDATE groups SET slug = 'core-verbs'
+WHERE id = (SELECT MIN(id) FROM groups WHERE name = 'Core Verbs');
@@ -0,0 +1,9 @@
+-- Readings of the dictionary entries in hiragana with long vowels folded, which romaji
+-- lookups are matched against; filled by import-jmdict, so dictionaries imported before
+-- this migration need importing again for romaji lookups
+CREATE TABLE IF NOT EXISTS dictionary_readings (
+    text TEXT NOT NULL,
+    entry_id INTEGER NOT NULL,
+    PRIMARY KEY (text, entry_id),
+    FOREIGN KEY (entry_id) REFERENCES dictionary_entries(id) ON DELETE CASCADE
+) WITHOUT ROWID;